@@ -0,0 +1,63 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// getConfigsConcurrency bounds the queries of a GetConfigs call in flight at the same time.
+const getConfigsConcurrency = 16
+
+func (client *ConfigClient) GetConfigs(params []vo.ConfigParam) []model.ConfigQueryResult {
+	return client.GetConfigsWithContext(context.Background(), params)
+}
+
+// GetConfigsWithContext gets many configs at once, the server has no batch query so they are queried
+// concurrently. Each config is read like GetConfig does, including the filters and the snapshot fallback,
+// and gets its own result at the same index as its param.
+func (client *ConfigClient) GetConfigsWithContext(ctx context.Context, params []vo.ConfigParam) []model.ConfigQueryResult {
+	results := make([]model.ConfigQueryResult, len(params))
+	sema := util.NewSemaphore(getConfigsConcurrency)
+	var wg sync.WaitGroup
+	for i := range params {
+		group := params[i].Group
+		if len(group) <= 0 {
+			group = constant.DEFAULT_GROUP
+		}
+		results[i] = model.ConfigQueryResult{DataId: params[i].DataId, Group: group}
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			continue
+		}
+		sema.Acquire()
+		wg.Add(1)
+		go func(result *model.ConfigQueryResult, param vo.ConfigParam) {
+			defer wg.Done()
+			defer sema.Release()
+			result.Content, result.Err = client.GetConfigWithContext(ctx, param)
+		}(&results[i], params[i])
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_response"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockConfigProxyForBatch struct {
+	MockConfigProxy
+	inFlight    int32
+	maxInFlight int32
+}
+
+func (m *MockConfigProxyForBatch) queryConfig(ctx context.Context, dataId, group, tenant, tag string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error) {
+	current := atomic.AddInt32(&m.inFlight, 1)
+	defer atomic.AddInt32(&m.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&m.maxInFlight)
+		if current <= max || atomic.CompareAndSwapInt32(&m.maxInFlight, max, current) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	if dataId == "batch-offline" {
+		return nil, errors.New("mock err for offline server")
+	}
+	return &rpc_response.ConfigQueryResponse{Content: "content of " + dataId, Response: &rpc_response.Response{Success: true}}, nil
+}
+
+func TestGetConfigs(t *testing.T) {
+	proxy := &MockConfigProxyForBatch{}
+	client := createConfigClientTest()
+	client.configProxy = proxy
+	clientConfig, _ := client.GetClientConfig()
+	cache.WriteConfigToFile(util.GetConfigCacheKey("batch-offline", "group", clientConfig.NamespaceId), client.configCacheDir, "snapshot")
+
+	params := []vo.ConfigParam{{DataId: ""}, {DataId: "batch-offline", Group: "group"}}
+	for i := 0; i < 40; i++ {
+		params = append(params, vo.ConfigParam{DataId: fmt.Sprintf("batch-%d", i), Group: "group"})
+	}
+	results := client.GetConfigs(params)
+	assert.Len(t, results, len(params))
+	assert.NotNil(t, results[0].Err)
+	assert.Nil(t, results[1].Err)
+	assert.Equal(t, "snapshot", results[1].Content)
+	for i, result := range results[2:] {
+		assert.Nil(t, result.Err)
+		assert.Equal(t, fmt.Sprintf("batch-%d", i), result.DataId)
+		assert.Equal(t, "content of "+result.DataId, result.Content)
+	}
+	assert.True(t, proxy.maxInFlight > 1)
+	assert.True(t, proxy.maxInFlight <= getConfigsConcurrency)
+}
+
+func TestGetConfigsWithContext_Cancelled(t *testing.T) {
+	client := createConfigClientTest()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := client.GetConfigsWithContext(ctx, []vo.ConfigParam{{DataId: "batch", Group: "group"}})
+	assert.Equal(t, context.Canceled, results[0].Err)
+	assert.Equal(t, "group", results[0].Group)
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+)
+
+func (client *ConfigClient) PublishBetaConfig(param vo.ConfigParam) (published bool, err error) {
+	return client.PublishBetaConfigWithContext(context.Background(), param)
+}
+
+// PublishBetaConfigWithContext publishes param.Content as the beta version, only served to the clients listed
+// in param.BetaIps or, on servers supporting gray rules, matching param.GrayRuleExp.
+func (client *ConfigClient) PublishBetaConfigWithContext(ctx context.Context, param vo.ConfigParam) (published bool, err error) {
+	if len(param.BetaIps) <= 0 && len(param.GrayName) <= 0 {
+		return false, errors.New("[client.PublishBetaConfig] param.betaIps or param.grayName must be set")
+	}
+	if len(param.GrayName) > 0 && len(param.GrayRuleExp) <= 0 {
+		return false, errors.New("[client.PublishBetaConfig] param.grayRuleExp can not be empty")
+	}
+	return client.PublishConfigWithContext(ctx, param)
+}
+
+func (client *ConfigClient) QueryBetaConfig(param vo.ConfigParam) (*model.ConfigBeta, error) {
+	return client.QueryBetaConfigWithContext(context.Background(), param)
+}
+
+// QueryBetaConfigWithContext returns the beta version of a config, nil if there is none.
+// The content is returned as stored, filters such as decryption are not applied.
+func (client *ConfigClient) QueryBetaConfigWithContext(ctx context.Context, param vo.ConfigParam) (*model.ConfigBeta, error) {
+	if len(param.DataId) <= 0 {
+		return nil, errors.New("[client.QueryBetaConfig] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		param.Group = constant.DEFAULT_GROUP
+	}
+	clientConfig, _ := client.GetClientConfig()
+	return client.configProxy.queryBetaConfigProxy(ctx, param.DataId, param.Group, clientConfig.NamespaceId)
+}
+
+func (client *ConfigClient) StopBetaConfig(param vo.ConfigParam) (stopped bool, err error) {
+	return client.StopBetaConfigWithContext(context.Background(), param)
+}
+
+// StopBetaConfigWithContext removes the beta version of a config, all clients get the formal version again.
+func (client *ConfigClient) StopBetaConfigWithContext(ctx context.Context, param vo.ConfigParam) (stopped bool, err error) {
+	if len(param.DataId) <= 0 {
+		return false, errors.New("[client.StopBetaConfig] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		param.Group = constant.DEFAULT_GROUP
+	}
+	clientConfig, _ := client.GetClientConfig()
+	return client.configProxy.stopBetaConfigProxy(ctx, param.DataId, param.Group, clientConfig.NamespaceId)
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_request"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_response"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockConfigProxyForBeta struct {
+	MockConfigProxy
+	queriedTag string
+	isBeta     bool
+	request    rpc_request.IRequest
+}
+
+func (m *MockConfigProxyForBeta) queryConfig(ctx context.Context, dataId, group, tenant, tag string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error) {
+	m.queriedTag = tag
+	if len(tag) > 0 {
+		return nil, errors.New("mock err for tagged config")
+	}
+	return &rpc_response.ConfigQueryResponse{Content: "gray=true", IsBeta: m.isBeta, Response: &rpc_response.Response{Success: true}}, nil
+}
+
+func (m *MockConfigProxyForBeta) requestProxy(ctx context.Context, rpcClient *rpc.RpcClient, request rpc_request.IRequest, timeoutMills uint64) (rpc_response.IResponse, error) {
+	m.request = request
+	return m.MockConfigProxy.requestProxy(ctx, rpcClient, request, timeoutMills)
+}
+
+func TestPublishBetaConfig(t *testing.T) {
+	proxy := &MockConfigProxyForBeta{}
+	client := createConfigClientTest()
+	client.configProxy = proxy
+
+	_, err := client.PublishBetaConfig(vo.ConfigParam{DataId: "beta", Group: "group", Content: "content"})
+	assert.NotNil(t, err)
+
+	published, err := client.PublishBetaConfig(vo.ConfigParam{DataId: "beta", Group: "group", Content: "content", BetaIps: "10.0.0.1,10.0.0.2"})
+	assert.Nil(t, err)
+	assert.True(t, published)
+	assert.Equal(t, "10.0.0.1,10.0.0.2", proxy.request.(*rpc_request.ConfigPublishRequest).AdditionMap["betaIps"])
+
+	published, err = client.PublishBetaConfig(vo.ConfigParam{DataId: "beta", Group: "group", Content: "content",
+		GrayName: "canary", GrayRuleExp: "10.0.0.*", GrayPriority: 1})
+	assert.Nil(t, err)
+	assert.True(t, published)
+	additionMap := proxy.request.(*rpc_request.ConfigPublishRequest).AdditionMap
+	assert.Equal(t, "canary", additionMap["grayName"])
+	assert.Equal(t, "10.0.0.*", additionMap["grayRuleExp"])
+	assert.Equal(t, "1", additionMap["grayPriority"])
+
+	beta, err := client.QueryBetaConfig(vo.ConfigParam{DataId: "beta", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "127.0.0.1", beta.BetaIps)
+	stopped, err := client.StopBetaConfig(vo.ConfigParam{DataId: "beta", Group: "group"})
+	assert.Nil(t, err)
+	assert.True(t, stopped)
+}
+
+func TestGetConfig_TaggedNotFallBackToSnapshot(t *testing.T) {
+	proxy := &MockConfigProxyForBeta{}
+	client := createConfigClientTest()
+	client.configProxy = proxy
+
+	_, err := client.GetConfig(vo.ConfigParam{DataId: localConfigTest.DataId, Group: localConfigTest.Group, Tag: "v2"})
+	assert.NotNil(t, err)
+	assert.Equal(t, "v2", proxy.queriedTag)
+
+	_, err = client.DeleteConfig(vo.ConfigParam{DataId: "beta", Group: "group", Tag: "v2"})
+	assert.Nil(t, err)
+	assert.Equal(t, "v2", proxy.request.(*rpc_request.ConfigRemoveRequest).Tag)
+}
+
+func TestListenConfig_GrayEvent(t *testing.T) {
+	client := createConfigClientTest()
+	client.configProxy = &MockConfigProxyForBeta{isBeta: true}
+	clientConfig, _ := client.GetClientConfig()
+	events := make(chan *model.ConfigChangeEvent, 1)
+	param := vo.ConfigParam{DataId: "gray-event.properties", Group: "group", OnChangeEvent: func(event *model.ConfigChangeEvent) {
+		events <- event
+	}}
+	assert.Nil(t, client.ListenConfig(param))
+
+	data, _ := client.cacheMap.Get(util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId))
+	client.refreshContentAndCheck(data.(cacheData), false)
+	event := receiveChangeEvent(t, events)
+	assert.True(t, event.IsGray)
+	assert.Equal(t, "gray=true", event.NewContent)
+}
+
+func newConfigProxyForHttpTest(t *testing.T, handler http.HandlerFunc) IConfigProxy {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	portNum, _ := strconv.ParseUint(port, 10, 64)
+	proxy, err := NewConfigProxy(context.Background(), []constant.ServerConfig{*constant.NewServerConfig(host, portNum)},
+		*constant.NewClientConfig(constant.WithTimeoutMs(3000)), &http_agent.HttpAgent{})
+	assert.Nil(t, err)
+	return proxy
+}
+
+func TestConfigProxy_BetaFallbackToV3(t *testing.T) {
+	var v1Called int
+	proxy := newConfigProxyForHttpTest(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/nacos/v1/cs/configs":
+			v1Called++
+			assert.Equal(t, "true", r.URL.Query().Get("beta"))
+			w.WriteHeader(http.StatusNotFound)
+		case "/nacos/v3/admin/cs/config/beta":
+			assert.Equal(t, "group", r.URL.Query().Get("groupName"))
+			if r.Method == http.MethodDelete {
+				_, _ = w.Write([]byte(`{"code":0,"message":"success","data":true}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"code":0,"message":"success","data":{"dataId":"beta","group":"group","content":"c","betaIps":"10.0.0.1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	beta, err := proxy.queryBetaConfigProxy(context.Background(), "beta", "group", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.1", beta.BetaIps)
+	assert.True(t, v1Called > 0)
+
+	stopped, err := proxy.stopBetaConfigProxy(context.Background(), "beta", "group", "")
+	assert.Nil(t, err)
+	assert.True(t, stopped)
+}
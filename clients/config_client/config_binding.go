@@ -0,0 +1,191 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/encoding"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+)
+
+// ConfigValidator can be implemented by a bound struct to reject values that decode but are not usable.
+type ConfigValidator interface {
+	Validate() error
+}
+
+// ConfigBinding keeps the last successfully decoded value of one config and reloads it on every change.
+// A push that fails to decode or validate is reported and dropped, Get keeps returning the last good value.
+type ConfigBinding[T any] struct {
+	client     IConfigClient
+	param      vo.ConfigParam
+	handle     *ConfigListenerHandle
+	configType string
+	value      atomic.Pointer[T]
+	lastErr    atomic.Pointer[error]
+	mux        sync.Mutex
+	validate   func(*T) error
+	onChange   func(old, new *T)
+	onError    func(error)
+}
+
+type BindOption[T any] func(*ConfigBinding[T])
+
+// WithBindValidator rejects decoded values for which validate returns an error.
+func WithBindValidator[T any](validate func(*T) error) BindOption[T] {
+	return func(binding *ConfigBinding[T]) {
+		binding.validate = validate
+	}
+}
+
+// WithBindChangeHandler is called after a new value has been swapped in.
+func WithBindChangeHandler[T any](onChange func(old, new *T)) BindOption[T] {
+	return func(binding *ConfigBinding[T]) {
+		binding.onChange = onChange
+	}
+}
+
+// WithBindErrorHandler is called when a pushed config is dropped because it can't be decoded or validated.
+func WithBindErrorHandler[T any](onError func(err error)) BindOption[T] {
+	return func(binding *ConfigBinding[T]) {
+		binding.onError = onError
+	}
+}
+
+// BindConfig loads the config described by param into a new T and keeps it up to date.
+// The decoder is picked by param.Type, or by the extension of param.DataId when Type is empty,
+// see encoding.RegisterConfigDecoder for the supported types.
+// param.OnChange is ignored, use WithBindChangeHandler instead.
+func BindConfig[T any](client IConfigClient, param vo.ConfigParam, opts ...BindOption[T]) (*ConfigBinding[T], error) {
+	if len(param.DataId) <= 0 {
+		return nil, errors.New("[client.BindConfig] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return nil, errors.New("[client.BindConfig] param.group can not be empty")
+	}
+	binding := &ConfigBinding[T]{
+		client:     client,
+		param:      param,
+		configType: encoding.ResolveConfigType(param.Type, param.DataId),
+	}
+	for _, opt := range opts {
+		opt(binding)
+	}
+	if _, err := encoding.GetConfigDecoder(binding.configType); err != nil {
+		return nil, err
+	}
+
+	// the initial value is delivered by ListenConfig, so no change slips in between reading and listening
+	var initial sync.Once
+	var initErr error
+	listenParam := param
+	listenParam.NotifyInitial = true
+	listenParam.OnChange = func(namespace, group, dataId, data string) {
+		err := binding.update(data)
+		isInitial := false
+		initial.Do(func() {
+			initErr, isInitial = err, true
+		})
+		if err != nil && !isInitial {
+			binding.reportError(err)
+		}
+	}
+	handle, err := client.AddConfigListener(listenParam)
+	if err != nil {
+		return nil, err
+	}
+	binding.handle = handle
+	// the config doesn't exist yet if there was no initial value
+	initial.Do(func() {
+		initErr = binding.update("")
+	})
+	if initErr != nil {
+		_ = client.RemoveConfigListener(handle)
+		return nil, initErr
+	}
+	return binding, nil
+}
+
+// Get returns the last good value, callers must not modify it.
+func (binding *ConfigBinding[T]) Get() *T {
+	return binding.value.Load()
+}
+
+// LastError returns the error of the latest update, nil if it was applied.
+func (binding *ConfigBinding[T]) LastError() error {
+	if err := binding.lastErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Close stops listening for changes, Get keeps returning the last good value.
+// Other listeners of the same config are not affected.
+func (binding *ConfigBinding[T]) Close() error {
+	return binding.client.RemoveConfigListener(binding.handle)
+}
+
+func (binding *ConfigBinding[T]) update(content string) error {
+	binding.mux.Lock()
+	defer binding.mux.Unlock()
+
+	newValue, err := binding.decode(content)
+	if err != nil {
+		err = errors.Wrapf(err, "bind config failed, dataId=%s, group=%s, type=%s",
+			binding.param.DataId, binding.param.Group, binding.configType)
+		binding.lastErr.Store(&err)
+		return err
+	}
+	binding.lastErr.Store(nil)
+	oldValue := binding.value.Swap(newValue)
+	if oldValue != nil && binding.onChange != nil {
+		binding.onChange(oldValue, newValue)
+	}
+	return nil
+}
+
+func (binding *ConfigBinding[T]) decode(content string) (*T, error) {
+	if len(strings.TrimSpace(content)) == 0 {
+		return nil, errors.New("config content is empty")
+	}
+	newValue := new(T)
+	if err := encoding.DecodeConfig(binding.configType, content, newValue); err != nil {
+		return nil, err
+	}
+	if validator, ok := interface{}(newValue).(ConfigValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if binding.validate != nil {
+		if err := binding.validate(newValue); err != nil {
+			return nil, err
+		}
+	}
+	return newValue, nil
+}
+
+func (binding *ConfigBinding[T]) reportError(err error) {
+	logger.Errorf("%v, keep the last good value", err)
+	if binding.onError != nil {
+		binding.onError(err)
+	}
+}
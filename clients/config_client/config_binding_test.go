@@ -0,0 +1,120 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_response"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockConfigProxyWithContent serves content for every config, or the content of its dataId when contents is set
+type MockConfigProxyWithContent struct {
+	MockConfigProxy
+	mux       sync.Mutex
+	content   string
+	contents  map[string]string
+	errorCode int
+}
+
+func (m *MockConfigProxyWithContent) queryConfig(ctx context.Context, dataId, group, tenant, tag string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	content := m.content
+	if m.contents != nil {
+		content = m.contents[dataId]
+	}
+	return &rpc_response.ConfigQueryResponse{Content: content, Response: &rpc_response.Response{Success: true, ErrorCode: m.errorCode}}, nil
+}
+
+type bindingTestConfig struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+}
+
+func (c *bindingTestConfig) Validate() error {
+	if c.Port <= 0 {
+		return errors.New("port must be positive")
+	}
+	return nil
+}
+
+func createConfigClientWithContent(content string) *ConfigClient {
+	client := createConfigClientTest()
+	client.configProxy = &MockConfigProxyWithContent{content: content}
+	return client
+}
+
+func pushConfig(t *testing.T, client *ConfigClient, param vo.ConfigParam, content string) {
+	clientConfig, _ := client.GetClientConfig()
+	data, ok := client.cacheMap.Get(util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId))
+	assert.True(t, ok)
+	for _, listener := range data.(cacheData).cacheDataListener.getListeners() {
+		listener.onChange(clientConfig.NamespaceId, param.Group, param.DataId, content)
+	}
+}
+
+func TestBindConfig(t *testing.T) {
+	param := vo.ConfigParam{DataId: "binding.json", Group: localConfigTest.Group}
+	client := createConfigClientWithContent(`{"name":"demo","port":8080}`)
+
+	var changed, failed int
+	binding, err := BindConfig[bindingTestConfig](client, param,
+		WithBindChangeHandler(func(old, new *bindingTestConfig) { changed++ }),
+		WithBindErrorHandler[bindingTestConfig](func(err error) { failed++ }))
+	assert.Nil(t, err)
+	assert.Equal(t, bindingTestConfig{Name: "demo", Port: 8080}, *binding.Get())
+
+	t.Run("GoodPush", func(t *testing.T) {
+		pushConfig(t, client, param, `{"name":"demo","port":9090}`)
+		assert.Equal(t, 9090, binding.Get().Port)
+		assert.Nil(t, binding.LastError())
+		assert.Equal(t, 1, changed)
+	})
+	t.Run("MalformedPushKeepsLastGoodValue", func(t *testing.T) {
+		pushConfig(t, client, param, `{"name":"demo",`)
+		assert.Equal(t, 9090, binding.Get().Port)
+		assert.NotNil(t, binding.LastError())
+		assert.Equal(t, 1, failed)
+	})
+	t.Run("InvalidPushKeepsLastGoodValue", func(t *testing.T) {
+		pushConfig(t, client, param, `{"name":"demo","port":0}`)
+		assert.Equal(t, 9090, binding.Get().Port)
+		assert.Equal(t, 2, failed)
+	})
+	assert.Nil(t, binding.Close())
+}
+
+func TestBindConfig_InitialDecodeFailed(t *testing.T) {
+	client := createConfigClientWithContent("port: [")
+	_, err := BindConfig[bindingTestConfig](client, vo.ConfigParam{DataId: "binding", Group: localConfigTest.Group, Type: "yaml"})
+	assert.NotNil(t, err)
+}
+
+func TestBindConfig_ConfigNotExist(t *testing.T) {
+	client := createConfigClientWithContent("")
+	_, err := BindConfig[bindingTestConfig](client, vo.ConfigParam{DataId: "binding.json", Group: localConfigTest.Group})
+	assert.NotNil(t, err)
+	// the listening is given up together with the binding
+	assert.True(t, client.cacheMap.IsEmpty())
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"sort"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/encoding"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+)
+
+func (cacheData *cacheData) buildChangeEvent(oldMd5, oldContent, newContent string) *model.ConfigChangeEvent {
+	event := cacheData.newChangeEvent(oldMd5, oldContent, newContent)
+	event.ChangedItems = diffConfigContent(diffConfigType(event.ContentType, event.DataId), oldContent, newContent)
+	return event
+}
+
+// newChangeEvent builds the event without the per-key diff, which is only worth parsing for event listeners.
+func (cacheData *cacheData) newChangeEvent(oldMd5, oldContent, newContent string) *model.ConfigChangeEvent {
+	event := &model.ConfigChangeEvent{
+		Namespace:   cacheData.tenant,
+		Group:       cacheData.group,
+		DataId:      cacheData.dataId,
+		OldContent:  oldContent,
+		NewContent:  newContent,
+		OldMd5:      oldMd5,
+		NewMd5:      cacheData.md5,
+		ContentType: cacheData.contentType,
+		ChangeType:  model.ConfigModified,
+		IsGray:      cacheData.isGray,
+	}
+	if cacheData.isDeleted {
+		event.ChangeType = model.ConfigDeleted
+	} else if len(oldMd5) == 0 {
+		event.ChangeType = model.ConfigAdded
+	}
+	return event
+}
+
+// diffConfigType prefers the type reported by the server, "text" is its default so the dataId extension is tried then.
+func diffConfigType(contentType, dataId string) string {
+	configType := encoding.NormalizeConfigType(contentType)
+	if len(configType) == 0 || configType == encoding.ConfigTypeText {
+		configType = encoding.ResolveConfigType("", dataId)
+	}
+	return configType
+}
+
+// diffConfigContent returns the per-key changes between two versions of a properties, yaml or json config,
+// nil for other types or if either version can't be parsed.
+func diffConfigContent(configType, oldContent, newContent string) []model.ConfigChangeItem {
+	switch configType {
+	case encoding.ConfigTypeProperties, encoding.ConfigTypeYaml, encoding.ConfigTypeJson:
+	default:
+		return nil
+	}
+	oldItems, err := encoding.FlattenConfig(configType, oldContent)
+	if err != nil {
+		logger.Debugf("parse old %s content for diff failed, err:%v", configType, err)
+		return nil
+	}
+	newItems, err := encoding.FlattenConfig(configType, newContent)
+	if err != nil {
+		logger.Debugf("parse new %s content for diff failed, err:%v", configType, err)
+		return nil
+	}
+	return diffConfigItems(oldItems, newItems)
+}
+
+// diffConfigItems returns the changes between two flattened configs, sorted by key.
+func diffConfigItems(oldItems, newItems map[string]string) []model.ConfigChangeItem {
+	var changes []model.ConfigChangeItem
+	for key, oldValue := range oldItems {
+		newValue, ok := newItems[key]
+		if !ok {
+			changes = append(changes, model.ConfigChangeItem{Key: key, OldValue: oldValue, Type: model.ConfigDeleted})
+		} else if newValue != oldValue {
+			changes = append(changes, model.ConfigChangeItem{Key: key, OldValue: oldValue, NewValue: newValue, Type: model.ConfigModified})
+		}
+	}
+	for key, newValue := range newItems {
+		if _, ok := oldItems[key]; !ok {
+			changes = append(changes, model.ConfigChangeItem{Key: key, NewValue: newValue, Type: model.ConfigAdded})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
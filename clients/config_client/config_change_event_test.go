@@ -0,0 +1,92 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenConfig_ChangeEvent(t *testing.T) {
+	proxy := &MockConfigProxyWithContent{}
+	client := createConfigClientTest()
+	client.configProxy = proxy
+	clientConfig, _ := client.GetClientConfig()
+	param := vo.ConfigParam{DataId: "change-event.properties", Group: localConfigTest.Group}
+	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId)
+
+	events := make(chan *model.ConfigChangeEvent, 1)
+	param.OnChangeEvent = func(event *model.ConfigChangeEvent) {
+		events <- event
+	}
+	assert.Nil(t, client.ListenConfig(param))
+
+	refresh := func(content string, errorCode int) *model.ConfigChangeEvent {
+		proxy.mux.Lock()
+		proxy.content, proxy.errorCode = content, errorCode
+		proxy.mux.Unlock()
+		data, _ := client.cacheMap.Get(key)
+		client.refreshContentAndCheck(data.(cacheData), false)
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("no change event received")
+			return nil
+		}
+	}
+
+	event := refresh("a=1\nb=2", 0)
+	assert.Equal(t, model.ConfigAdded, event.ChangeType)
+	assert.Equal(t, "", event.OldContent)
+	assert.Equal(t, util.Md5("a=1\nb=2"), event.NewMd5)
+	assert.Equal(t, []model.ConfigChangeItem{
+		{Key: "a", NewValue: "1", Type: model.ConfigAdded},
+		{Key: "b", NewValue: "2", Type: model.ConfigAdded},
+	}, event.ChangedItems)
+
+	event = refresh("a=1\nb=3\nc=4", 0)
+	assert.Equal(t, model.ConfigModified, event.ChangeType)
+	assert.Equal(t, "a=1\nb=2", event.OldContent)
+	assert.Equal(t, util.Md5("a=1\nb=2"), event.OldMd5)
+	assert.Equal(t, []model.ConfigChangeItem{
+		{Key: "b", OldValue: "2", NewValue: "3", Type: model.ConfigModified},
+		{Key: "c", NewValue: "4", Type: model.ConfigAdded},
+	}, event.ChangedItems)
+
+	event = refresh("", 300)
+	assert.Equal(t, model.ConfigDeleted, event.ChangeType)
+	assert.Equal(t, "", event.NewMd5)
+	assert.Len(t, event.ChangedItems, 3)
+	assert.Nil(t, client.CancelListenConfig(param))
+}
+
+func TestDiffConfigContent(t *testing.T) {
+	changes := diffConfigContent("yaml", "server:\n  port: 80\n  hosts: [a, b]", "server:\n  port: 81\n  hosts: [a]")
+	assert.Equal(t, []model.ConfigChangeItem{
+		{Key: "server.hosts[1]", OldValue: "b", Type: model.ConfigDeleted},
+		{Key: "server.port", OldValue: "80", NewValue: "81", Type: model.ConfigModified},
+	}, changes)
+
+	assert.Nil(t, diffConfigContent("json", `{"a":1}`, `{"a":`))
+	assert.Nil(t, diffConfigContent("text", "a", "b"))
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"github.com/nacos-group/nacos-sdk-go/v2/common/security"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -45,6 +46,8 @@ import (
 const (
 	perTaskConfigSize = 3000
 	executorErrDelay  = 5 * time.Second
+	// searchConfigAllPageSize is the page size of SearchConfigAll if none is given
+	searchConfigAllPageSize = 100
 )
 
 type ConfigClient struct {
@@ -61,6 +64,12 @@ type ConfigClient struct {
 	uid                      string
 	listenExecute            chan struct{}
 	isClosed                 bool
+	fuzzyWatchers            cache.ConcurrentMap
+	fuzzyWatchOnce           sync.Once
+	fuzzyWatchExecute        chan struct{}
+	listenerDispatcher       *listenerDispatcher
+	rateLimiter              *rateLimiter
+	unlistenCaches           cache.ConcurrentMap
 }
 
 type cacheData struct {
@@ -77,30 +86,59 @@ type cacheData struct {
 	taskId            int
 	configClient      *ConfigClient
 	isSyncWithServer  bool
+	isDeleted         bool
+	isGray            bool
 }
 
+// cacheDataListener is the listener registry of a config, lastMd5 and lastContent are those of the content
+// handed to the registry last, each listener keeps track of what it has seen itself.
 type cacheDataListener struct {
-	listener vo.Listener
-	lastMd5  string
+	lastMd5     string
+	lastContent string
+	mux         sync.Mutex
+	listeners   map[*configListener]struct{}
 }
 
 func (cacheData *cacheData) executeListener() {
+	oldMd5 := cacheData.cacheDataListener.lastMd5
 	cacheData.cacheDataListener.lastMd5 = cacheData.md5
-	cacheData.configClient.cacheMap.Set(util.GetConfigCacheKey(cacheData.dataId, cacheData.group, cacheData.tenant), *cacheData)
+	cacheKey := util.GetConfigCacheKey(cacheData.dataId, cacheData.group, cacheData.tenant)
+	cacheData.configClient.cacheMap.Set(cacheKey, *cacheData)
 
+	decryptedContent, err := cacheData.decryptContent(cacheData.content, cacheData.encryptedDataKey)
+	if err != nil {
+		logger.Errorf("do filters failed ,dataId=%s,group=%s,tenant=%s,err:%+v ", cacheData.dataId,
+			cacheData.group, cacheData.tenant, err)
+		return
+	}
+	client := cacheData.configClient
+	listener := cacheData.cacheDataListener
+	if client.hasFuzzyWatchers() {
+		event := cacheData.buildChangeEvent(oldMd5, listener.lastContent, decryptedContent)
+		client.notifyFuzzyWatchers(event, listener.isFuzzyOnly())
+	}
+	listener.lastContent = decryptedContent
+	cacheData.notifyListeners(cacheKey, decryptedContent)
+}
+
+// isFuzzyOnly reports whether the cache entry only exists because it matches a fuzzy watch pattern.
+func (registry *cacheDataListener) isFuzzyOnly() bool {
+	return !registry.hasListeners()
+}
+
+func (cacheData *cacheData) decryptContent(content, encryptedDataKey string) (string, error) {
 	param := &vo.ConfigParam{
 		DataId:           cacheData.dataId,
-		Content:          cacheData.content,
-		EncryptedDataKey: cacheData.encryptedDataKey,
+		Group:            cacheData.group,
+		Type:             cacheData.contentType,
+		Content:          content,
+		EncryptedDataKey: encryptedDataKey,
 		UsageType:        vo.ResponseType,
 	}
 	if err := cacheData.configClient.configFilterChainManager.DoFilters(param); err != nil {
-		logger.Errorf("do filters failed ,dataId=%s,group=%s,tenant=%s,err:%+v ", cacheData.dataId,
-			cacheData.group, cacheData.tenant, err)
-		return
+		return "", err
 	}
-	decryptedContent := param.Content
-	go cacheData.cacheDataListener.listener(cacheData.tenant, cacheData.group, cacheData.dataId, decryptedContent)
+	return param.Content, nil
 }
 
 func NewConfigClientWithRamCredentialProvider(nc nacos_client.INacosClient, provider security.RamCredentialProvider) (*ConfigClient, error) {
@@ -132,7 +170,7 @@ func NewConfigClientWithRamCredentialProvider(nc nacos_client.INacosClient, prov
 
 	config.configFilterChainManager = filter.NewConfigFilterChainManager()
 
-	if clientConfig.OpenKMS {
+	if clientConfig.OpenKMS || clientConfig.LocalKMSConfig != nil {
 		kmsEncryptionHandler := nacos_inner_encryption.NewKmsHandler()
 		nacos_inner_encryption.RegisterConfigEncryptionKmsPlugins(kmsEncryptionHandler, clientConfig)
 		encryptionFilter := filter.NewDefaultConfigEncryptionFilter(kmsEncryptionHandler)
@@ -150,6 +188,12 @@ func NewConfigClientWithRamCredentialProvider(nc nacos_client.INacosClient, prov
 	config.uid = uid.String()
 	config.cacheMap = cache.NewConcurrentMap()
 	config.listenExecute = make(chan struct{})
+	config.fuzzyWatchers = cache.NewConcurrentMap()
+	config.fuzzyWatchExecute = make(chan struct{})
+	config.unlistenCaches = cache.NewConcurrentMap()
+	config.rateLimiter = newRateLimiter(clientConfig.RateLimit)
+	config.listenerDispatcher = newListenerDispatcher(config.ctx, clientConfig.ListenerWorkerNum,
+		clientConfig.ListenerQueueSize, clientConfig.ListenerCoalesce)
 	config.startInternal()
 	return config, err
 }
@@ -163,7 +207,11 @@ func initLogger(clientConfig constant.ClientConfig) error {
 }
 
 func (client *ConfigClient) GetConfig(param vo.ConfigParam) (content string, err error) {
-	content, encryptedDataKey, err := client.getConfigInner(param)
+	return client.GetConfigWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) GetConfigWithContext(ctx context.Context, param vo.ConfigParam) (content string, err error) {
+	content, encryptedDataKey, err := client.getConfigInner(ctx, param)
 	if err != nil {
 		return "", err
 	}
@@ -178,10 +226,40 @@ func (client *ConfigClient) GetConfig(param vo.ConfigParam) (content string, err
 	return content, nil
 }
 
-func (client *ConfigClient) getConfigInner(param vo.ConfigParam) (content, encryptedDataKey string, err error) {
+func (client *ConfigClient) GetConfigDetail(param vo.ConfigParam) (*model.ConfigDetail, error) {
+	return client.GetConfigDetailWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) GetConfigDetailWithContext(ctx context.Context, param vo.ConfigParam) (*model.ConfigDetail, error) {
+	detail, encryptedDataKey, err := client.getConfigDetailInner(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+	deepCopyParam := param.DeepCopy()
+	deepCopyParam.EncryptedDataKey = encryptedDataKey
+	deepCopyParam.Content = detail.Content
+	deepCopyParam.UsageType = vo.ResponseType
+	if err = client.configFilterChainManager.DoFilters(deepCopyParam); err != nil {
+		return nil, err
+	}
+	detail.Content = deepCopyParam.Content
+	return detail, nil
+}
+
+func (client *ConfigClient) getConfigInner(ctx context.Context, param vo.ConfigParam) (content, encryptedDataKey string, err error) {
+	detail, encryptedDataKey, err := client.getConfigDetailInner(ctx, param)
+	if detail != nil {
+		content = detail.Content
+	}
+	return content, encryptedDataKey, err
+}
+
+// getConfigDetailInner reads the config from the failover file, the server or the snapshot, in that order. The
+// content of the returned detail isn't decrypted yet.
+func (client *ConfigClient) getConfigDetailInner(ctx context.Context, param vo.ConfigParam) (detail *model.ConfigDetail, encryptedDataKey string, err error) {
 	if len(param.DataId) <= 0 {
 		err = errors.New("[client.GetConfig] param.dataId can not be empty")
-		return "", "", err
+		return nil, "", err
 	}
 	if len(param.Group) <= 0 {
 		param.Group = constant.DEFAULT_GROUP
@@ -189,49 +267,90 @@ func (client *ConfigClient) getConfigInner(param vo.ConfigParam) (content, encry
 
 	clientConfig, _ := client.GetClientConfig()
 	cacheKey := util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId)
-	content = cache.GetFailover(cacheKey, client.configCacheDir)
+	localDetail := func(content, encryptedDataKey string, source model.ConfigSource) *model.ConfigDetail {
+		detail := &model.ConfigDetail{
+			DataId:    param.DataId,
+			Group:     param.Group,
+			Namespace: clientConfig.NamespaceId,
+			Content:   content,
+			Encrypted: len(encryptedDataKey) > 0,
+			Source:    source,
+		}
+		if len(content) > 0 {
+			detail.Md5 = util.Md5(content)
+		}
+		return detail
+	}
+	// failover and snapshot files hold the untagged content, they can't stand in for a tagged one
+	isTagged := len(param.Tag) > 0
+	var content string
+	if !isTagged {
+		content = cache.GetFailover(cacheKey, client.configCacheDir)
+	}
 	if len(content) > 0 {
 		logger.Warnf("%s %s %s is using failover content!", clientConfig.NamespaceId, param.Group, param.DataId)
 		encryptedDataKey = cache.GetFailoverEncryptedDataKey(cacheKey, client.configCacheDir)
-		return content, encryptedDataKey, nil
+		return localDetail(content, encryptedDataKey, model.ConfigSourceFailover), encryptedDataKey, nil
 	}
-	response, err := client.configProxy.queryConfig(param.DataId, param.Group, clientConfig.NamespaceId,
+	response, err := client.configProxy.queryConfig(ctx, param.DataId, param.Group, clientConfig.NamespaceId, param.Tag,
 		clientConfig.TimeoutMs, false, client)
 	if err != nil {
+		// the caller gave up, falling back to the snapshot would hide that from it
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
 		logger.Errorf("get config from server error:%v, dataId=%s, group=%s, namespaceId=%s", err,
 			param.DataId, param.Group, clientConfig.NamespaceId)
 
-		if clientConfig.DisableUseSnapShot {
-			return "", "", errors.Errorf("get config from remote nacos server fail, and is not allowed to read local file, err:%v", err)
+		if clientConfig.DisableUseSnapShot || isTagged {
+			return nil, "", errors.Errorf("get config from remote nacos server fail, and is not allowed to read local file, err:%v", err)
 		}
 
 		cacheContent, cacheErr := cache.ReadConfigFromFile(cacheKey, client.configCacheDir)
 		if cacheErr != nil {
-			return "", "", errors.Errorf("read config from both server and cache fail, err=%v，dataId=%s, group=%s, namespaceId=%s",
+			return nil, "", errors.Errorf("read config from both server and cache fail, err=%v，dataId=%s, group=%s, namespaceId=%s",
 				cacheErr, param.DataId, param.Group, clientConfig.NamespaceId)
 		}
 
 		if !strings.HasPrefix(param.DataId, nacos_inner_encryption.CipherPrefix) {
-			return cacheContent, "", nil
+			return localDetail(cacheContent, "", model.ConfigSourceSnapshot), "", nil
 		}
 		encryptedDataKey, cacheErr = cache.ReadEncryptedDataKeyFromFile(cacheKey, client.configCacheDir)
 		if cacheErr != nil {
-			return "", "", errors.Errorf("read encryptedDataKey from server and cache fail, err=%v，dataId=%s, group=%s, namespaceId=%s",
+			return nil, "", errors.Errorf("read encryptedDataKey from server and cache fail, err=%v，dataId=%s, group=%s, namespaceId=%s",
 				cacheErr, param.DataId, param.Group, clientConfig.NamespaceId)
 		}
 
 		logger.Warnf("read config from cache success, dataId=%s, group=%s, namespaceId=%s", param.DataId, param.Group, clientConfig.NamespaceId)
-		return cacheContent, encryptedDataKey, nil
-	}
-	if response != nil && response.Response != nil && !response.IsSuccess() {
-		return response.Content, response.EncryptedDataKey, errors.New(response.GetMessage())
-	}
-	encryptedDataKey = response.EncryptedDataKey
-	content = response.Content
-	return content, encryptedDataKey, nil
+		return localDetail(cacheContent, encryptedDataKey, model.ConfigSourceSnapshot), encryptedDataKey, nil
+	}
+	detail = &model.ConfigDetail{
+		DataId:       param.DataId,
+		Group:        param.Group,
+		Namespace:    clientConfig.NamespaceId,
+		Content:      response.Content,
+		ContentType:  response.ContentType,
+		Md5:          response.Md5,
+		LastModified: response.LastModified,
+		Tag:          response.Tag,
+		IsBeta:       response.IsBeta,
+		Encrypted:    len(response.EncryptedDataKey) > 0,
+		Source:       model.ConfigSourceServer,
+	}
+	if len(detail.Md5) == 0 && len(detail.Content) > 0 {
+		detail.Md5 = util.Md5(detail.Content)
+	}
+	if response.Response != nil && !response.IsSuccess() {
+		return detail, response.EncryptedDataKey, errors.New(response.GetMessage())
+	}
+	return detail, response.EncryptedDataKey, nil
 }
 
 func (client *ConfigClient) PublishConfig(param vo.ConfigParam) (published bool, err error) {
+	return client.PublishConfigWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) PublishConfigWithContext(ctx context.Context, param vo.ConfigParam) (published bool, err error) {
 	if len(param.DataId) <= 0 {
 		err = errors.New("[client.PublishConfig] param.dataId can not be empty")
 		return
@@ -255,12 +374,19 @@ func (client *ConfigClient) PublishConfig(param vo.ConfigParam) (published bool,
 	request.AdditionMap["tag"] = param.Tag
 	request.AdditionMap["config_tags"] = param.ConfigTags
 	request.AdditionMap["appName"] = param.AppName
+	request.AdditionMap["desc"] = param.Desc
 	request.AdditionMap["betaIps"] = param.BetaIps
 	request.AdditionMap["type"] = param.Type
 	request.AdditionMap["src_user"] = param.SrcUser
 	request.AdditionMap["encryptedDataKey"] = param.EncryptedDataKey
+	if len(param.GrayName) > 0 {
+		request.AdditionMap["grayName"] = param.GrayName
+		request.AdditionMap["grayRuleExp"] = param.GrayRuleExp
+		request.AdditionMap["grayVersion"] = param.GrayVersion
+		request.AdditionMap["grayPriority"] = strconv.Itoa(param.GrayPriority)
+	}
 	rpcClient := client.configProxy.getRpcClient(client)
-	response, err := client.configProxy.requestProxy(rpcClient, request, constant.DEFAULT_TIMEOUT_MILLS)
+	response, err := client.configProxy.requestProxy(ctx, rpcClient, request, constant.DEFAULT_TIMEOUT_MILLS)
 	if err != nil {
 		return false, err
 	}
@@ -271,6 +397,10 @@ func (client *ConfigClient) PublishConfig(param vo.ConfigParam) (published bool,
 }
 
 func (client *ConfigClient) DeleteConfig(param vo.ConfigParam) (deleted bool, err error) {
+	return client.DeleteConfigWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) DeleteConfigWithContext(ctx context.Context, param vo.ConfigParam) (deleted bool, err error) {
 	if len(param.DataId) <= 0 {
 		err = errors.New("[client.DeleteConfig] param.dataId can not be empty")
 	}
@@ -282,8 +412,9 @@ func (client *ConfigClient) DeleteConfig(param vo.ConfigParam) (deleted bool, er
 	}
 	clientConfig, _ := client.GetClientConfig()
 	request := rpc_request.NewConfigRemoveRequest(param.Group, param.DataId, clientConfig.NamespaceId)
+	request.Tag = param.Tag
 	rpcClient := client.configProxy.getRpcClient(client)
-	response, err := client.configProxy.requestProxy(rpcClient, request, constant.DEFAULT_TIMEOUT_MILLS)
+	response, err := client.configProxy.requestProxy(ctx, rpcClient, request, constant.DEFAULT_TIMEOUT_MILLS)
 	if err != nil {
 		return false, err
 	}
@@ -293,75 +424,105 @@ func (client *ConfigClient) DeleteConfig(param vo.ConfigParam) (deleted bool, er
 	return false, err
 }
 
-// Cancel Listen Config
+// CancelListenConfig removes the listener added with param.ListenerId, or the only listener added without one.
+// The config stops being listened once its last listener is gone.
 func (client *ConfigClient) CancelListenConfig(param vo.ConfigParam) (err error) {
 	clientConfig, err := client.GetClientConfig()
 	if err != nil {
 		logger.Errorf("[checkConfigInfo.GetClientConfig] failed,err:%+v", err)
 		return
 	}
-	client.cacheMap.Remove(util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId))
+	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId)
+	if v, ok := client.cacheMap.Get(key); ok {
+		if err = v.(cacheData).cacheDataListener.cancelListener(param.ListenerId); err != nil {
+			return errors.Wrapf(err, "[client.CancelListenConfig] dataId=%s, group=%s", param.DataId, param.Group)
+		}
+		client.removeCacheDataIfUnused(key)
+	}
 	logger.Infof("Cancel listen config DataId:%s Group:%s", param.DataId, param.Group)
 	return err
 }
 
 func (client *ConfigClient) ListenConfig(param vo.ConfigParam) (err error) {
-	if len(param.DataId) <= 0 {
-		err = errors.New("[client.ListenConfig] DataId can not be empty")
-		return err
-	}
-	if len(param.Group) <= 0 {
-		err = errors.New("[client.ListenConfig] Group can not be empty")
-		return err
-	}
-	clientConfig, err := client.GetClientConfig()
+	return client.ListenConfigWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) ListenConfigWithContext(ctx context.Context, param vo.ConfigParam) (err error) {
+	_, err = client.AddConfigListenerWithContext(ctx, param)
+	return err
+}
+
+// newCacheData builds the entry of a config listened for the first time, seeded with its snapshot.
+func (client *ConfigClient) newCacheData(key, dataId, group, tenant string) cacheData {
+	content, err := cache.ReadConfigFromFile(key, client.configCacheDir)
 	if err != nil {
-		err = errors.New("[checkConfigInfo.GetClientConfig] failed")
-		return err
+		logger.Warn(err)
 	}
+	encryptedDataKey, _ := cache.ReadEncryptedDataKeyFromFile(key, client.configCacheDir)
+	var md5Str string
+	if len(content) > 0 {
+		md5Str = util.Md5(content)
+	}
+	return cacheData{
+		isInitializing:    true,
+		dataId:            dataId,
+		group:             group,
+		tenant:            tenant,
+		content:           content,
+		md5:               md5Str,
+		cacheDataListener: &cacheDataListener{lastMd5: md5Str},
+		encryptedDataKey:  encryptedDataKey,
+		taskId:            client.cacheMap.Count() / perTaskConfigSize,
+		configClient:      client,
+	}
+}
 
-	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId)
-	var cData cacheData
-	if v, ok := client.cacheMap.Get(key); ok {
-		cData = v.(cacheData)
-		cData.isInitializing = true
-	} else {
-		var (
-			content  string
-			md5Str   string
-			innerErr error
-		)
-		if content, innerErr = cache.ReadConfigFromFile(key, client.configCacheDir); innerErr != nil {
-			logger.Warn(innerErr)
-		}
-		encryptedDataKey, _ := cache.ReadEncryptedDataKeyFromFile(key, client.configCacheDir)
-		if len(content) > 0 {
-			md5Str = util.Md5(content)
+func (client *ConfigClient) SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage, error) {
+	return client.searchConfigInner(context.Background(), param)
+}
+
+func (client *ConfigClient) SearchConfigWithContext(ctx context.Context, param vo.SearchConfigParam) (*model.ConfigPage, error) {
+	return client.searchConfigInner(ctx, param)
+}
+
+// SearchConfigAll is SearchConfig walking through all the pages, see SearchConfigAllWithContext.
+func (client *ConfigClient) SearchConfigAll(param vo.SearchConfigParam) func(yield func(model.ConfigItem, error) bool) {
+	return client.SearchConfigAllWithContext(context.Background(), param)
+}
+
+// SearchConfigAllWithContext returns an iterator of iter.Seq2[model.ConfigItem, error] shape over the configs
+// matching param. The pages are fetched one by one as the iteration proceeds, starting from param.PageNo, and the
+// iteration stops after param.MaxItems configs. A failed page is yielded as an error and ends the iteration.
+func (client *ConfigClient) SearchConfigAllWithContext(ctx context.Context, param vo.SearchConfigParam) func(yield func(model.ConfigItem, error) bool) {
+	return func(yield func(model.ConfigItem, error) bool) {
+		pageParam := param
+		if pageParam.PageNo <= 0 {
+			pageParam.PageNo = 1
 		}
-		listener := &cacheDataListener{
-			listener: param.OnChange,
-			lastMd5:  md5Str,
+		if pageParam.PageSize <= 0 {
+			pageParam.PageSize = searchConfigAllPageSize
 		}
-
-		cData = cacheData{
-			isInitializing:    true,
-			dataId:            param.DataId,
-			group:             param.Group,
-			tenant:            clientConfig.NamespaceId,
-			content:           content,
-			md5:               md5Str,
-			cacheDataListener: listener,
-			encryptedDataKey:  encryptedDataKey,
-			taskId:            client.cacheMap.Count() / perTaskConfigSize,
-			configClient:      client,
+		count := 0
+		for ; param.MaxItems <= 0 || count < param.MaxItems; pageParam.PageNo++ {
+			page, err := client.searchConfigInner(ctx, pageParam)
+			if err != nil {
+				yield(model.ConfigItem{}, err)
+				return
+			}
+			for _, item := range page.PageItems {
+				if param.MaxItems > 0 && count >= param.MaxItems {
+					return
+				}
+				count++
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(page.PageItems) == 0 || pageParam.PageNo >= page.PagesAvailable {
+				return
+			}
 		}
 	}
-	client.cacheMap.Set(key, cData)
-	return
-}
-
-func (client *ConfigClient) SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage, error) {
-	return client.searchConfigInner(param)
 }
 
 func (client *ConfigClient) CloseClient() {
@@ -376,7 +537,7 @@ func (client *ConfigClient) CloseClient() {
 	client.isClosed = true
 }
 
-func (client *ConfigClient) searchConfigInner(param vo.SearchConfigParam) (*model.ConfigPage, error) {
+func (client *ConfigClient) searchConfigInner(ctx context.Context, param vo.SearchConfigParam) (*model.ConfigPage, error) {
 	if param.Search != "accurate" && param.Search != "blur" {
 		return nil, errors.New("[client.searchConfigInner] param.search must be accurate or blur")
 	}
@@ -387,7 +548,7 @@ func (client *ConfigClient) searchConfigInner(param vo.SearchConfigParam) (*mode
 		param.PageSize = 10
 	}
 	clientConfig, _ := client.GetClientConfig()
-	configItems, err := client.configProxy.searchConfigProxy(param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	configItems, err := client.configProxy.searchConfigProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
 	if err != nil {
 		logger.Errorf("search config from server error:%+v ", err)
 		if _, ok := err.(*nacos_error.NacosError); ok {
@@ -428,6 +589,7 @@ func (client *ConfigClient) executeConfigListen() {
 		hasChangedKeys = false
 	)
 
+	client.executeConfigUnlisten()
 	listenTaskMap := client.buildListenTask(needAllSync)
 	if len(listenTaskMap) == 0 {
 		return
@@ -436,7 +598,7 @@ func (client *ConfigClient) executeConfigListen() {
 	for taskId, caches := range listenTaskMap {
 		request := buildConfigBatchListenRequest(caches)
 		rpcClient := client.configProxy.createRpcClient(client.ctx, fmt.Sprintf("%d", taskId), client)
-		iResponse, err := client.configProxy.requestProxy(rpcClient, request, 3000)
+		iResponse, err := client.configProxy.requestProxy(client.ctx, rpcClient, request, 3000)
 		if err != nil {
 			logger.Warnf("ConfigBatchListenRequest failure, err:%v", err)
 			continue
@@ -500,7 +662,7 @@ func buildConfigBatchListenRequest(caches []cacheData) *rpc_request.ConfigBatchL
 }
 
 func (client *ConfigClient) refreshContentAndCheck(cacheData cacheData, notify bool) {
-	configQueryResponse, err := client.configProxy.queryConfig(cacheData.dataId, cacheData.group, cacheData.tenant,
+	configQueryResponse, err := client.configProxy.queryConfig(client.ctx, cacheData.dataId, cacheData.group, cacheData.tenant, "",
 		constant.DEFAULT_TIMEOUT_MILLS, notify, client)
 	if err != nil {
 		logger.Errorf("refresh content and check md5 fail ,dataId=%s,group=%s,tenant=%s ", cacheData.dataId,
@@ -514,6 +676,8 @@ func (client *ConfigClient) refreshContentAndCheck(cacheData cacheData, notify b
 	}
 	cacheData.content = configQueryResponse.Content
 	cacheData.contentType = configQueryResponse.ContentType
+	cacheData.isDeleted = configQueryResponse.GetErrorCode() == 300
+	cacheData.isGray = configQueryResponse.IsBeta || len(configQueryResponse.Tag) > 0
 	cacheData.encryptedDataKey = configQueryResponse.EncryptedDataKey
 	if notify {
 		logger.Infof("[config_rpc_client] [data-received] dataId=%s, group=%s, tenant=%s, md5=%s, content=%s, type=%s",
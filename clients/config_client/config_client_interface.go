@@ -17,6 +17,9 @@
 package config_client
 
 import (
+	"context"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/filter"
 	"github.com/nacos-group/nacos-sdk-go/v2/model"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
 )
@@ -27,9 +30,24 @@ type IConfigClient interface {
 	// GetConfig use to get config from nacos server
 	// dataId  require
 	// group   require
+	// tag     optional, reads the tagged variant
 	// tenant ==>nacos.namespace optional
 	GetConfig(param vo.ConfigParam) (string, error)
 
+	// GetConfigDetail use to get config with its type, md5, last modified time, tag and beta flag, read like GetConfig
+	// the source of the detail tells whether it came from the server, the failover file or the snapshot
+	// dataId  require
+	// group   require
+	// tag     optional, reads the tagged variant
+	// tenant ==>nacos.namespace optional
+	GetConfigDetail(param vo.ConfigParam) (*model.ConfigDetail, error)
+
+	// GetConfigs use to get many configs at once, every config gets its own result or error
+	// dataId  require
+	// group   require
+	// tenant ==>nacos.namespace optional
+	GetConfigs(params []vo.ConfigParam) []model.ConfigQueryResult
+
 	// PublishConfig use to publish config to nacos server
 	// dataId  require
 	// group   require
@@ -43,19 +61,93 @@ type IConfigClient interface {
 	// tenant ==>nacos.namespace optional
 	DeleteConfig(param vo.ConfigParam) (bool, error)
 
-	// ListenConfig use to listen config change,it will callback OnChange() when config change
+	// ListenConfig use to listen config change,it will callback OnChange() or OnChangeEvent() when config change
 	// dataId  require
 	// group   require
-	// onchange require
+	// onchange or onChangeEvent require
+	// notifyInitial optional, callback with the current value before returning
+	// listenerId optional, names the listener for CancelListenConfig, unique among the listeners of the config
 	// tenant ==>nacos.namespace optional
 	ListenConfig(params vo.ConfigParam) (err error)
 
 	//CancelListenConfig use to cancel listen config change
 	// dataId  require
 	// group   require
+	// listenerId optional, the listener added with the same ListenerId is removed, otherwise the only listener
+	//   added without ListenerId; an error is returned if there are several of those
 	// tenant ==>nacos.namespace optional
 	CancelListenConfig(params vo.ConfigParam) (err error)
 
+	// AddConfigListener is ListenConfig returning a handle of the listener, which RemoveConfigListener
+	// removes without affecting the other listeners of the config
+	AddConfigListener(params vo.ConfigParam) (*ConfigListenerHandle, error)
+
+	// RemoveConfigListener use to remove the listener added by AddConfigListener, the config is no longer
+	// listened on the server once its last listener is removed
+	RemoveConfigListener(handle *ConfigListenerHandle) error
+
+	// WatchConfig use to receive the changes of config from a channel instead of a callback, the channel is
+	// closed once ctx is done. Watches and listeners of the same config share one listening
+	// dataId  require
+	// group   require
+	// notifyInitial optional, the current value is the first event
+	// tenant ==>nacos.namespace optional
+	WatchConfig(ctx context.Context, param vo.ConfigParam, opts ...WatchOption) (<-chan ConfigEvent, error)
+
+	// PublishBetaConfig use to publish a beta version of config, served only to the listed clients
+	// dataId  require
+	// group   require
+	// content require
+	// betaIps or grayName and grayRuleExp require
+	// tenant ==>nacos.namespace optional
+	PublishBetaConfig(param vo.ConfigParam) (bool, error)
+
+	// QueryBetaConfig use to query the beta version of config, it returns nil if there is none
+	// dataId  require
+	// group   require
+	// tenant ==>nacos.namespace optional
+	QueryBetaConfig(param vo.ConfigParam) (*model.ConfigBeta, error)
+
+	// StopBetaConfig use to remove the beta version of config
+	// dataId  require
+	// group   require
+	// tenant ==>nacos.namespace optional
+	StopBetaConfig(param vo.ConfigParam) (bool, error)
+
+	// ListConfigHistory use to list the revisions of config, latest first
+	// dataId  require
+	// group   require
+	// tenant ==>nacos.namespace optional
+	// pageNo  option,default is 1
+	// pageSize option,default is 10
+	ListConfigHistory(param vo.ConfigParam, page vo.PageParam) (*model.ConfigHistoryPage, error)
+
+	// GetConfigHistory use to get a revision of config with its content
+	// dataId    require
+	// group     require
+	// historyId require
+	// tenant ==>nacos.namespace optional
+	GetConfigHistory(param vo.ConfigParam, historyId string) (*model.ConfigHistoryItem, error)
+
+	// RollbackConfig use to publish the content of a revision again, it fails if the config is modified concurrently
+	// dataId    require
+	// group     require
+	// historyId require
+	// casMd5    optional, the md5 of the content being rolled back, the rollback fails if the config has changed since
+	// tenant ==>nacos.namespace optional
+	RollbackConfig(param vo.ConfigParam, historyId string) (bool, error)
+
+	// FuzzyListenConfig use to listen all configs matching the patterns, it will callback OnChangeEvent() when
+	// a matching config is added, modified or deleted. It falls back to searching the configs periodically
+	// when the server doesn't support fuzzy watch (before nacos 3.0)
+	// dataIdPattern  require, glob like tenant-*.yaml
+	// groupPattern   require, glob
+	// onChangeEvent  require
+	FuzzyListenConfig(param vo.FuzzyListenConfigParam) (err error)
+
+	// CancelFuzzyListenConfig use to cancel a listening added by FuzzyListenConfig
+	CancelFuzzyListenConfig(param vo.FuzzyListenConfigParam) (err error)
+
 	// SearchConfig use to search nacos config
 	// search  require search=accurate--精确搜索  search=blur--模糊搜索
 	// group   option
@@ -65,6 +157,78 @@ type IConfigClient interface {
 	// pageSize option,default is 10
 	SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage, error)
 
+	// SearchConfigAll use to search nacos config across all the pages, the pages are fetched lazily
+	// the result has the shape of iter.Seq2[model.ConfigItem, error], range over it with go 1.23 or later
+	// configTags, types, content option, filter the configs on the server
+	// pageNo  option, the page to start from, default is 1
+	// pageSize option,default is 100
+	// maxItems option, stop after that many configs, default is all of them
+	SearchConfigAll(param vo.SearchConfigParam) func(yield func(model.ConfigItem, error) bool)
+
+	// GetConfigWithContext is GetConfig bound to ctx, it returns ctx.Err() once ctx is cancelled or its deadline
+	// expires, without falling back to the local snapshot
+	GetConfigWithContext(ctx context.Context, param vo.ConfigParam) (string, error)
+
+	// GetConfigDetailWithContext is GetConfigDetail bound to ctx
+	GetConfigDetailWithContext(ctx context.Context, param vo.ConfigParam) (*model.ConfigDetail, error)
+
+	// GetConfigsWithContext is GetConfigs bound to ctx
+	GetConfigsWithContext(ctx context.Context, params []vo.ConfigParam) []model.ConfigQueryResult
+
+	// ListenConfigWithContext is ListenConfig bound to ctx, ctx only limits the fetch of the initial value
+	ListenConfigWithContext(ctx context.Context, params vo.ConfigParam) (err error)
+
+	// AddConfigListenerWithContext is AddConfigListener bound to ctx, ctx only limits the fetch of the initial value
+	AddConfigListenerWithContext(ctx context.Context, params vo.ConfigParam) (*ConfigListenerHandle, error)
+
+	// PublishConfigWithContext is PublishConfig bound to ctx
+	PublishConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error)
+
+	// DeleteConfigWithContext is DeleteConfig bound to ctx
+	DeleteConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error)
+
+	// SearchConfigWithContext is SearchConfig bound to ctx
+	SearchConfigWithContext(ctx context.Context, param vo.SearchConfigParam) (*model.ConfigPage, error)
+
+	// SearchConfigAllWithContext is SearchConfigAll bound to ctx
+	SearchConfigAllWithContext(ctx context.Context, param vo.SearchConfigParam) func(yield func(model.ConfigItem, error) bool)
+
+	// PublishBetaConfigWithContext is PublishBetaConfig bound to ctx
+	PublishBetaConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error)
+
+	// QueryBetaConfigWithContext is QueryBetaConfig bound to ctx
+	QueryBetaConfigWithContext(ctx context.Context, param vo.ConfigParam) (*model.ConfigBeta, error)
+
+	// StopBetaConfigWithContext is StopBetaConfig bound to ctx
+	StopBetaConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error)
+
+	// ListConfigHistoryWithContext is ListConfigHistory bound to ctx
+	ListConfigHistoryWithContext(ctx context.Context, param vo.ConfigParam, page vo.PageParam) (*model.ConfigHistoryPage, error)
+
+	// GetConfigHistoryWithContext is GetConfigHistory bound to ctx
+	GetConfigHistoryWithContext(ctx context.Context, param vo.ConfigParam, historyId string) (*model.ConfigHistoryItem, error)
+
+	// RollbackConfigWithContext is RollbackConfig bound to ctx
+	RollbackConfigWithContext(ctx context.Context, param vo.ConfigParam, historyId string) (bool, error)
+
+	// RotateConfigKey use to encrypt the cipher- configs of the namespace again with a new master key
+	// NewKeyId   require
+	// DataId     optional, default value is cipher-*
+	// ReportFile optional, the rotation is resumed from the report written by an interrupted one
+	RotateConfigKey(param vo.RotateConfigKeyParam) (*model.ConfigKeyRotationReport, error)
+
+	// RotateConfigKeyWithContext is RotateConfigKey bound to ctx
+	RotateConfigKeyWithContext(ctx context.Context, param vo.RotateConfigKeyParam) (*model.ConfigKeyRotationReport, error)
+
+	// RegisterConfigFilter use to add a filter to the content being published and read, e.g. the one created by
+	// NewConfigPlaceholderFilter, filters should be registered before the client is used
+	RegisterConfigFilter(configFilter filter.IConfigFilter) error
+
+	// RegisterConfigValidator use to add a validator to the content being published, e.g. the one created by
+	// filter.NewConfigSyntaxValidator or filter.NewJsonSchemaValidator, a rejected publish returns a
+	// *filter.ConfigValidationError
+	RegisterConfigValidator(validator filter.IConfigValidator) error
+
 	// CloseClient Close the GRPC client
 	CloseClient()
 }
@@ -18,9 +18,14 @@ package config_client
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"github.com/nacos-group/nacos-sdk-go/v2/common/security"
 	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc"
@@ -28,8 +33,10 @@ import (
 	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_response"
 	"github.com/nacos-group/nacos-sdk-go/v2/model"
 
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/cache"
 	"github.com/nacos-group/nacos-sdk-go/v2/clients/nacos_client"
 	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	nacos_inner_encryption "github.com/nacos-group/nacos-sdk-go/v2/common/encryption"
 	"github.com/nacos-group/nacos-sdk-go/v2/common/http_agent"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
 	"github.com/stretchr/testify/assert"
@@ -110,24 +117,39 @@ type MockConfigProxyForUsingLocalDiskCache struct {
 	MockConfigProxy
 }
 
-func (m *MockConfigProxyForUsingLocalDiskCache) queryConfig(dataId, group, tenant string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error) {
+func (m *MockConfigProxyForUsingLocalDiskCache) queryConfig(ctx context.Context, dataId, group, tenant, tag string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error) {
 	return nil, errors.New("mock err for using localCache")
 }
 
 type MockConfigProxy struct {
 }
 
-func (m *MockConfigProxy) queryConfig(dataId, group, tenant string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error) {
+func (m *MockConfigProxy) queryConfig(ctx context.Context, dataId, group, tenant, tag string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error) {
 	cacheKey := util.GetConfigCacheKey(dataId, group, tenant)
-	if IsLimited(cacheKey) {
-		return nil, errors.New("request is limited")
+	if err := client.acquireRateLimit(ctx, cacheKey); err != nil {
+		return nil, err
 	}
 	return &rpc_response.ConfigQueryResponse{Content: "hello world", Response: &rpc_response.Response{Success: true}}, nil
 }
-func (m *MockConfigProxy) searchConfigProxy(param vo.SearchConfigParam, tenant, accessKey, secretKey string) (*model.ConfigPage, error) {
+func (m *MockConfigProxy) searchConfigProxy(ctx context.Context, param vo.SearchConfigParam, tenant, accessKey, secretKey string) (*model.ConfigPage, error) {
 	return &model.ConfigPage{TotalCount: 1}, nil
 }
-func (m *MockConfigProxy) requestProxy(rpcClient *rpc.RpcClient, request rpc_request.IRequest, timeoutMills uint64) (rpc_response.IResponse, error) {
+func (m *MockConfigProxy) queryBetaConfigProxy(ctx context.Context, dataId, group, tenant string) (*model.ConfigBeta, error) {
+	return &model.ConfigBeta{DataId: dataId, Group: group, Tenant: tenant, BetaIps: "127.0.0.1"}, nil
+}
+func (m *MockConfigProxy) stopBetaConfigProxy(ctx context.Context, dataId, group, tenant string) (bool, error) {
+	return true, nil
+}
+func (m *MockConfigProxy) listConfigHistoryProxy(ctx context.Context, dataId, group, tenant string, page vo.PageParam) (*model.ConfigHistoryPage, error) {
+	return &model.ConfigHistoryPage{PageNumber: page.PageNo}, nil
+}
+func (m *MockConfigProxy) getConfigHistoryProxy(ctx context.Context, dataId, group, tenant, historyId string) (*model.ConfigHistoryItem, error) {
+	return &model.ConfigHistoryItem{Id: json.Number(historyId), DataId: dataId, Group: group, Tenant: tenant, Content: "history"}, nil
+}
+func (m *MockConfigProxy) queryConfigMetadataProxy(ctx context.Context, dataId, group, tenant string) (*model.ConfigMetadata, error) {
+	return &model.ConfigMetadata{DataId: dataId, Group: group, Tenant: tenant}, nil
+}
+func (m *MockConfigProxy) requestProxy(ctx context.Context, rpcClient *rpc.RpcClient, request rpc_request.IRequest, timeoutMills uint64) (rpc_response.IResponse, error) {
 	return &rpc_response.MockResponse{Response: &rpc_response.Response{Success: true}}, nil
 }
 func (m *MockConfigProxy) createRpcClient(ctx context.Context, taskId string, client *ConfigClient) *rpc.RpcClient {
@@ -155,6 +177,82 @@ func Test_GetConfig(t *testing.T) {
 	assert.Equal(t, "hello world", content)
 }
 
+func Test_GetConfigWithContextCancelled(t *testing.T) {
+	client := createConfigClientForKms()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	content, err := client.GetConfigWithContext(ctx, vo.ConfigParam{
+		DataId: localConfigTest.DataId,
+		Group:  localConfigTest.Group})
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, "", content)
+}
+
+type MockConfigProxyForDetail struct {
+	MockConfigProxy
+}
+
+func (m *MockConfigProxyForDetail) queryConfig(ctx context.Context, dataId, group, tenant, tag string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error) {
+	return &rpc_response.ConfigQueryResponse{
+		Content:      "a: 1",
+		ContentType:  "yaml",
+		Md5:          util.Md5("a: 1"),
+		LastModified: 1700000000000,
+		Tag:          tag,
+		IsBeta:       true,
+		Response:     &rpc_response.Response{Success: true},
+	}, nil
+}
+
+func Test_GetConfigDetail(t *testing.T) {
+	client := createConfigClientTest()
+	client.configProxy = &MockConfigProxyForDetail{}
+	detail, err := client.GetConfigDetail(vo.ConfigParam{DataId: "detail.yaml", Group: "group", Tag: "gray"})
+	assert.Nil(t, err)
+	assert.Equal(t, "a: 1", detail.Content)
+	assert.Equal(t, "yaml", detail.ContentType)
+	assert.Equal(t, util.Md5("a: 1"), detail.Md5)
+	assert.Equal(t, int64(1700000000000), detail.LastModified)
+	assert.Equal(t, "gray", detail.Tag)
+	assert.True(t, detail.IsBeta)
+	assert.False(t, detail.Encrypted)
+	assert.Equal(t, model.ConfigSourceServer, detail.Source)
+	assert.False(t, detail.IsLocal())
+
+	_, err = client.GetConfigDetail(vo.ConfigParam{})
+	assert.NotNil(t, err)
+}
+
+func Test_GetConfigDetail_Snapshot(t *testing.T) {
+	client := createConfigClientForKms()
+	clientConfig, _ := client.GetClientConfig()
+	cacheKey := util.GetConfigCacheKey("detail-snapshot", "group", clientConfig.NamespaceId)
+	assert.Nil(t, cache.WriteConfigToFile(cacheKey, client.configCacheDir, "snapshot"))
+
+	detail, err := client.GetConfigDetail(vo.ConfigParam{DataId: "detail-snapshot", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "snapshot", detail.Content)
+	assert.Equal(t, util.Md5("snapshot"), detail.Md5)
+	assert.Equal(t, model.ConfigSourceSnapshot, detail.Source)
+	assert.True(t, detail.IsLocal())
+}
+
+func Test_GetConfigDetail_Failover(t *testing.T) {
+	client := createConfigClientTest()
+	clientConfig, _ := client.GetClientConfig()
+	cacheKey := util.GetConfigCacheKey("detail-failover", "group", clientConfig.NamespaceId)
+	fileName := cache.GetConfigFailOverContentFileName(cacheKey, client.configCacheDir)
+	assert.Nil(t, os.MkdirAll(filepath.Dir(fileName), 0755))
+	assert.Nil(t, os.WriteFile(fileName, []byte("failover"), 0644))
+	defer os.Remove(fileName)
+
+	detail, err := client.GetConfigDetail(vo.ConfigParam{DataId: "detail-failover", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "failover", detail.Content)
+	assert.Equal(t, model.ConfigSourceFailover, detail.Source)
+}
+
 func Test_SearchConfig(t *testing.T) {
 	client := createConfigClientTest()
 	_, _ = client.PublishConfig(vo.ConfigParam{
@@ -172,6 +270,110 @@ func Test_SearchConfig(t *testing.T) {
 	assert.NotEmpty(t, configPage)
 }
 
+type MockConfigProxyForSearch struct {
+	MockConfigProxy
+	total  int
+	params []vo.SearchConfigParam
+}
+
+func (m *MockConfigProxyForSearch) searchConfigProxy(ctx context.Context, param vo.SearchConfigParam, tenant, accessKey, secretKey string) (*model.ConfigPage, error) {
+	m.params = append(m.params, param)
+	if param.PageNo == 3 && m.total < 0 {
+		return nil, errors.New("search failed")
+	}
+	total := m.total
+	if total < 0 {
+		total = 100
+	}
+	page := &model.ConfigPage{TotalCount: total, PageNumber: param.PageNo,
+		PagesAvailable: (total + param.PageSize - 1) / param.PageSize}
+	for i := (param.PageNo - 1) * param.PageSize; i < total && i < param.PageNo*param.PageSize; i++ {
+		page.PageItems = append(page.PageItems, model.ConfigItem{DataId: "dataId-" + strconv.Itoa(i), Group: param.Group})
+	}
+	return page, nil
+}
+
+func Test_SearchConfigAll(t *testing.T) {
+	client := createConfigClientTest()
+	proxy := &MockConfigProxyForSearch{total: 25}
+	client.configProxy = proxy
+	param := vo.SearchConfigParam{
+		Search:     "blur",
+		Group:      "DEFAULT_GROUP",
+		ConfigTags: []string{"a", "b"},
+		Types:      []string{"yaml"},
+		Content:    "timeout",
+		PageSize:   10,
+	}
+
+	var dataIds []string
+	client.SearchConfigAll(param)(func(item model.ConfigItem, err error) bool {
+		assert.Nil(t, err)
+		dataIds = append(dataIds, item.DataId)
+		return true
+	})
+	assert.Len(t, dataIds, 25)
+	assert.Equal(t, "dataId-24", dataIds[24])
+	assert.Len(t, proxy.params, 3)
+	assert.Equal(t, []string{"a", "b"}, proxy.params[0].ConfigTags)
+	assert.Equal(t, "timeout", proxy.params[0].Content)
+
+	t.Run("MaxItems", func(t *testing.T) {
+		proxy.params = nil
+		param.MaxItems = 12
+		count := 0
+		client.SearchConfigAll(param)(func(item model.ConfigItem, err error) bool {
+			count++
+			return true
+		})
+		assert.Equal(t, 12, count)
+		assert.Len(t, proxy.params, 2)
+	})
+
+	t.Run("Break", func(t *testing.T) {
+		proxy.params = nil
+		param.MaxItems = 0
+		count := 0
+		client.SearchConfigAll(param)(func(item model.ConfigItem, err error) bool {
+			count++
+			return count < 5
+		})
+		assert.Equal(t, 5, count)
+		assert.Len(t, proxy.params, 1)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		proxy.total = -1
+		var errs []error
+		count := 0
+		client.SearchConfigAll(param)(func(item model.ConfigItem, err error) bool {
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				count++
+			}
+			return true
+		})
+		assert.Equal(t, 20, count)
+		assert.Len(t, errs, 1)
+	})
+}
+
+func Test_RenameParam(t *testing.T) {
+	params := util.TransformObject2Param(vo.SearchConfigParam{ConfigTags: []string{"a", "b"}, Content: "timeout"})
+	assert.Equal(t, "a,b", params["config_tags"])
+	_, ok := params["maxItems"]
+	assert.False(t, ok)
+
+	renameParam(params, "config_tags", "configTags")
+	renameParam(params, "types", "type")
+	assert.Equal(t, "a,b", params["configTags"])
+	_, ok = params["config_tags"]
+	assert.False(t, ok)
+	_, ok = params["type"]
+	assert.False(t, ok)
+}
+
 func Test_GetConfigTls(t *testing.T) {
 	client := createConfigClientTestTls()
 	_, _ = client.PublishConfig(vo.ConfigParam{
@@ -328,6 +530,43 @@ func TestListen(t *testing.T) {
 		err := client.ListenConfig(listenConfigParam)
 		assert.Error(t, err)
 	})
+	// ListenConfig with the current value delivered before returning
+	t.Run("TestListenConfigNotifyInitial", func(t *testing.T) {
+		client := createConfigClientTest()
+		var received []string
+		err := client.ListenConfig(vo.ConfigParam{
+			DataId:        "notify-initial",
+			Group:         localConfigTest.Group,
+			NotifyInitial: true,
+			OnChange: func(namespace, group, dataId, data string) {
+				received = append(received, data)
+			},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"hello world"}, received)
+
+		clientConfig, _ := client.GetClientConfig()
+		v, ok := client.cacheMap.Get(util.GetConfigCacheKey("notify-initial", localConfigTest.Group, clientConfig.NamespaceId))
+		assert.True(t, ok)
+		data := v.(cacheData)
+		assert.Equal(t, util.Md5("hello world"), data.md5)
+		// the listen task won't report the delivered value once more
+		assert.Equal(t, data.md5, data.cacheDataListener.lastMd5)
+	})
+	// ListenConfig fails when the initial value can't be read
+	t.Run("TestListenConfigNotifyInitialFailed", func(t *testing.T) {
+		client := createConfigClientForKms()
+		err := client.ListenConfig(vo.ConfigParam{
+			DataId:        "notify-initial-without-snapshot",
+			Group:         localConfigTest.Group,
+			NotifyInitial: true,
+			OnChange: func(namespace, group, dataId, data string) {
+				t.Fatal("listener must not be called")
+			},
+		})
+		assert.NotNil(t, err)
+		assert.True(t, client.cacheMap.IsEmpty())
+	})
 }
 
 // CancelListenConfig
@@ -409,3 +648,28 @@ func Test_ConfigClientWithProvider(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "hello world", content)
 }
+
+func Test_LocalKMSConfig(t *testing.T) {
+	key, err := nacos_inner_encryption.GenerateLocalMasterKey()
+	assert.Nil(t, err)
+	t.Setenv(nacos_inner_encryption.DefaultLocalKeyringEnv, `{"keys": {"key-1": "`+key+`"}}`)
+	nc := nacos_client.NacosClient{}
+	_ = nc.SetServerConfig([]constant.ServerConfig{*serverConfigWithOptions})
+	_ = nc.SetClientConfig(*constant.NewClientConfig(
+		constant.WithNotLoadCacheAtStart(true),
+		constant.WithLocalKMSConfig(&constant.LocalKMSConfig{}),
+	))
+	_ = nc.SetHttpAgent(&http_agent.HttpAgent{})
+	client, err := NewConfigClient(&nc)
+	assert.Nil(t, err)
+	defer client.CloseClient()
+
+	param := &vo.ConfigParam{DataId: "cipher-local-aes-256-app", Group: "group", Content: "password=secret", UsageType: vo.RequestType}
+	assert.Nil(t, client.configFilterChainManager.DoFilters(param))
+	assert.NotEqual(t, "password=secret", param.Content)
+	assert.True(t, strings.HasPrefix(param.EncryptedDataKey, "key-1:"))
+
+	param.UsageType = vo.ResponseType
+	assert.Nil(t, client.configFilterChainManager.DoFilters(param))
+	assert.Equal(t, "password=secret", param.Content)
+}
@@ -0,0 +1,418 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/encoding"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+)
+
+// CompositeChangeEvent is a change of the merged view of a CompositeConfig, caused by a change of one of its sources.
+type CompositeChangeEvent struct {
+	// DataId and Group of the source that changed
+	DataId string
+	Group  string
+	// ChangedItems are the changes of the merged view, keys are flattened like encoding.FlattenConfig does
+	ChangedItems []model.ConfigChangeItem
+}
+
+// CompositeConfig merges several configs into one view, a later config overrides the keys of the earlier ones.
+// Maps are merged key by key, any other value, lists included, is replaced as a whole.
+// The sources are listened to, the view is rebuilt on every change of them.
+type CompositeConfig struct {
+	client   IConfigClient
+	sources  []*compositeSource
+	merged   atomic.Pointer[map[string]interface{}]
+	mux      sync.Mutex
+	ready    bool
+	onChange func(event *CompositeChangeEvent)
+	onError  func(error)
+}
+
+type compositeSource struct {
+	param      vo.ConfigParam
+	configType string
+	handle     *ConfigListenerHandle
+	tree       map[string]interface{}
+	initErr    error
+}
+
+type CompositeOption func(*CompositeConfig)
+
+// WithCompositeChangeHandler is called after a change of a source has changed the merged view.
+func WithCompositeChangeHandler(onChange func(event *CompositeChangeEvent)) CompositeOption {
+	return func(composite *CompositeConfig) {
+		composite.onChange = onChange
+	}
+}
+
+// WithCompositeErrorHandler is called when a pushed config is dropped because it can't be parsed.
+func WithCompositeErrorHandler(onError func(err error)) CompositeOption {
+	return func(composite *CompositeConfig) {
+		composite.onError = onError
+	}
+}
+
+// NewCompositeConfig loads the configs of params, from the lowest precedence to the highest, and keeps their merged
+// view up to date. Each config is parsed by its param.Type, or by the extension of its param.DataId when Type is
+// empty, it must be a type that decodes into a map, e.g. yaml, json, properties or toml. A config that doesn't
+// exist contributes nothing to the view. param.OnChange is ignored, use WithCompositeChangeHandler instead.
+func NewCompositeConfig(client IConfigClient, params []vo.ConfigParam, opts ...CompositeOption) (*CompositeConfig, error) {
+	if len(params) == 0 {
+		return nil, errors.New("[client.NewCompositeConfig] params can not be empty")
+	}
+	composite := &CompositeConfig{client: client}
+	for _, opt := range opts {
+		opt(composite)
+	}
+	for _, param := range params {
+		if len(param.DataId) <= 0 {
+			return nil, errors.New("[client.NewCompositeConfig] param.dataId can not be empty")
+		}
+		if len(param.Group) <= 0 {
+			return nil, errors.New("[client.NewCompositeConfig] param.group can not be empty")
+		}
+		configType := encoding.ResolveConfigType(param.Type, param.DataId)
+		if configType == encoding.ConfigTypeText || configType == encoding.ConfigTypeXml {
+			return nil, errors.Errorf("[client.NewCompositeConfig] config type [%s] of dataId=%s can not be merged",
+				configType, param.DataId)
+		}
+		if _, err := encoding.GetConfigDecoder(configType); err != nil {
+			return nil, err
+		}
+		composite.sources = append(composite.sources, &compositeSource{param: param, configType: configType})
+	}
+
+	for _, source := range composite.sources {
+		source := source
+		listenParam := source.param
+		listenParam.NotifyInitial = true
+		listenParam.OnChangeEvent = nil
+		listenParam.OnChange = func(namespace, group, dataId, data string) {
+			if err := composite.update(source, data); err != nil {
+				composite.reportError(err)
+			}
+		}
+		handle, err := client.AddConfigListener(listenParam)
+		if err != nil {
+			_ = composite.Close()
+			return nil, err
+		}
+		source.handle = handle
+	}
+
+	composite.mux.Lock()
+	defer composite.mux.Unlock()
+	for _, source := range composite.sources {
+		if source.initErr != nil {
+			_ = composite.Close()
+			return nil, source.initErr
+		}
+	}
+	composite.merged.Store(composite.merge())
+	composite.ready = true
+	return composite, nil
+}
+
+// Close stops listening to the sources, the merged view keeps its last value.
+func (composite *CompositeConfig) Close() error {
+	var err error
+	for _, source := range composite.sources {
+		if source.handle == nil {
+			continue
+		}
+		if removeErr := composite.client.RemoveConfigListener(source.handle); removeErr != nil {
+			err = removeErr
+		}
+		source.handle = nil
+	}
+	return err
+}
+
+func (composite *CompositeConfig) update(source *compositeSource, content string) error {
+	composite.mux.Lock()
+	defer composite.mux.Unlock()
+
+	tree, err := source.parse(content)
+	if !composite.ready {
+		// NewCompositeConfig fails if the initial value can't be parsed, a later value may replace it meanwhile
+		source.initErr = err
+	}
+	if err != nil {
+		if !composite.ready {
+			return nil
+		}
+		return err
+	}
+	source.tree = tree
+	if !composite.ready {
+		return nil
+	}
+	oldMerged := composite.merged.Load()
+	newMerged := composite.merge()
+	composite.merged.Store(newMerged)
+	if composite.onChange == nil {
+		return nil
+	}
+	changes := diffConfigItems(encoding.FlattenMap(*oldMerged), encoding.FlattenMap(*newMerged))
+	if len(changes) == 0 {
+		return nil
+	}
+	composite.onChange(&CompositeChangeEvent{
+		DataId:       source.param.DataId,
+		Group:        source.param.Group,
+		ChangedItems: changes,
+	})
+	return nil
+}
+
+func (source *compositeSource) parse(content string) (map[string]interface{}, error) {
+	tree, err := encoding.DecodeConfigToMap(source.configType, content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse composite config source failed, dataId=%s, group=%s, type=%s",
+			source.param.DataId, source.param.Group, source.configType)
+	}
+	return tree, nil
+}
+
+func (composite *CompositeConfig) merge() *map[string]interface{} {
+	merged := make(map[string]interface{})
+	for _, source := range composite.sources {
+		mergeConfigTree(merged, source.tree)
+	}
+	return &merged
+}
+
+// mergeConfigTree merges src into dst, the maps of dst are owned by it and those of src are copied.
+func mergeConfigTree(dst map[string]interface{}, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, ok := toConfigMap(value)
+		if !ok {
+			dst[key] = value
+			continue
+		}
+		dstMap, ok := dst[key].(map[string]interface{})
+		if !ok {
+			dstMap = make(map[string]interface{})
+			dst[key] = dstMap
+		}
+		mergeConfigTree(dstMap, srcMap)
+	}
+}
+
+func toConfigMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			result[fmt.Sprint(key)] = child
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
+
+func (composite *CompositeConfig) reportError(err error) {
+	logger.Errorf("%v, keep the last good value", err)
+	if composite.onError != nil {
+		composite.onError(err)
+	}
+}
+
+// Get returns the value of key in the merged view, key is a "." separated path and list elements are addressed
+// as key[index]. A map or list returned is shared, callers must not modify it.
+func (composite *CompositeConfig) Get(key string) (interface{}, bool) {
+	merged := composite.merged.Load()
+	if merged == nil {
+		return nil, false
+	}
+	var current interface{} = *merged
+	for _, part := range strings.Split(key, ".") {
+		name, indexes, ok := parseConfigPathPart(part)
+		if !ok {
+			return nil, false
+		}
+		if len(name) > 0 {
+			m, ok := toConfigMap(current)
+			if !ok {
+				return nil, false
+			}
+			if current, ok = m[name]; !ok {
+				return nil, false
+			}
+		}
+		for _, index := range indexes {
+			list, ok := current.([]interface{})
+			if !ok || index >= len(list) {
+				return nil, false
+			}
+			current = list[index]
+		}
+	}
+	return current, true
+}
+
+// parseConfigPathPart splits "name[1][2]" into its name and indexes.
+func parseConfigPathPart(part string) (string, []int, bool) {
+	bracket := strings.IndexByte(part, '[')
+	if bracket < 0 {
+		return part, nil, len(part) > 0
+	}
+	name, rest := part[:bracket], part[bracket:]
+	var indexes []int
+	for len(rest) > 0 {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end < 0 {
+			return "", nil, false
+		}
+		index, err := strconv.Atoi(rest[1:end])
+		if err != nil || index < 0 {
+			return "", nil, false
+		}
+		indexes = append(indexes, index)
+		rest = rest[end+1:]
+	}
+	return name, indexes, true
+}
+
+// IsSet reports whether key is present in the merged view.
+func (composite *CompositeConfig) IsSet(key string) bool {
+	_, ok := composite.Get(key)
+	return ok
+}
+
+// GetString returns the value of key as a string, "" if it isn't set or is a map or list.
+func (composite *CompositeConfig) GetString(key string) string {
+	value, ok := composite.Get(key)
+	if !ok || value == nil {
+		return ""
+	}
+	switch value.(type) {
+	case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// GetInt returns the value of key as an int, 0 if it isn't set or isn't a number.
+func (composite *CompositeConfig) GetInt(key string) int {
+	switch value, _ := composite.Get(key); v := value.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case uint64:
+		return int(v)
+	case float64:
+		return int(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return int(f)
+	case string:
+		i, _ := strconv.Atoi(strings.TrimSpace(v))
+		return i
+	}
+	return 0
+}
+
+// GetFloat64 returns the value of key as a float64, 0 if it isn't set or isn't a number.
+func (composite *CompositeConfig) GetFloat64(key string) float64 {
+	switch value, _ := composite.Get(key); v := value.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f
+	}
+	return 0
+}
+
+// GetBool returns the value of key as a bool, false if it isn't set or isn't a bool.
+func (composite *CompositeConfig) GetBool(key string) bool {
+	switch value, _ := composite.Get(key); v := value.(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(strings.TrimSpace(v))
+		return b
+	}
+	return false
+}
+
+// GetDuration returns the value of key parsed by time.ParseDuration, a number is taken as milliseconds.
+// It returns 0 if key isn't set or can't be parsed.
+func (composite *CompositeConfig) GetDuration(key string) time.Duration {
+	value, ok := composite.Get(key)
+	if !ok {
+		return 0
+	}
+	if s, ok := value.(string); ok {
+		if d, err := time.ParseDuration(strings.TrimSpace(s)); err == nil {
+			return d
+		}
+	}
+	return time.Duration(composite.GetInt(key)) * time.Millisecond
+}
+
+// GetStringSlice returns the elements of the list at key as strings, nil if it isn't set or isn't a list.
+func (composite *CompositeConfig) GetStringSlice(key string) []string {
+	value, _ := composite.Get(key)
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		result = append(result, fmt.Sprint(item))
+	}
+	return result
+}
+
+// AllSettings returns the merged view flattened like encoding.FlattenConfig does.
+func (composite *CompositeConfig) AllSettings() map[string]string {
+	merged := composite.merged.Load()
+	if merged == nil {
+		return map[string]string{}
+	}
+	return encoding.FlattenMap(*merged)
+}
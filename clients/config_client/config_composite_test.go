@@ -0,0 +1,119 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+var compositeTestParams = []vo.ConfigParam{
+	{DataId: "defaults.yaml", Group: localConfigTest.Group},
+	{DataId: "env.properties", Group: localConfigTest.Group},
+	{DataId: "app.json", Group: localConfigTest.Group},
+}
+
+func TestCompositeConfig(t *testing.T) {
+	client := createConfigClientTest()
+	client.configProxy = &MockConfigProxyWithContent{contents: map[string]string{
+		"defaults.yaml":  "db:\n  host: localhost\n  port: 3306\n  pool:\n    size: 10\ntimeout: 5s\nfeatures: [a, b]\n",
+		"env.properties": "db.host=db.prod\ndebug=true\n",
+		"app.json":       `{"db":{"pool":{"size":20}},"ratio":0.5}`,
+	}}
+	var events []*CompositeChangeEvent
+	var failed int
+	composite, err := NewCompositeConfig(client, compositeTestParams,
+		WithCompositeChangeHandler(func(event *CompositeChangeEvent) { events = append(events, event) }),
+		WithCompositeErrorHandler(func(err error) { failed++ }))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "db.prod", composite.GetString("db.host"))
+	assert.Equal(t, 3306, composite.GetInt("db.port"))
+	assert.Equal(t, 20, composite.GetInt("db.pool.size"))
+	assert.Equal(t, 5*time.Second, composite.GetDuration("timeout"))
+	assert.True(t, composite.GetBool("debug"))
+	assert.Equal(t, 0.5, composite.GetFloat64("ratio"))
+	assert.Equal(t, []string{"a", "b"}, composite.GetStringSlice("features"))
+	assert.Equal(t, "b", composite.GetString("features[1]"))
+	assert.Equal(t, "", composite.GetString("db"))
+	assert.False(t, composite.IsSet("db.user"))
+	assert.Empty(t, events)
+
+	t.Run("Push", func(t *testing.T) {
+		pushConfig(t, client, compositeTestParams[1], "db.host=db.test\n")
+		assert.Equal(t, "db.test", composite.GetString("db.host"))
+		assert.False(t, composite.IsSet("debug"))
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, "env.properties", events[0].DataId)
+			assert.Equal(t, []model.ConfigChangeItem{
+				{Key: "db.host", OldValue: "db.prod", NewValue: "db.test", Type: model.ConfigModified},
+				{Key: "debug", OldValue: "true", Type: model.ConfigDeleted},
+			}, events[0].ChangedItems)
+		}
+	})
+	t.Run("OverriddenPush", func(t *testing.T) {
+		// the pool size of defaults is overridden by app, the merged view doesn't change
+		pushConfig(t, client, compositeTestParams[0], "db:\n  host: localhost\n  port: 3306\n  pool:\n    size: 15\ntimeout: 5s\nfeatures: [a, b]\n")
+		assert.Equal(t, 20, composite.GetInt("db.pool.size"))
+		assert.Len(t, events, 1)
+	})
+	t.Run("MalformedPushKeepsLastGoodValue", func(t *testing.T) {
+		pushConfig(t, client, compositeTestParams[2], `{"db":`)
+		assert.Equal(t, 20, composite.GetInt("db.pool.size"))
+		assert.Equal(t, 1, failed)
+		assert.Len(t, events, 1)
+	})
+	assert.Nil(t, composite.Close())
+	assert.True(t, client.cacheMap.IsEmpty())
+}
+
+func TestCompositeConfig_ConfigNotExist(t *testing.T) {
+	client := createConfigClientTest()
+	client.configProxy = &MockConfigProxyWithContent{contents: map[string]string{"defaults.yaml": "port: 8080"}}
+	composite, err := NewCompositeConfig(client, compositeTestParams)
+	assert.Nil(t, err)
+	assert.Equal(t, 8080, composite.GetInt("port"))
+	assert.Equal(t, map[string]string{"port": "8080"}, composite.AllSettings())
+}
+
+func TestCompositeConfig_Invalid(t *testing.T) {
+	client := createConfigClientTest()
+	client.configProxy = &MockConfigProxyWithContent{contents: map[string]string{"app.json": `{"db":`}}
+	_, err := NewCompositeConfig(client, compositeTestParams)
+	assert.NotNil(t, err)
+	assert.True(t, client.cacheMap.IsEmpty())
+
+	_, err = NewCompositeConfig(client, nil)
+	assert.NotNil(t, err)
+	_, err = NewCompositeConfig(client, []vo.ConfigParam{{DataId: "app.txt", Group: localConfigTest.Group}})
+	assert.NotNil(t, err)
+}
+
+func TestMergeConfigTree(t *testing.T) {
+	base := map[string]interface{}{"a": map[string]interface{}{"b": 1, "c": 2}, "list": []interface{}{1, 2}}
+	override := map[string]interface{}{"a": map[string]interface{}{"c": 3}, "list": []interface{}{3}}
+	merged := make(map[string]interface{})
+	mergeConfigTree(merged, base)
+	mergeConfigTree(merged, override)
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": 1, "c": 3}, "list": []interface{}{3}}, merged)
+	// the sources are left alone
+	assert.Equal(t, 2, base["a"].(map[string]interface{})["c"])
+}
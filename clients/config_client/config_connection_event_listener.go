@@ -38,6 +38,7 @@ func (c *ConfigConnectionEventListener) OnConnected() {
 	logger.Info("[ConfigConnectionEventListener] connect to config server for taskId: " + c.taskId)
 	if c.client != nil {
 		c.client.asyncNotifyListenConfig()
+		c.client.resetFuzzyWatchers()
 	}
 }
 
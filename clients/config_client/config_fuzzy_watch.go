@@ -0,0 +1,443 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_request"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+)
+
+const fuzzyWatchSearchPageSize = 100
+
+var errFuzzyWatchUnsupported = errors.New("fuzzy watch is not supported by server")
+
+var (
+	groupKeyEncoder = strings.NewReplacer("%", "%25", "+", "%2B")
+	groupKeyDecoder = strings.NewReplacer("%2B", "+", "%25", "%")
+)
+
+type fuzzyWatcher struct {
+	dataIdPattern     string
+	groupPattern      string
+	tenant            string
+	listener          vo.ConfigChangeListener
+	mux               sync.Mutex
+	groupKeys         map[string]struct{}
+	serverWatching    bool
+	serverUnsupported bool
+}
+
+func newFuzzyWatcher(param vo.FuzzyListenConfigParam, tenant string) *fuzzyWatcher {
+	return &fuzzyWatcher{
+		dataIdPattern: param.DataIdPattern,
+		groupPattern:  param.GroupPattern,
+		tenant:        tenant,
+		listener:      param.OnChangeEvent,
+		groupKeys:     make(map[string]struct{}),
+	}
+}
+
+// dispatchKey keeps the changes of one config delivered to this watcher in order.
+func (w *fuzzyWatcher) dispatchKey(cacheKey string) string {
+	return cacheKey + "#fuzzy#" + w.groupKeyPattern()
+}
+
+// groupKeyPattern is the pattern format of the nacos 3 fuzzy watch requests.
+func (w *fuzzyWatcher) groupKeyPattern() string {
+	return getGroupKeyPattern(w.dataIdPattern, w.groupPattern, w.tenant)
+}
+
+func (w *fuzzyWatcher) matches(dataId, group string) bool {
+	dataIdMatched, _ := path.Match(w.dataIdPattern, dataId)
+	groupMatched, _ := path.Match(w.groupPattern, group)
+	return dataIdMatched && groupMatched
+}
+
+func (w *fuzzyWatcher) receivedGroupKeys() []string {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	keys := make([]string, 0, len(w.groupKeys))
+	for key := range w.groupKeys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (w *fuzzyWatcher) addGroupKey(groupKey string) bool {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if _, ok := w.groupKeys[groupKey]; ok {
+		return false
+	}
+	w.groupKeys[groupKey] = struct{}{}
+	return true
+}
+
+func (w *fuzzyWatcher) removeGroupKey(groupKey string) bool {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if _, ok := w.groupKeys[groupKey]; !ok {
+		return false
+	}
+	delete(w.groupKeys, groupKey)
+	return true
+}
+
+// FuzzyListenConfig watches all configs whose dataId and group match the glob patterns,
+// OnChangeEvent is called when a matching config is added, modified or deleted.
+// Configs that already exist are reported as added once the watch is established.
+func (client *ConfigClient) FuzzyListenConfig(param vo.FuzzyListenConfigParam) (err error) {
+	if len(param.DataIdPattern) <= 0 {
+		return errors.New("[client.FuzzyListenConfig] DataIdPattern can not be empty")
+	}
+	if len(param.GroupPattern) <= 0 {
+		return errors.New("[client.FuzzyListenConfig] GroupPattern can not be empty")
+	}
+	if param.OnChangeEvent == nil {
+		return errors.New("[client.FuzzyListenConfig] OnChangeEvent can not be nil")
+	}
+	if _, err = path.Match(param.DataIdPattern, ""); err != nil {
+		return errors.Wrap(err, "[client.FuzzyListenConfig] invalid DataIdPattern")
+	}
+	if _, err = path.Match(param.GroupPattern, ""); err != nil {
+		return errors.Wrap(err, "[client.FuzzyListenConfig] invalid GroupPattern")
+	}
+	clientConfig, err := client.GetClientConfig()
+	if err != nil {
+		return errors.New("[checkConfigInfo.GetClientConfig] failed")
+	}
+
+	watcher := newFuzzyWatcher(param, clientConfig.NamespaceId)
+	if !client.fuzzyWatchers.SetIfAbsent(watcher.groupKeyPattern(), watcher) {
+		return errors.Errorf("[client.FuzzyListenConfig] DataIdPattern:%s Group:%s is already listened",
+			param.DataIdPattern, param.GroupPattern)
+	}
+	client.fuzzyWatchOnce.Do(client.startFuzzyWatch)
+	client.asyncNotifyFuzzyWatch()
+	logger.Infof("Fuzzy listen config DataIdPattern:%s GroupPattern:%s", param.DataIdPattern, param.GroupPattern)
+	return nil
+}
+
+// CancelFuzzyListenConfig stops a watch added by FuzzyListenConfig.
+func (client *ConfigClient) CancelFuzzyListenConfig(param vo.FuzzyListenConfigParam) (err error) {
+	clientConfig, err := client.GetClientConfig()
+	if err != nil {
+		logger.Errorf("[checkConfigInfo.GetClientConfig] failed,err:%+v", err)
+		return
+	}
+	value, ok := client.fuzzyWatchers.Pop(getGroupKeyPattern(param.DataIdPattern, param.GroupPattern, clientConfig.NamespaceId))
+	if !ok {
+		return nil
+	}
+	watcher := value.(*fuzzyWatcher)
+	watcher.mux.Lock()
+	serverWatching := watcher.serverWatching
+	watcher.mux.Unlock()
+	if serverWatching {
+		if err = client.fuzzyWatchOnServer(watcher, constant.FUZZY_WATCH_TYPE_CANCEL); err != nil {
+			logger.Warnf("cancel fuzzy watch on server failed, pattern=%s, err:%v", watcher.groupKeyPattern(), err)
+		}
+	}
+	for _, groupKey := range watcher.receivedGroupKeys() {
+		dataId, group, _, parseErr := parseGroupKey(groupKey)
+		if parseErr != nil || client.isFuzzyWatched(dataId, group) {
+			continue
+		}
+		key := util.GetConfigCacheKey(dataId, group, clientConfig.NamespaceId)
+		if v, ok := client.cacheMap.Get(key); ok && v.(cacheData).cacheDataListener.isFuzzyOnly() {
+			client.cacheMap.Remove(key)
+		}
+	}
+	logger.Infof("Cancel fuzzy listen config DataIdPattern:%s GroupPattern:%s", param.DataIdPattern, param.GroupPattern)
+	return nil
+}
+
+func (client *ConfigClient) startFuzzyWatch() {
+	go func() {
+		ticker := time.NewTicker(constant.FUZZY_WATCH_SYNC_INTERVAL)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-client.fuzzyWatchExecute:
+			case <-ticker.C:
+			case <-client.ctx.Done():
+				return
+			}
+			for _, v := range client.fuzzyWatchers.Items() {
+				client.syncFuzzyWatcher(v.(*fuzzyWatcher))
+			}
+		}
+	}()
+}
+
+func (client *ConfigClient) asyncNotifyFuzzyWatch() {
+	go func() {
+		select {
+		case client.fuzzyWatchExecute <- struct{}{}:
+		case <-client.ctx.Done():
+		}
+	}()
+}
+
+// resetFuzzyWatchers makes the watchers register their patterns again, the new connection doesn't know them.
+func (client *ConfigClient) resetFuzzyWatchers() {
+	if !client.hasFuzzyWatchers() {
+		return
+	}
+	for _, v := range client.fuzzyWatchers.Items() {
+		watcher := v.(*fuzzyWatcher)
+		watcher.mux.Lock()
+		watcher.serverWatching = false
+		watcher.mux.Unlock()
+	}
+	client.asyncNotifyFuzzyWatch()
+}
+
+// syncFuzzyWatcher registers the pattern on server, which pushes the matching configs from then on.
+// When the server doesn't support fuzzy watch, the matching configs are searched on every call instead.
+func (client *ConfigClient) syncFuzzyWatcher(watcher *fuzzyWatcher) {
+	watcher.mux.Lock()
+	serverWatching, serverUnsupported := watcher.serverWatching, watcher.serverUnsupported
+	watcher.mux.Unlock()
+	if serverWatching {
+		return
+	}
+	if !serverUnsupported {
+		err := client.fuzzyWatchOnServer(watcher, constant.FUZZY_WATCH_TYPE_WATCH)
+		if err == nil {
+			watcher.mux.Lock()
+			watcher.serverWatching = true
+			watcher.mux.Unlock()
+			return
+		}
+		if err != errFuzzyWatchUnsupported {
+			logger.Warnf("fuzzy watch failed, pattern=%s, err:%v", watcher.groupKeyPattern(), err)
+			return
+		}
+		logger.Infof("server doesn't support fuzzy watch, search configs of pattern=%s every %v instead",
+			watcher.groupKeyPattern(), constant.FUZZY_WATCH_SYNC_INTERVAL)
+		watcher.mux.Lock()
+		watcher.serverUnsupported = true
+		watcher.mux.Unlock()
+	}
+	client.searchFuzzyWatcher(watcher)
+}
+
+func (client *ConfigClient) fuzzyWatchOnServer(watcher *fuzzyWatcher, watchType string) error {
+	request := rpc_request.NewConfigFuzzyWatchRequest(watcher.groupKeyPattern(), watcher.receivedGroupKeys(), watchType)
+	request.IsInitializing = watchType == constant.FUZZY_WATCH_TYPE_WATCH
+	response, err := client.configProxy.requestProxy(client.ctx, client.configProxy.getRpcClient(client), request,
+		constant.DEFAULT_TIMEOUT_MILLS)
+	if err != nil {
+		return err
+	}
+	if response == nil {
+		return errors.New("ConfigFuzzyWatchRequest failure, response is nil")
+	}
+	if response.GetErrorCode() == constant.NO_HANDLER {
+		return errFuzzyWatchUnsupported
+	}
+	if !response.IsSuccess() {
+		return errors.Errorf("ConfigFuzzyWatchRequest failure, error code:%d, message:%s", response.GetErrorCode(),
+			response.GetMessage())
+	}
+	return nil
+}
+
+func (client *ConfigClient) searchFuzzyWatcher(watcher *fuzzyWatcher) {
+	// blur search only knows "*", the result is filtered by the exact pattern below
+	param := vo.SearchConfigParam{
+		Search:   "blur",
+		DataId:   strings.ReplaceAll(watcher.dataIdPattern, "?", "*"),
+		Group:    strings.ReplaceAll(watcher.groupPattern, "?", "*"),
+		PageSize: fuzzyWatchSearchPageSize,
+	}
+	groupKeys := make(map[string]struct{})
+	var err error
+	client.SearchConfigAllWithContext(client.ctx, param)(func(item model.ConfigItem, itemErr error) bool {
+		if itemErr != nil {
+			err = itemErr
+			return false
+		}
+		if watcher.matches(item.DataId, item.Group) {
+			groupKeys[getGroupKey(item.DataId, item.Group, watcher.tenant)] = struct{}{}
+		}
+		return true
+	})
+	if err != nil {
+		logger.Warnf("search configs of fuzzy watch pattern=%s failed, err:%v", watcher.groupKeyPattern(), err)
+		return
+	}
+	for groupKey := range groupKeys {
+		client.onFuzzyConfigChanged(watcher, groupKey, constant.FUZZY_WATCH_ADD_CONFIG)
+	}
+	for _, groupKey := range watcher.receivedGroupKeys() {
+		if _, ok := groupKeys[groupKey]; !ok {
+			client.onFuzzyConfigChanged(watcher, groupKey, constant.FUZZY_WATCH_DELETE_CONFIG)
+		}
+	}
+}
+
+// onFuzzyConfigChanged handles a config entering or leaving the matching set of a watcher. The content is
+// tracked by the listen task, which reports added and deleted configs to the watcher through executeListener.
+func (client *ConfigClient) onFuzzyConfigChanged(watcher *fuzzyWatcher, groupKey, changeType string) {
+	dataId, group, _, err := parseGroupKey(groupKey)
+	if err != nil {
+		logger.Warnf("fuzzy watch received invalid groupKey:%s", groupKey)
+		return
+	}
+	// the namespace of the key sent by server may be spelled differently, use the one of this client
+	groupKey = getGroupKey(dataId, group, watcher.tenant)
+	key := util.GetConfigCacheKey(dataId, group, watcher.tenant)
+	switch changeType {
+	case constant.FUZZY_WATCH_ADD_CONFIG:
+		if !watcher.addGroupKey(groupKey) {
+			return
+		}
+		added := client.cacheMap.SetIfAbsent(key, cacheData{
+			isInitializing:    true,
+			dataId:            dataId,
+			group:             group,
+			tenant:            watcher.tenant,
+			cacheDataListener: &cacheDataListener{},
+			taskId:            client.cacheMap.Count() / perTaskConfigSize,
+			configClient:      client,
+		})
+		if added {
+			client.asyncNotifyListenConfig()
+			return
+		}
+		// the config is listened already and its content is known, the listen task won't report it as added
+		if v, ok := client.cacheMap.Get(key); ok && len(v.(cacheData).md5) > 0 {
+			cData := v.(cacheData)
+			content, err := cData.decryptContent(cData.content, cData.encryptedDataKey)
+			if err != nil {
+				logger.Errorf("do filters failed ,dataId=%s,group=%s,tenant=%s,err:%+v ", dataId, group, watcher.tenant, err)
+				return
+			}
+			event := cData.buildChangeEvent("", "", content)
+			client.dispatchListener(watcher.dispatchKey(key), event, true, watcher.listener)
+		}
+	case constant.FUZZY_WATCH_DELETE_CONFIG:
+		if !watcher.removeGroupKey(groupKey) {
+			return
+		}
+		if v, ok := client.cacheMap.Get(key); ok {
+			cData := v.(cacheData)
+			cData.isSyncWithServer = false
+			client.cacheMap.Set(key, cData)
+			client.asyncNotifyListenConfig()
+		}
+	default:
+		logger.Warnf("fuzzy watch received unknown changeType:%s, groupKey:%s", changeType, groupKey)
+	}
+}
+
+// onFuzzyWatchNotify applies a change pushed by server to every watcher matching the config.
+func (client *ConfigClient) onFuzzyWatchNotify(groupKeyPattern, groupKey, changeType string) {
+	if len(groupKeyPattern) > 0 {
+		if v, ok := client.fuzzyWatchers.Get(groupKeyPattern); ok {
+			client.onFuzzyConfigChanged(v.(*fuzzyWatcher), groupKey, changeType)
+		}
+		return
+	}
+	dataId, group, _, err := parseGroupKey(groupKey)
+	if err != nil {
+		logger.Warnf("fuzzy watch received invalid groupKey:%s", groupKey)
+		return
+	}
+	for _, v := range client.fuzzyWatchers.Items() {
+		if watcher := v.(*fuzzyWatcher); watcher.matches(dataId, group) {
+			client.onFuzzyConfigChanged(watcher, groupKey, changeType)
+		}
+	}
+}
+
+func (client *ConfigClient) notifyFuzzyWatchers(event *model.ConfigChangeEvent, isFuzzyOnly bool) {
+	groupKey := getGroupKey(event.DataId, event.Group, event.Namespace)
+	cacheKey := util.GetConfigCacheKey(event.DataId, event.Group, event.Namespace)
+	for _, v := range client.fuzzyWatchers.Items() {
+		watcher := v.(*fuzzyWatcher)
+		if !watcher.matches(event.DataId, event.Group) {
+			continue
+		}
+		if event.ChangeType == model.ConfigDeleted {
+			watcher.removeGroupKey(groupKey)
+		} else {
+			watcher.addGroupKey(groupKey)
+		}
+		client.dispatchListener(watcher.dispatchKey(cacheKey), event, true, watcher.listener)
+	}
+	if isFuzzyOnly && event.ChangeType == model.ConfigDeleted {
+		client.cacheMap.Remove(cacheKey)
+	}
+}
+
+// hasFuzzyWatchers also copes with clients that were not built by NewConfigClient.
+func (client *ConfigClient) hasFuzzyWatchers() bool {
+	return len(client.fuzzyWatchers) > 0 && !client.fuzzyWatchers.IsEmpty()
+}
+
+func (client *ConfigClient) isFuzzyWatched(dataId, group string) bool {
+	if !client.hasFuzzyWatchers() {
+		return false
+	}
+	for _, v := range client.fuzzyWatchers.Items() {
+		if v.(*fuzzyWatcher).matches(dataId, group) {
+			return true
+		}
+	}
+	return false
+}
+
+func getGroupKeyPattern(dataIdPattern, groupPattern, tenant string) string {
+	if len(tenant) == 0 {
+		tenant = constant.DEFAULT_NAMESPACE_ID
+	}
+	return tenant + constant.FUZZY_WATCH_PATTERN_SPLITTER + groupPattern + constant.FUZZY_WATCH_PATTERN_SPLITTER + dataIdPattern
+}
+
+// getGroupKey builds the config key used by server, dataId+group+tenant with '+' and '%' escaped.
+func getGroupKey(dataId, group, tenant string) string {
+	groupKey := groupKeyEncoder.Replace(dataId) + "+" + groupKeyEncoder.Replace(group)
+	if len(tenant) > 0 {
+		groupKey += "+" + groupKeyEncoder.Replace(tenant)
+	}
+	return groupKey
+}
+
+func parseGroupKey(groupKey string) (dataId, group, tenant string, err error) {
+	parts := strings.Split(groupKey, "+")
+	if len(parts) < 2 || len(parts) > 3 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", "", errors.Errorf("invalid groupKey:%s", groupKey)
+	}
+	dataId, group = groupKeyDecoder.Replace(parts[0]), groupKeyDecoder.Replace(parts[1])
+	if len(parts) == 3 {
+		tenant = groupKeyDecoder.Replace(parts[2])
+	}
+	return dataId, group, tenant, nil
+}
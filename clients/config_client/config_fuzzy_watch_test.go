@@ -0,0 +1,198 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_request"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_response"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockConfigProxyWithoutFuzzyWatch acts like a nacos 2.x server holding the configs in contents.
+type MockConfigProxyWithoutFuzzyWatch struct {
+	MockConfigProxy
+	mux      sync.Mutex
+	contents map[string]string
+}
+
+func (m *MockConfigProxyWithoutFuzzyWatch) setContent(dataId, content string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.contents[dataId] = content
+}
+
+func (m *MockConfigProxyWithoutFuzzyWatch) removeContent(dataId string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	delete(m.contents, dataId)
+}
+
+func (m *MockConfigProxyWithoutFuzzyWatch) queryConfig(ctx context.Context, dataId, group, tenant, tag string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	content, ok := m.contents[dataId]
+	if !ok {
+		return &rpc_response.ConfigQueryResponse{Response: &rpc_response.Response{Success: true, ErrorCode: 300}}, nil
+	}
+	return &rpc_response.ConfigQueryResponse{Content: content, Response: &rpc_response.Response{Success: true}}, nil
+}
+
+func (m *MockConfigProxyWithoutFuzzyWatch) searchConfigProxy(ctx context.Context, param vo.SearchConfigParam, tenant, accessKey, secretKey string) (*model.ConfigPage, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	page := &model.ConfigPage{PageNumber: param.PageNo, PagesAvailable: 1}
+	for dataId := range m.contents {
+		page.PageItems = append(page.PageItems, model.ConfigItem{DataId: dataId, Group: "FUZZY_GROUP"})
+	}
+	page.TotalCount = len(page.PageItems)
+	return page, nil
+}
+
+func (m *MockConfigProxyWithoutFuzzyWatch) requestProxy(ctx context.Context, rpcClient *rpc.RpcClient, request rpc_request.IRequest, timeoutMills uint64) (rpc_response.IResponse, error) {
+	if request.GetRequestType() == constant.CONFIG_FUZZY_WATCH_REQUEST_NAME {
+		return &rpc_response.ErrorResponse{Response: &rpc_response.Response{ErrorCode: constant.NO_HANDLER}}, nil
+	}
+	return m.MockConfigProxy.requestProxy(ctx, rpcClient, request, timeoutMills)
+}
+
+func receiveChangeEvent(t *testing.T, events chan *model.ConfigChangeEvent) *model.ConfigChangeEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no change event received")
+		return nil
+	}
+}
+
+func TestFuzzyListenConfig_FallbackToSearch(t *testing.T) {
+	proxy := &MockConfigProxyWithoutFuzzyWatch{contents: map[string]string{
+		"tenant-a.yaml": "a: 1",
+		"tenant-b.yaml": "b: 1",
+		"other.yaml":    "c: 1",
+	}}
+	client := createConfigClientTest()
+	client.configProxy = proxy
+	clientConfig, _ := client.GetClientConfig()
+	cacheKey := func(dataId string) string {
+		return util.GetConfigCacheKey(dataId, "FUZZY_GROUP", clientConfig.NamespaceId)
+	}
+	refresh := func(dataId string) {
+		data, ok := client.cacheMap.Get(cacheKey(dataId))
+		assert.True(t, ok)
+		client.refreshContentAndCheck(data.(cacheData), false)
+	}
+
+	events := make(chan *model.ConfigChangeEvent, 4)
+	param := vo.FuzzyListenConfigParam{
+		DataIdPattern: "tenant-*.yaml",
+		GroupPattern:  "FUZZY_GROUP",
+		OnChangeEvent: func(event *model.ConfigChangeEvent) {
+			events <- event
+		},
+	}
+	assert.Nil(t, client.FuzzyListenConfig(param))
+	assert.NotNil(t, client.FuzzyListenConfig(param))
+	assert.Eventually(t, func() bool {
+		return client.cacheMap.Has(cacheKey("tenant-a.yaml")) && client.cacheMap.Has(cacheKey("tenant-b.yaml"))
+	}, time.Second, 10*time.Millisecond)
+	assert.False(t, client.cacheMap.Has(cacheKey("other.yaml")))
+
+	refresh("tenant-a.yaml")
+	event := receiveChangeEvent(t, events)
+	assert.Equal(t, "tenant-a.yaml", event.DataId)
+	assert.Equal(t, model.ConfigAdded, event.ChangeType)
+	assert.Equal(t, "a: 1", event.NewContent)
+
+	proxy.setContent("tenant-a.yaml", "a: 2")
+	refresh("tenant-a.yaml")
+	event = receiveChangeEvent(t, events)
+	assert.Equal(t, model.ConfigModified, event.ChangeType)
+	assert.Equal(t, []model.ConfigChangeItem{{Key: "a", OldValue: "1", NewValue: "2", Type: model.ConfigModified}}, event.ChangedItems)
+
+	refresh("tenant-b.yaml")
+	assert.Equal(t, model.ConfigAdded, receiveChangeEvent(t, events).ChangeType)
+	proxy.removeContent("tenant-b.yaml")
+	value, _ := client.fuzzyWatchers.Get(getGroupKeyPattern(param.DataIdPattern, param.GroupPattern, clientConfig.NamespaceId))
+	client.syncFuzzyWatcher(value.(*fuzzyWatcher))
+	refresh("tenant-b.yaml")
+	event = receiveChangeEvent(t, events)
+	assert.Equal(t, "tenant-b.yaml", event.DataId)
+	assert.Equal(t, model.ConfigDeleted, event.ChangeType)
+	assert.False(t, client.cacheMap.Has(cacheKey("tenant-b.yaml")))
+
+	assert.Nil(t, client.CancelFuzzyListenConfig(param))
+	assert.False(t, client.cacheMap.Has(cacheKey("tenant-a.yaml")))
+}
+
+func TestFuzzyListenConfig_ServerPush(t *testing.T) {
+	client := createConfigClientTest()
+	clientConfig, _ := client.GetClientConfig()
+	param := vo.FuzzyListenConfigParam{
+		DataIdPattern: "push-*",
+		GroupPattern:  "FUZZY_GROUP",
+		OnChangeEvent: func(event *model.ConfigChangeEvent) {},
+	}
+	assert.Nil(t, client.FuzzyListenConfig(param))
+	pattern := getGroupKeyPattern(param.DataIdPattern, param.GroupPattern, clientConfig.NamespaceId)
+	value, _ := client.fuzzyWatchers.Get(pattern)
+	watcher := value.(*fuzzyWatcher)
+	assert.Eventually(t, func() bool {
+		watcher.mux.Lock()
+		defer watcher.mux.Unlock()
+		return watcher.serverWatching
+	}, time.Second, 10*time.Millisecond)
+
+	syncRequest := rpc_request.NewConfigFuzzyWatchSyncRequest()
+	syncRequest.GroupKeyPattern = pattern
+	syncRequest.Contexts = []rpc_request.FuzzyWatchSyncContext{
+		{GroupKey: getGroupKey("push-1", "FUZZY_GROUP", clientConfig.NamespaceId), ChangedType: constant.FUZZY_WATCH_ADD_CONFIG},
+	}
+	response := (&ConfigFuzzyWatchSyncRequestHandler{client: client}).RequestReply(syncRequest, &rpc.RpcClient{})
+	assert.True(t, response.IsSuccess())
+	key := util.GetConfigCacheKey("push-1", "FUZZY_GROUP", clientConfig.NamespaceId)
+	assert.True(t, client.cacheMap.Has(key))
+
+	notifyRequest := rpc_request.NewConfigFuzzyWatchChangeNotifyRequest()
+	notifyRequest.GroupKey = getGroupKey("push-1", "FUZZY_GROUP", clientConfig.NamespaceId)
+	notifyRequest.ChangeType = constant.FUZZY_WATCH_DELETE_CONFIG
+	response = (&ConfigFuzzyWatchChangeNotifyRequestHandler{client: client}).RequestReply(notifyRequest, &rpc.RpcClient{})
+	assert.True(t, response.IsSuccess())
+	assert.Empty(t, watcher.receivedGroupKeys())
+	assert.Nil(t, client.CancelFuzzyListenConfig(param))
+}
+
+func TestGroupKey(t *testing.T) {
+	groupKey := getGroupKey("a+b%c", "group", "tenant")
+	assert.Equal(t, "a%2Bb%25c+group+tenant", groupKey)
+	dataId, group, tenant, err := parseGroupKey(groupKey)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a+b%c", "group", "tenant"}, []string{dataId, group, tenant})
+
+	_, _, _, err = parseGroupKey("dataId")
+	assert.NotNil(t, err)
+}
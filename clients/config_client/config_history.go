@@ -0,0 +1,137 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+)
+
+func (client *ConfigClient) ListConfigHistory(param vo.ConfigParam, page vo.PageParam) (*model.ConfigHistoryPage, error) {
+	return client.ListConfigHistoryWithContext(context.Background(), param, page)
+}
+
+// ListConfigHistoryWithContext returns the revisions of a config, latest first.
+func (client *ConfigClient) ListConfigHistoryWithContext(ctx context.Context, param vo.ConfigParam, page vo.PageParam) (*model.ConfigHistoryPage, error) {
+	if len(param.DataId) <= 0 {
+		return nil, errors.New("[client.ListConfigHistory] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		param.Group = constant.DEFAULT_GROUP
+	}
+	if page.PageNo <= 0 {
+		page.PageNo = 1
+	}
+	if page.PageSize <= 0 {
+		page.PageSize = 10
+	}
+	clientConfig, _ := client.GetClientConfig()
+	return client.configProxy.listConfigHistoryProxy(ctx, param.DataId, param.Group, clientConfig.NamespaceId, page)
+}
+
+func (client *ConfigClient) GetConfigHistory(param vo.ConfigParam, historyId string) (*model.ConfigHistoryItem, error) {
+	return client.GetConfigHistoryWithContext(context.Background(), param, historyId)
+}
+
+// GetConfigHistoryWithContext returns a single revision including its content. The content is returned as stored,
+// filters such as decryption are not applied.
+func (client *ConfigClient) GetConfigHistoryWithContext(ctx context.Context, param vo.ConfigParam, historyId string) (*model.ConfigHistoryItem, error) {
+	if len(param.DataId) <= 0 {
+		return nil, errors.New("[client.GetConfigHistory] param.dataId can not be empty")
+	}
+	if len(historyId) <= 0 {
+		return nil, errors.New("[client.GetConfigHistory] historyId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		param.Group = constant.DEFAULT_GROUP
+	}
+	clientConfig, _ := client.GetClientConfig()
+	return client.configProxy.getConfigHistoryProxy(ctx, param.DataId, param.Group, clientConfig.NamespaceId, historyId)
+}
+
+func (client *ConfigClient) RollbackConfig(param vo.ConfigParam, historyId string) (bool, error) {
+	return client.RollbackConfigWithContext(context.Background(), param, historyId)
+}
+
+// RollbackConfigWithContext publishes the content of a revision again. The publish is guarded by param.CasMd5, the
+// md5 of the content the caller decided to roll back from, or by the md5 of the current content when it's empty. So
+// it fails instead of overwriting the config when it was changed since. A config that doesn't exist anymore is
+// published again without the guard, unless param.CasMd5 is set.
+func (client *ConfigClient) RollbackConfigWithContext(ctx context.Context, param vo.ConfigParam, historyId string) (bool, error) {
+	if len(param.Group) <= 0 {
+		param.Group = constant.DEFAULT_GROUP
+	}
+	history, err := client.GetConfigHistoryWithContext(ctx, param, historyId)
+	if err != nil {
+		return false, err
+	}
+	if len(history.Content) <= 0 {
+		return false, errors.Errorf("[client.RollbackConfig] config history %s has no content", historyId)
+	}
+
+	clientConfig, _ := client.GetClientConfig()
+	current, err := client.configProxy.queryConfig(ctx, param.DataId, param.Group, clientConfig.NamespaceId, "",
+		clientConfig.TimeoutMs, false, client)
+	if err != nil {
+		return false, errors.Wrap(err, "[client.RollbackConfig] query current config failed")
+	}
+	if current != nil && current.Response != nil && !current.IsSuccess() {
+		return false, errors.Errorf("[client.RollbackConfig] query current config failed: %s", current.GetMessage())
+	}
+
+	// the history holds the stored content, it is decrypted here and encrypted again when published,
+	// its placeholders and secret references are published as they are
+	decrypted := &vo.ConfigParam{
+		DataId:           param.DataId,
+		Group:            param.Group,
+		Content:          history.Content,
+		EncryptedDataKey: history.EncryptedDataKey,
+		UsageType:        vo.ResponseType,
+	}
+	if err = client.doFiltersToPublishAgain(decrypted); err != nil {
+		return false, err
+	}
+
+	currentMd5 := current.Md5
+	if len(currentMd5) == 0 {
+		currentMd5 = util.Md5(current.Content)
+	}
+	if len(param.CasMd5) > 0 && param.CasMd5 != currentMd5 {
+		return false, errors.Errorf("[client.RollbackConfig] config dataId=%s, group=%s was modified, its md5 is %s instead of %s",
+			param.DataId, param.Group, currentMd5, param.CasMd5)
+	}
+	rollbackParam := param
+	rollbackParam.Content = decrypted.Content
+	if len(rollbackParam.CasMd5) <= 0 {
+		rollbackParam.CasMd5 = currentMd5
+	}
+	if len(rollbackParam.Type) <= 0 {
+		rollbackParam.Type = history.Type
+	}
+	if len(rollbackParam.AppName) <= 0 {
+		rollbackParam.AppName = history.AppName
+	}
+	logger.Infof("rollback config dataId=%s, group=%s, tenant=%s to history %s, casMd5=%s", param.DataId, param.Group,
+		clientConfig.NamespaceId, historyId, rollbackParam.CasMd5)
+	return client.PublishConfigWithContext(ctx, rollbackParam)
+}
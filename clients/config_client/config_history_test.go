@@ -0,0 +1,123 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_request"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListConfigHistory(t *testing.T) {
+	client := createConfigClientTest()
+	_, err := client.ListConfigHistory(vo.ConfigParam{}, vo.PageParam{})
+	assert.NotNil(t, err)
+
+	page, err := client.ListConfigHistory(vo.ConfigParam{DataId: "history", Group: "group"}, vo.PageParam{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, page.PageNumber)
+
+	item, err := client.GetConfigHistory(vo.ConfigParam{DataId: "history", Group: "group"}, "12")
+	assert.Nil(t, err)
+	assert.Equal(t, "12", item.Id.String())
+}
+
+func TestRollbackConfig(t *testing.T) {
+	proxy := &MockConfigProxyForBeta{}
+	client := createConfigClientTest()
+	client.configProxy = proxy
+
+	published, err := client.RollbackConfig(vo.ConfigParam{DataId: "history", Group: "group"}, "12")
+	assert.Nil(t, err)
+	assert.True(t, published)
+	request := proxy.request.(*rpc_request.ConfigPublishRequest)
+	assert.Equal(t, "history", request.Content)
+	// the md5 of the content returned by queryConfig of the mock
+	assert.Equal(t, util.Md5("gray=true"), request.CasMd5)
+}
+
+func TestRollbackConfig_CasMd5(t *testing.T) {
+	proxy := &MockConfigProxyForBeta{}
+	client := createConfigClientTest()
+	client.configProxy = proxy
+
+	// the config was edited after the caller read it
+	published, err := client.RollbackConfig(vo.ConfigParam{DataId: "history", Group: "group", CasMd5: util.Md5("gray=false")}, "12")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "was modified")
+	assert.False(t, published)
+	assert.Nil(t, proxy.request)
+
+	published, err = client.RollbackConfig(vo.ConfigParam{DataId: "history", Group: "group", CasMd5: util.Md5("gray=true")}, "12")
+	assert.Nil(t, err)
+	assert.True(t, published)
+	assert.Equal(t, util.Md5("gray=true"), proxy.request.(*rpc_request.ConfigPublishRequest).CasMd5)
+}
+
+func TestConfigProxy_History(t *testing.T) {
+	proxy := newConfigProxyForHttpTest(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/nacos/v1/cs/history", r.URL.Path)
+		if r.URL.Query().Get("nid") != "" {
+			_, _ = w.Write([]byte(`{"id":"12","dataId":"history","group":"group","content":"a=1","md5":"m","srcIp":"10.0.0.1",` +
+				`"srcUser":"nacos","opType":"U         ","createdTime":"2024-05-01T10:00:00.000+00:00","lastModifiedTime":"2024-05-01T10:00:01.000+00:00"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"totalCount":1,"pageNumber":1,"pagesAvailable":1,"pageItems":[{"id":"12","dataId":"history","group":"group","opType":"U"}]}`))
+	})
+
+	page, err := proxy.listConfigHistoryProxy(context.Background(), "history", "group", "", vo.PageParam{PageNo: 1, PageSize: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, page.TotalCount)
+	assert.Equal(t, "U", page.PageItems[0].OpType)
+
+	item, err := proxy.getConfigHistoryProxy(context.Background(), "history", "group", "", "12")
+	assert.Nil(t, err)
+	assert.Equal(t, "a=1", item.Content)
+	assert.Equal(t, "U", item.OpType)
+	assert.Equal(t, "nacos", item.SrcUser)
+	assert.True(t, item.CreatedTime.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+}
+
+func TestConfigProxy_HistoryFallbackToV3(t *testing.T) {
+	proxy := newConfigProxyForHttpTest(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/nacos/v3/admin/cs/history":
+			_, _ = w.Write([]byte(`{"code":0,"message":"success","data":{"id":12,"dataId":"history","groupName":"group",` +
+				`"namespaceId":"public","content":"a=1","opType":"D","createTime":1714557600000}}`))
+		case "/nacos/v3/admin/cs/history/list":
+			_, _ = w.Write([]byte(`{"code":0,"message":"success","data":{"totalCount":1,"pageNumber":1,"pagesAvailable":1,` +
+				`"pageItems":[{"id":12,"dataId":"history","groupName":"group","opType":"D"}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	page, err := proxy.listConfigHistoryProxy(context.Background(), "history", "group", "", vo.PageParam{PageNo: 1, PageSize: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, "group", page.PageItems[0].Group)
+
+	item, err := proxy.getConfigHistoryProxy(context.Background(), "history", "group", "", "12")
+	assert.Nil(t, err)
+	assert.Equal(t, "public", item.Tenant)
+	assert.Equal(t, int64(1714557600000), item.CreatedTime.UnixMilli())
+}
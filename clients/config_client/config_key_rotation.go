@@ -0,0 +1,250 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	nacos_inner_encryption "github.com/nacos-group/nacos-sdk-go/v2/common/encryption"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/filter"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+)
+
+func (client *ConfigClient) RotateConfigKey(param vo.RotateConfigKeyParam) (*model.ConfigKeyRotationReport, error) {
+	return client.RotateConfigKeyWithContext(context.Background(), param)
+}
+
+// RotateConfigKeyWithContext encrypts the cipher- configs of the namespace again with a data key of param.NewKeyId.
+// Each config is read from the server, decrypted with its current data key and published again, guarded by the md5
+// of the content read, so a config changed in the meantime fails rather than being overwritten. The configs are
+// decrypted with the key they were encrypted with, so they stay readable while the rotation is in progress.
+//
+// With param.ReportFile set the report is saved after each config, running the rotation again with the same
+// NewKeyId skips the configs rotated or skipped already and retries the failed ones. An error is returned when
+// configs failed or the rotation was interrupted, the report tells which.
+func (client *ConfigClient) RotateConfigKeyWithContext(ctx context.Context, param vo.RotateConfigKeyParam) (*model.ConfigKeyRotationReport, error) {
+	if len(strings.TrimSpace(param.NewKeyId)) == 0 {
+		return nil, errors.New("[client.RotateConfigKey] param.newKeyId can not be empty")
+	}
+	if !client.hasConfigFilter(filter.EncryptionFilterName) {
+		return nil, errors.New("[client.RotateConfigKey] config encryption is not enabled on the client")
+	}
+	if len(param.DataId) == 0 {
+		param.DataId = nacos_inner_encryption.CipherPrefix + "*"
+	}
+	clientConfig, _ := client.GetClientConfig()
+	report, err := loadConfigKeyRotationReport(param.ReportFile, clientConfig.NamespaceId, param.NewKeyId)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[string]int, len(report.Items))
+	for i, item := range report.Items {
+		done[util.GetConfigCacheKey(item.DataId, item.Group, "")] = i
+	}
+
+	// the configs are listed before any is published again, so the publishes can't move them across the pages
+	var targets []model.ConfigItem
+	client.SearchConfigAllWithContext(ctx, vo.SearchConfigParam{
+		Search: "blur",
+		DataId: param.DataId,
+		Group:  param.Group,
+	})(func(item model.ConfigItem, searchErr error) bool {
+		if searchErr != nil {
+			err = searchErr
+			return false
+		}
+		targets = append(targets, item)
+		return true
+	})
+	if err != nil {
+		return report, errors.Wrap(err, "[client.RotateConfigKey] search configs failed")
+	}
+
+	for _, target := range targets {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+		key := util.GetConfigCacheKey(target.DataId, target.Group, "")
+		index, found := done[key]
+		if found && report.Items[index].Status != model.ConfigKeyFailed {
+			continue
+		}
+		item := client.rotateConfigKey(ctx, target.DataId, target.Group, param.NewKeyId)
+		if found {
+			report.Items[index] = item
+		} else {
+			done[key] = len(report.Items)
+			report.Items = append(report.Items, item)
+		}
+		logger.Infof("rotate key of config dataId=%s, group=%s, tenant=%s to %s: %s %s", item.DataId, item.Group,
+			clientConfig.NamespaceId, param.NewKeyId, item.Status, item.Message)
+		if err = saveConfigKeyRotationReport(param.ReportFile, report); err != nil {
+			return report, err
+		}
+		if param.OnProgress != nil {
+			param.OnProgress(item)
+		}
+	}
+	report.EndTime = time.Now()
+	if err = saveConfigKeyRotationReport(param.ReportFile, report); err != nil {
+		return report, err
+	}
+	if failed := report.Count(model.ConfigKeyFailed); failed > 0 {
+		return report, errors.Errorf("[client.RotateConfigKey] %d of %d configs failed to rotate", failed, len(report.Items))
+	}
+	return report, nil
+}
+
+func (client *ConfigClient) rotateConfigKey(ctx context.Context, dataId, group, newKeyId string) model.ConfigKeyRotationItem {
+	item := model.ConfigKeyRotationItem{DataId: dataId, Group: group, Status: model.ConfigKeyFailed}
+	defer func() {
+		item.Time = time.Now()
+	}()
+	if !strings.HasPrefix(dataId, nacos_inner_encryption.CipherPrefix) {
+		item.Status, item.Message = model.ConfigKeySkipped, "not an encrypted config"
+		return item
+	}
+	clientConfig, _ := client.GetClientConfig()
+	current, err := client.configProxy.queryConfig(ctx, dataId, group, clientConfig.NamespaceId, "",
+		clientConfig.TimeoutMs, false, client)
+	if err == nil && current.Response != nil && !current.IsSuccess() {
+		err = errors.New(current.GetMessage())
+	}
+	if err != nil {
+		item.Message = "query config failed: " + err.Error()
+		return item
+	}
+	if len(current.Content) == 0 {
+		item.Status, item.Message = model.ConfigKeySkipped, "config not found"
+		return item
+	}
+
+	// a publish overwrites the metadata, it is published again as it is
+	metadata, err := client.configProxy.queryConfigMetadataProxy(ctx, dataId, group, clientConfig.NamespaceId)
+	if err != nil {
+		item.Message = "query config metadata failed: " + err.Error()
+		return item
+	}
+	contentType := current.ContentType
+	if len(contentType) == 0 {
+		contentType = metadata.Type
+	}
+
+	// the content is published again as stored, its placeholders and secret references aren't resolved
+	decrypted := &vo.ConfigParam{
+		DataId:           dataId,
+		Group:            group,
+		Content:          current.Content,
+		EncryptedDataKey: current.EncryptedDataKey,
+		Type:             contentType,
+		UsageType:        vo.ResponseType,
+	}
+	if err = client.doFiltersToPublishAgain(decrypted); err != nil {
+		item.Message = "read config failed: " + err.Error()
+		return item
+	}
+	casMd5 := current.Md5
+	if len(casMd5) == 0 {
+		casMd5 = util.Md5(current.Content)
+	}
+	published, err := client.PublishConfigWithContext(ctx, vo.ConfigParam{
+		DataId:     dataId,
+		Group:      group,
+		Content:    decrypted.Content,
+		Type:       contentType,
+		AppName:    metadata.AppName,
+		Desc:       metadata.Desc,
+		ConfigTags: metadata.ConfigTags,
+		KmsKeyId:   newKeyId,
+		CasMd5:     casMd5,
+	})
+	if err == nil && !published {
+		err = errors.New("config not published")
+	}
+	if err != nil {
+		item.Message = "publish config failed: " + err.Error()
+		return item
+	}
+	item.Status = model.ConfigKeyRotated
+	return item
+}
+
+func (client *ConfigClient) hasConfigFilter(name string) bool {
+	for _, configFilter := range client.configFilterChainManager.GetFilters() {
+		if configFilter.GetFilterName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// loadConfigKeyRotationReport reads the report of an earlier run of the same rotation, or starts a new one.
+func loadConfigKeyRotationReport(reportFile, namespace, newKeyId string) (*model.ConfigKeyRotationReport, error) {
+	report := &model.ConfigKeyRotationReport{Namespace: namespace, NewKeyId: newKeyId, StartTime: time.Now()}
+	if len(reportFile) == 0 {
+		return report, nil
+	}
+	data, err := os.ReadFile(reportFile)
+	if os.IsNotExist(err) {
+		return report, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "[client.RotateConfigKey] read report failed")
+	}
+	if err = json.Unmarshal(data, report); err != nil {
+		return nil, errors.Wrap(err, "[client.RotateConfigKey] parse report failed")
+	}
+	if report.NewKeyId != newKeyId || report.Namespace != namespace {
+		return nil, errors.Errorf("[client.RotateConfigKey] report %s belongs to the rotation of namespace [%s] to %s",
+			reportFile, report.Namespace, report.NewKeyId)
+	}
+	report.EndTime = time.Time{}
+	return report, nil
+}
+
+// saveConfigKeyRotationReport replaces the report file at once, so an interruption never leaves it half written.
+func saveConfigKeyRotationReport(reportFile string, report *model.ConfigKeyRotationReport) error {
+	if len(reportFile) == 0 {
+		return nil
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(reportFile), filepath.Base(reportFile)+".*")
+	if err != nil {
+		return errors.Wrap(err, "[client.RotateConfigKey] write report failed")
+	}
+	defer os.Remove(temp.Name())
+	if _, err = temp.Write(data); err != nil {
+		temp.Close()
+		return errors.Wrap(err, "[client.RotateConfigKey] write report failed")
+	}
+	if err = temp.Close(); err != nil {
+		return errors.Wrap(err, "[client.RotateConfigKey] write report failed")
+	}
+	return errors.Wrap(os.Rename(temp.Name(), reportFile), "[client.RotateConfigKey] write report failed")
+}
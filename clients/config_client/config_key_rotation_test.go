@@ -0,0 +1,335 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/nacos_client"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	nacos_inner_encryption "github.com/nacos-group/nacos-sdk-go/v2/common/encryption"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/filter"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_request"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_response"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type storedConfig struct {
+	content          string
+	encryptedDataKey string
+	appName          string
+	desc             string
+	configTags       string
+}
+
+// MockConfigProxyWithStore keeps the configs published and enforces their cas md5
+type MockConfigProxyWithStore struct {
+	MockConfigProxy
+	mux       sync.Mutex
+	configs   map[string]*storedConfig
+	publishes map[string]int
+	// changeOnQuery is a dataId published by someone else with changedTo right after it's queried
+	changeOnQuery string
+	changedTo     storedConfig
+}
+
+func newMockConfigProxyWithStore() *MockConfigProxyWithStore {
+	return &MockConfigProxyWithStore{configs: map[string]*storedConfig{}, publishes: map[string]int{}}
+}
+
+func (m *MockConfigProxyWithStore) queryConfig(ctx context.Context, dataId, group, tenant, tag string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	config, ok := m.configs[dataId]
+	if !ok {
+		return &rpc_response.ConfigQueryResponse{Response: &rpc_response.Response{Success: true}}, nil
+	}
+	response := &rpc_response.ConfigQueryResponse{Content: config.content, EncryptedDataKey: config.encryptedDataKey,
+		Md5: util.Md5(config.content), Response: &rpc_response.Response{Success: true}}
+	if dataId == m.changeOnQuery {
+		changed := m.changedTo
+		m.configs[dataId] = &changed
+	}
+	return response, nil
+}
+
+func (m *MockConfigProxyWithStore) queryConfigMetadataProxy(ctx context.Context, dataId, group, tenant string) (*model.ConfigMetadata, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	config, ok := m.configs[dataId]
+	if !ok {
+		return nil, errors.New("config not found")
+	}
+	return &model.ConfigMetadata{DataId: dataId, Group: group, Tenant: tenant, AppName: config.appName,
+		Desc: config.desc, ConfigTags: config.configTags}, nil
+}
+
+func (m *MockConfigProxyWithStore) searchConfigProxy(ctx context.Context, param vo.SearchConfigParam, tenant, accessKey, secretKey string) (*model.ConfigPage, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	page := &model.ConfigPage{PageNumber: 1, PagesAvailable: 1}
+	for dataId, config := range m.configs {
+		if strings.HasPrefix(dataId, strings.TrimSuffix(param.DataId, "*")) {
+			page.PageItems = append(page.PageItems, model.ConfigItem{DataId: dataId, Group: localConfigTest.Group, Content: config.content})
+		}
+	}
+	page.TotalCount = len(page.PageItems)
+	return page, nil
+}
+
+func (m *MockConfigProxyWithStore) requestProxy(ctx context.Context, rpcClient *rpc.RpcClient, request rpc_request.IRequest, timeoutMills uint64) (rpc_response.IResponse, error) {
+	publish, ok := request.(*rpc_request.ConfigPublishRequest)
+	if !ok {
+		return m.MockConfigProxy.requestProxy(ctx, rpcClient, request, timeoutMills)
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if current, exists := m.configs[publish.DataId]; exists && len(publish.CasMd5) > 0 && util.Md5(current.content) != publish.CasMd5 {
+		return &rpc_response.ConfigPublishResponse{Response: &rpc_response.Response{Success: false, Message: "cas md5 mismatch"}}, nil
+	}
+	m.configs[publish.DataId] = &storedConfig{content: publish.Content, encryptedDataKey: publish.AdditionMap["encryptedDataKey"],
+		appName: publish.AdditionMap["appName"], desc: publish.AdditionMap["desc"], configTags: publish.AdditionMap["config_tags"]}
+	m.publishes[publish.DataId]++
+	return &rpc_response.ConfigPublishResponse{Response: &rpc_response.Response{Success: true}}, nil
+}
+
+func createConfigClientForRotation(t *testing.T) (*ConfigClient, *MockConfigProxyWithStore) {
+	keys := map[string]string{}
+	for _, keyId := range []string{"key-1", "key-2"} {
+		key, err := nacos_inner_encryption.GenerateLocalMasterKey()
+		assert.Nil(t, err)
+		keys[keyId] = key
+	}
+	keyring, _ := json.Marshal(map[string]interface{}{"primaryKeyId": "key-1", "keys": keys})
+	t.Setenv(nacos_inner_encryption.DefaultLocalKeyringEnv, string(keyring))
+	nc := nacos_client.NacosClient{}
+	_ = nc.SetServerConfig([]constant.ServerConfig{*serverConfigWithOptions})
+	_ = nc.SetClientConfig(*constant.NewClientConfig(
+		constant.WithNotLoadCacheAtStart(true),
+		constant.WithCacheDir(t.TempDir()),
+		constant.WithLocalKMSConfig(&constant.LocalKMSConfig{}),
+	))
+	_ = nc.SetHttpAgent(&http_agent.HttpAgent{})
+	client, err := NewConfigClient(&nc)
+	assert.Nil(t, err)
+	t.Cleanup(client.CloseClient)
+	proxy := newMockConfigProxyWithStore()
+	client.configProxy = proxy
+	for _, dataId := range []string{"cipher-local-aes-256-a", "cipher-local-aes-256-b", "cipher-local-aes-256-c"} {
+		published, err := client.PublishConfig(vo.ConfigParam{DataId: dataId, Group: localConfigTest.Group, Content: "secret of " + dataId,
+			AppName: "billing", Desc: "credentials of " + dataId, ConfigTags: "db,prod"})
+		assert.Nil(t, err)
+		assert.True(t, published)
+	}
+	proxy.publishes = map[string]int{}
+	return client, proxy
+}
+
+func assertConfigKeyId(t *testing.T, proxy *MockConfigProxyWithStore, dataId, keyId string) {
+	proxy.mux.Lock()
+	defer proxy.mux.Unlock()
+	assert.True(t, strings.HasPrefix(proxy.configs[dataId].encryptedDataKey, keyId+":"), dataId)
+}
+
+func TestRotateConfigKey(t *testing.T) {
+	client, proxy := createConfigClientForRotation(t)
+	assertConfigKeyId(t, proxy, "cipher-local-aes-256-a", "key-1")
+
+	var progress []model.ConfigKeyRotationItem
+	report, err := client.RotateConfigKey(vo.RotateConfigKeyParam{
+		NewKeyId:   "key-2",
+		OnProgress: func(item model.ConfigKeyRotationItem) { progress = append(progress, item) },
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, report.Count(model.ConfigKeyRotated))
+	assert.Len(t, progress, 3)
+	assert.False(t, report.EndTime.IsZero())
+	for _, dataId := range []string{"cipher-local-aes-256-a", "cipher-local-aes-256-b", "cipher-local-aes-256-c"} {
+		assertConfigKeyId(t, proxy, dataId, "key-2")
+		// the metadata survives the publish
+		assert.Equal(t, "billing", proxy.configs[dataId].appName)
+		assert.Equal(t, "credentials of "+dataId, proxy.configs[dataId].desc)
+		assert.Equal(t, "db,prod", proxy.configs[dataId].configTags)
+		content, err := client.GetConfig(vo.ConfigParam{DataId: dataId, Group: localConfigTest.Group})
+		assert.Nil(t, err)
+		assert.Equal(t, "secret of "+dataId, content)
+	}
+}
+
+func TestRotateConfigKey_Resume(t *testing.T) {
+	client, proxy := createConfigClientForRotation(t)
+	reportFile := filepath.Join(t.TempDir(), "rotation.json")
+	ctx, cancel := context.WithCancel(context.Background())
+	report, err := client.RotateConfigKeyWithContext(ctx, vo.RotateConfigKeyParam{
+		NewKeyId:   "key-2",
+		ReportFile: reportFile,
+		OnProgress: func(item model.ConfigKeyRotationItem) { cancel() },
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, report.Items, 1)
+	rotated := report.Items[0].DataId
+
+	data, err := os.ReadFile(reportFile)
+	assert.Nil(t, err)
+	var saved model.ConfigKeyRotationReport
+	assert.Nil(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, "key-2", saved.NewKeyId)
+	assert.Len(t, saved.Items, 1)
+
+	// the report belongs to the rotation to key-2
+	_, err = client.RotateConfigKey(vo.RotateConfigKeyParam{NewKeyId: "key-1", ReportFile: reportFile})
+	assert.NotNil(t, err)
+
+	report, err = client.RotateConfigKey(vo.RotateConfigKeyParam{NewKeyId: "key-2", ReportFile: reportFile})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, report.Count(model.ConfigKeyRotated))
+	assert.Equal(t, 1, proxy.publishes[rotated])
+	for dataId, count := range proxy.publishes {
+		assert.Equal(t, 1, count, dataId)
+		assertConfigKeyId(t, proxy, dataId, "key-2")
+	}
+}
+
+func TestRotateConfigKey_Conflict(t *testing.T) {
+	client, proxy := createConfigClientForRotation(t)
+	proxy.changeOnQuery = "cipher-local-aes-256-b"
+	proxy.changedTo = *proxy.configs["cipher-local-aes-256-c"]
+	reportFile := filepath.Join(t.TempDir(), "rotation.json")
+	report, err := client.RotateConfigKey(vo.RotateConfigKeyParam{NewKeyId: "key-2", ReportFile: reportFile})
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, report.Count(model.ConfigKeyRotated))
+	assert.Equal(t, 1, report.Count(model.ConfigKeyFailed))
+	assertConfigKeyId(t, proxy, "cipher-local-aes-256-b", "key-1")
+	// configs still encrypted with the former key are read during the rotation
+	content, err := client.GetConfig(vo.ConfigParam{DataId: "cipher-local-aes-256-b", Group: localConfigTest.Group})
+	assert.Nil(t, err)
+	assert.Equal(t, "secret of cipher-local-aes-256-c", content)
+
+	// the failed config is retried when the rotation is run again
+	proxy.changeOnQuery = ""
+	report, err = client.RotateConfigKey(vo.RotateConfigKeyParam{NewKeyId: "key-2", ReportFile: reportFile})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, report.Count(model.ConfigKeyRotated))
+	assert.Len(t, report.Items, 3)
+	assertConfigKeyId(t, proxy, "cipher-local-aes-256-b", "key-2")
+}
+
+// signStoredConfigs signs the configs as stored, as a client with the signing key would have published them
+func signStoredConfigs(t *testing.T, proxy *MockConfigProxyWithStore, signingKey ed25519.PrivateKey) {
+	signer, err := filter.NewConfigSignatureFilter(filter.WithSigningKey("release", signingKey))
+	assert.Nil(t, err)
+	proxy.mux.Lock()
+	defer proxy.mux.Unlock()
+	for dataId, config := range proxy.configs {
+		param := &vo.ConfigParam{DataId: dataId, Group: localConfigTest.Group, Content: config.content, UsageType: vo.RequestType}
+		assert.Nil(t, signer.DoFilter(param))
+		config.content = param.Content
+	}
+}
+
+func TestRotateConfigKey_Signed(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	client, proxy := createConfigClientForRotation(t)
+	signStoredConfigs(t, proxy, privateKey)
+	signatureFilter, err := filter.NewConfigSignatureFilter(filter.WithSigningKey("release", privateKey),
+		filter.WithTrustedKey("release", publicKey))
+	assert.Nil(t, err)
+	assert.Nil(t, client.RegisterConfigFilter(signatureFilter))
+
+	report, err := client.RotateConfigKey(vo.RotateConfigKeyParam{NewKeyId: "key-2"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, report.Count(model.ConfigKeyRotated))
+	assertConfigKeyId(t, proxy, "cipher-local-aes-256-a", "key-2")
+	assert.Contains(t, proxy.configs["cipher-local-aes-256-a"].content, "#nacos-signature:")
+	content, err := client.GetConfig(vo.ConfigParam{DataId: "cipher-local-aes-256-a", Group: localConfigTest.Group})
+	assert.Nil(t, err)
+	assert.Equal(t, "secret of cipher-local-aes-256-a", content)
+}
+
+func TestRotateConfigKey_VerifyOnly(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	client, proxy := createConfigClientForRotation(t)
+	signStoredConfigs(t, proxy, privateKey)
+	verifier, err := filter.NewConfigSignatureFilter(filter.WithTrustedKey("release", publicKey),
+		filter.WithSignatureVerifyMode(filter.SignatureReject))
+	assert.Nil(t, err)
+	assert.Nil(t, client.RegisterConfigFilter(verifier))
+
+	// the configs would be published again without their signature, which the readers reject
+	report, err := client.RotateConfigKey(vo.RotateConfigKeyParam{NewKeyId: "key-2"})
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, report.Count(model.ConfigKeyFailed))
+	assert.Contains(t, report.Items[0].Message, "signing key")
+	assert.Empty(t, proxy.publishes)
+	assertConfigKeyId(t, proxy, "cipher-local-aes-256-a", "key-1")
+	content, err := client.GetConfig(vo.ConfigParam{DataId: "cipher-local-aes-256-a", Group: localConfigTest.Group})
+	assert.Nil(t, err)
+	assert.Equal(t, "secret of cipher-local-aes-256-a", content)
+}
+
+func TestRotateConfigKey_Invalid(t *testing.T) {
+	// the client has no encryption filter without kms
+	_, err := createConfigClientTestTls().RotateConfigKey(vo.RotateConfigKeyParam{NewKeyId: "key-2"})
+	assert.NotNil(t, err)
+	client, _ := createConfigClientForRotation(t)
+	_, err = client.RotateConfigKey(vo.RotateConfigKeyParam{})
+	assert.NotNil(t, err)
+	// a config not encrypted is left alone
+	report, err := client.RotateConfigKey(vo.RotateConfigKeyParam{NewKeyId: "key-3", DataId: "cipher-local-aes-256-a"})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, report.Count(model.ConfigKeyFailed))
+	assert.Contains(t, report.Items[0].Message, "key-3")
+}
+
+func TestConfigProxy_ConfigMetadataFallbackToV3(t *testing.T) {
+	var v1Called int
+	proxy := newConfigProxyForHttpTest(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/nacos/v1/cs/configs":
+			v1Called++
+			assert.Equal(t, "all", r.URL.Query().Get("show"))
+			w.WriteHeader(http.StatusNotFound)
+		case "/nacos/v3/admin/cs/config":
+			assert.Equal(t, "group", r.URL.Query().Get("groupName"))
+			_, _ = w.Write([]byte(`{"code":0,"message":"success","data":{"dataId":"cipher-a","group":"group",` +
+				`"appName":"billing","desc":"credentials","configTags":"db,prod","type":"yaml"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	metadata, err := proxy.queryConfigMetadataProxy(context.Background(), "cipher-a", "group", "")
+	assert.Nil(t, err)
+	assert.True(t, v1Called > 0)
+	assert.Equal(t, model.ConfigMetadata{DataId: "cipher-a", Group: "group", AppName: "billing", Desc: "credentials",
+		ConfigTags: "db,prod", Type: "yaml"}, *metadata)
+}
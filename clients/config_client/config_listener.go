@@ -0,0 +1,328 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+)
+
+var listenerSeq int64
+
+// configListener is one listener of a config, added by ListenConfig, AddConfigListener or WatchConfig.
+// The listeners of a config share its cache entry, each one remembers the content it has seen last,
+// so that a listener added late is neither notified twice nor skipped.
+type configListener struct {
+	id            string
+	listenerId    string // param.ListenerId the listener was added with
+	onChange      vo.Listener
+	onChangeEvent vo.ConfigChangeListener
+	isWatch       bool
+	mux           sync.Mutex
+	lastMd5       string
+	lastContent   string
+}
+
+// ConfigListenerHandle identifies a listener added by AddConfigListener, see RemoveConfigListener.
+type ConfigListenerHandle struct {
+	key      string
+	dataId   string
+	group    string
+	listener *configListener
+}
+
+func newConfigListener(onChange vo.Listener, onChangeEvent vo.ConfigChangeListener) *configListener {
+	return &configListener{
+		id:            strconv.FormatInt(atomic.AddInt64(&listenerSeq, 1), 10),
+		onChange:      onChange,
+		onChangeEvent: onChangeEvent,
+	}
+}
+
+func (client *ConfigClient) AddConfigListener(param vo.ConfigParam) (*ConfigListenerHandle, error) {
+	return client.AddConfigListenerWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) AddConfigListenerWithContext(ctx context.Context, param vo.ConfigParam) (*ConfigListenerHandle, error) {
+	if param.OnChange == nil && param.OnChangeEvent == nil {
+		return nil, errors.New("[client.ListenConfig] OnChange or OnChangeEvent must be set")
+	}
+	listener := newConfigListener(param.OnChange, param.OnChangeEvent)
+	listener.listenerId = param.ListenerId
+	return client.addListener(ctx, param, listener)
+}
+
+// RemoveConfigListener removes the listener of handle only, the config stops being listened once its last
+// listener is gone.
+func (client *ConfigClient) RemoveConfigListener(handle *ConfigListenerHandle) error {
+	if handle == nil || handle.listener == nil {
+		return errors.New("[client.RemoveConfigListener] handle can not be nil")
+	}
+	if v, ok := client.cacheMap.Get(handle.key); ok {
+		v.(cacheData).cacheDataListener.removeListener(handle.listener)
+		client.removeCacheDataIfUnused(handle.key)
+	}
+	logger.Infof("Remove config listener DataId:%s Group:%s", handle.dataId, handle.group)
+	return nil
+}
+
+// addListener registers listener on the cache entry of the config, the entry is created if the config isn't
+// listened yet. With param.NotifyInitial the current value is fetched and handed to listener before it is
+// registered, so no change can reach listener earlier.
+func (client *ConfigClient) addListener(ctx context.Context, param vo.ConfigParam, listener *configListener) (*ConfigListenerHandle, error) {
+	if len(param.DataId) <= 0 {
+		return nil, errors.New("[client.ListenConfig] DataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return nil, errors.New("[client.ListenConfig] Group can not be empty")
+	}
+	clientConfig, err := client.GetClientConfig()
+	if err != nil {
+		return nil, errors.New("[checkConfigInfo.GetClientConfig] failed")
+	}
+
+	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId)
+	if v, ok := client.cacheMap.Get(key); ok && v.(cacheData).cacheDataListener.hasListenerId(listener.listenerId) {
+		return nil, errors.Errorf("[client.ListenConfig] listener %s of dataId=%s, group=%s exists already",
+			listener.listenerId, param.DataId, param.Group)
+	}
+	newData := client.newCacheData(key, param.DataId, param.Group, clientConfig.NamespaceId)
+	startData := newData
+	if v, ok := client.cacheMap.Get(key); ok {
+		startData = v.(cacheData)
+	}
+	if param.NotifyInitial {
+		initialParam := param
+		initialParam.Tag = ""
+		content, encryptedDataKey, err := client.getConfigInner(ctx, initialParam)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[client.ListenConfig] get initial value failed, dataId=%s, group=%s",
+				param.DataId, param.Group)
+		}
+		var md5Str string
+		if len(content) > 0 {
+			md5Str = util.Md5(content)
+		}
+		// a new entry starts from the fetched value too, rather than from the snapshot
+		newData.content, newData.encryptedDataKey, newData.md5 = content, encryptedDataKey, md5Str
+		newData.cacheDataListener.lastMd5 = md5Str
+		startData.content, startData.encryptedDataKey, startData.md5 = content, encryptedDataKey, md5Str
+	}
+	startContent, err := startData.decryptContent(startData.content, startData.encryptedDataKey)
+	if err != nil {
+		if param.NotifyInitial {
+			return nil, errors.Wrapf(err, "[client.ListenConfig] decrypt initial value failed, dataId=%s, group=%s",
+				param.DataId, param.Group)
+		}
+		logger.Warnf("decrypt snapshot of dataId=%s, group=%s failed, err:%v", param.DataId, param.Group, err)
+	}
+	listener.lastMd5, listener.lastContent = startData.md5, startContent
+	if param.NotifyInitial && len(startData.content) > 0 {
+		event := startData.buildChangeEvent("", "", startContent)
+		runListener(listener.dispatchKey(key), &listenerTask{event: event, withDiff: true, handler: listener.handle})
+	}
+
+	var duplicated bool
+	client.cacheMap.Upsert(key, newData, func(exist bool, valueInMap interface{}, newValue interface{}) interface{} {
+		cData := newValue.(cacheData)
+		if exist {
+			cData = valueInMap.(cacheData)
+		}
+		// added concurrently with the same ListenerId
+		if duplicated = cData.cacheDataListener.hasListenerId(listener.listenerId); duplicated {
+			return cData
+		}
+		// the entry moved on since the start content was read, the next change is reported against it
+		if !param.NotifyInitial && cData.md5 != listener.lastMd5 {
+			listener.lastMd5, listener.lastContent = cData.md5, ""
+		}
+		cData.isInitializing = true
+		cData.cacheDataListener.addListener(listener)
+		return cData
+	})
+	if duplicated {
+		return nil, errors.Errorf("[client.ListenConfig] listener %s of dataId=%s, group=%s exists already",
+			listener.listenerId, param.DataId, param.Group)
+	}
+	return &ConfigListenerHandle{key: key, dataId: param.DataId, group: param.Group, listener: listener}, nil
+}
+
+// removeCacheDataIfUnused stops listening to the config once no listener or fuzzy watch needs it.
+func (client *ConfigClient) removeCacheDataIfUnused(key string) {
+	var removed cacheData
+	if !client.cacheMap.RemoveCb(key, func(key string, v interface{}, exists bool) bool {
+		if !exists {
+			return false
+		}
+		removed = v.(cacheData)
+		return removed.cacheDataListener.isFuzzyOnly() && !client.isFuzzyWatched(removed.dataId, removed.group)
+	}) {
+		return
+	}
+	if len(client.unlistenCaches) > 0 {
+		client.unlistenCaches.Set(key, removed)
+		client.asyncNotifyListenConfig()
+	}
+}
+
+// executeConfigUnlisten tells the server to stop pushing the changes of the configs nobody listens to anymore.
+func (client *ConfigClient) executeConfigUnlisten() {
+	if len(client.unlistenCaches) == 0 {
+		return
+	}
+	tasks := make(map[int][]cacheData)
+	for _, key := range client.unlistenCaches.Keys() {
+		v, ok := client.unlistenCaches.Pop(key)
+		// listened again meanwhile
+		if !ok || client.cacheMap.Has(key) {
+			continue
+		}
+		data := v.(cacheData)
+		tasks[data.taskId] = append(tasks[data.taskId], data)
+	}
+	for taskId, caches := range tasks {
+		request := buildConfigBatchListenRequest(caches)
+		request.Listen = false
+		rpcClient := client.configProxy.createRpcClient(client.ctx, fmt.Sprintf("%d", taskId), client)
+		iResponse, err := client.configProxy.requestProxy(client.ctx, rpcClient, request, 3000)
+		if err != nil {
+			logger.Warnf("ConfigBatchListenRequest to remove listening failure, err:%v", err)
+			continue
+		}
+		if iResponse != nil && !iResponse.IsSuccess() {
+			logger.Warnf("ConfigBatchListenRequest to remove listening failure, error code:%d", iResponse.GetErrorCode())
+		}
+	}
+}
+
+func (listener *configListener) dispatchKey(cacheKey string) string {
+	return cacheKey + "#listener#" + listener.id
+}
+
+func (listener *configListener) handle(event *model.ConfigChangeEvent) {
+	if listener.onChangeEvent != nil {
+		listener.onChangeEvent(event)
+	}
+	if listener.onChange != nil {
+		listener.onChange(event.Namespace, event.Group, event.DataId, event.NewContent)
+	}
+}
+
+// advance moves the listener to the content of cacheData, it returns nil if the listener has seen it already.
+// The content is compared too, filters such as the placeholder filter may change it while the md5 stays.
+// The per-key diff is only computed for event listeners.
+func (listener *configListener) advance(cacheData *cacheData, decryptedContent string) *model.ConfigChangeEvent {
+	listener.mux.Lock()
+	defer listener.mux.Unlock()
+	if listener.lastMd5 == cacheData.md5 && listener.lastContent == decryptedContent {
+		return nil
+	}
+	var event *model.ConfigChangeEvent
+	if listener.onChangeEvent != nil {
+		event = cacheData.buildChangeEvent(listener.lastMd5, listener.lastContent, decryptedContent)
+	} else {
+		event = cacheData.newChangeEvent(listener.lastMd5, listener.lastContent, decryptedContent)
+	}
+	listener.lastMd5, listener.lastContent = cacheData.md5, decryptedContent
+	return event
+}
+
+func (registry *cacheDataListener) addListener(listener *configListener) {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	if registry.listeners == nil {
+		registry.listeners = make(map[*configListener]struct{})
+	}
+	registry.listeners[listener] = struct{}{}
+}
+
+func (registry *cacheDataListener) removeListener(listener *configListener) {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	delete(registry.listeners, listener)
+}
+
+// cancelListener removes the listener added with listenerId. An empty listenerId stands for the listener added
+// without one, which has to be the only such listener of the config, so that canceling never removes the
+// listener of somebody else. Listeners of WatchConfig are left alone, they end with their context.
+func (registry *cacheDataListener) cancelListener(listenerId string) error {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	var found *configListener
+	for listener := range registry.listeners {
+		if listener.isWatch || listener.listenerId != listenerId {
+			continue
+		}
+		if found != nil {
+			return errors.New("several listeners were added without ListenerId, remove them by their handles")
+		}
+		found = listener
+	}
+	if found != nil {
+		delete(registry.listeners, found)
+	}
+	return nil
+}
+
+func (registry *cacheDataListener) hasListenerId(listenerId string) bool {
+	if len(listenerId) == 0 {
+		return false
+	}
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	for listener := range registry.listeners {
+		if !listener.isWatch && listener.listenerId == listenerId {
+			return true
+		}
+	}
+	return false
+}
+
+func (registry *cacheDataListener) hasListeners() bool {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	return len(registry.listeners) > 0
+}
+
+func (registry *cacheDataListener) getListeners() []*configListener {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	listeners := make([]*configListener, 0, len(registry.listeners))
+	for listener := range registry.listeners {
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
+// notifyListeners hands the content of cacheData to every listener that hasn't seen it yet.
+func (cacheData *cacheData) notifyListeners(cacheKey, decryptedContent string) {
+	for _, listener := range cacheData.cacheDataListener.getListeners() {
+		if event := listener.advance(cacheData, decryptedContent); event != nil {
+			cacheData.configClient.dispatchListener(listener.dispatchKey(cacheKey), event,
+				listener.onChangeEvent != nil, listener.handle)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_request"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_response"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockConfigProxyForUnlisten struct {
+	MockConfigProxyWithContent
+	mux      sync.Mutex
+	requests []*rpc_request.ConfigBatchListenRequest
+}
+
+func (m *MockConfigProxyForUnlisten) requestProxy(ctx context.Context, rpcClient *rpc.RpcClient, request rpc_request.IRequest, timeoutMills uint64) (rpc_response.IResponse, error) {
+	if listenRequest, ok := request.(*rpc_request.ConfigBatchListenRequest); ok {
+		m.mux.Lock()
+		m.requests = append(m.requests, listenRequest)
+		m.mux.Unlock()
+	}
+	return m.MockConfigProxyWithContent.requestProxy(ctx, rpcClient, request, timeoutMills)
+}
+
+type recordingListener struct {
+	mux      sync.Mutex
+	contents []string
+}
+
+func (r *recordingListener) onChange(namespace, group, dataId, data string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.contents = append(r.contents, data)
+}
+
+func (r *recordingListener) get() []string {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return append([]string(nil), r.contents...)
+}
+
+func TestAddConfigListener_Independent(t *testing.T) {
+	param := vo.ConfigParam{DataId: "listener.properties", Group: localConfigTest.Group}
+	client, push := createConfigClientForWatch(t, param)
+	first, second := &recordingListener{}, &recordingListener{}
+
+	param.OnChange = first.onChange
+	firstHandle, err := client.AddConfigListener(param)
+	assert.Nil(t, err)
+	param.OnChange = second.onChange
+	_, err = client.AddConfigListener(param)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, client.cacheMap.Count())
+
+	push("a=1")
+	waitListenersDispatched(t, client)
+	assert.Equal(t, []string{"a=1"}, first.get())
+	assert.Equal(t, []string{"a=1"}, second.get())
+
+	assert.Nil(t, client.RemoveConfigListener(firstHandle))
+	assert.Equal(t, 1, client.cacheMap.Count())
+	push("a=2")
+	waitListenersDispatched(t, client)
+	assert.Equal(t, []string{"a=1"}, first.get())
+	assert.Equal(t, []string{"a=1", "a=2"}, second.get())
+}
+
+func TestAddConfigListener_LateListener(t *testing.T) {
+	param := vo.ConfigParam{DataId: "listener.properties", Group: localConfigTest.Group}
+	client, push := createConfigClientForWatch(t, param)
+	early, late := &recordingListener{}, &recordingListener{}
+
+	param.OnChange = early.onChange
+	_, err := client.AddConfigListener(param)
+	assert.Nil(t, err)
+	push("a=1")
+	waitListenersDispatched(t, client)
+
+	// the late listener starts from the current value, only later changes reach it
+	param.OnChange = late.onChange
+	_, err = client.AddConfigListener(param)
+	assert.Nil(t, err)
+	push("a=1")
+	waitListenersDispatched(t, client)
+	assert.Empty(t, late.get())
+
+	push("a=2")
+	waitListenersDispatched(t, client)
+	assert.Equal(t, []string{"a=1", "a=2"}, early.get())
+	assert.Equal(t, []string{"a=2"}, late.get())
+}
+
+func TestAddConfigListener_NotifyInitial(t *testing.T) {
+	param := vo.ConfigParam{DataId: "listener.properties", Group: localConfigTest.Group}
+	client, push := createConfigClientForWatch(t, param)
+	first, second := &recordingListener{}, &recordingListener{}
+
+	param.OnChange = first.onChange
+	_, err := client.AddConfigListener(param)
+	assert.Nil(t, err)
+	push("a=1")
+	waitListenersDispatched(t, client)
+
+	param.OnChange = second.onChange
+	param.NotifyInitial = true
+	_, err = client.AddConfigListener(param)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a=1"}, second.get())
+	assert.Equal(t, []string{"a=1"}, first.get())
+}
+
+func TestAddConfigListener_Invalid(t *testing.T) {
+	client := createConfigClientTest()
+	_, err := client.AddConfigListener(vo.ConfigParam{DataId: "listener.properties", Group: localConfigTest.Group})
+	assert.NotNil(t, err)
+	assert.NotNil(t, client.RemoveConfigListener(nil))
+}
+
+func TestCancelListenConfig_OtherListenerSurvives(t *testing.T) {
+	param := vo.ConfigParam{DataId: "listener.properties", Group: localConfigTest.Group}
+	client, push := createConfigClientForWatch(t, param)
+	first, second, anonymous := &recordingListener{}, &recordingListener{}, &recordingListener{}
+
+	firstParam, secondParam := param, param
+	firstParam.ListenerId, firstParam.OnChange = "first", first.onChange
+	secondParam.ListenerId, secondParam.OnChange = "second", second.onChange
+	assert.Nil(t, client.ListenConfig(firstParam))
+	assert.Nil(t, client.ListenConfig(secondParam))
+	assert.NotNil(t, client.ListenConfig(firstParam))
+	param.OnChange = anonymous.onChange
+	assert.Nil(t, client.ListenConfig(param))
+
+	assert.Nil(t, client.CancelListenConfig(vo.ConfigParam{DataId: param.DataId, Group: param.Group, ListenerId: "first"}))
+	push("a=1")
+	waitListenersDispatched(t, client)
+	assert.Empty(t, first.get())
+	assert.Equal(t, []string{"a=1"}, second.get())
+	assert.Equal(t, []string{"a=1"}, anonymous.get())
+
+	// the only listener without ListenerId is canceled without one
+	assert.Nil(t, client.CancelListenConfig(vo.ConfigParam{DataId: param.DataId, Group: param.Group}))
+	push("a=2")
+	waitListenersDispatched(t, client)
+	assert.Equal(t, []string{"a=1", "a=2"}, second.get())
+	assert.Equal(t, []string{"a=1"}, anonymous.get())
+
+	assert.Nil(t, client.CancelListenConfig(vo.ConfigParam{DataId: param.DataId, Group: param.Group, ListenerId: "second"}))
+	assert.Equal(t, 0, client.cacheMap.Count())
+}
+
+func TestCancelListenConfig_Ambiguous(t *testing.T) {
+	param := vo.ConfigParam{DataId: "listener.properties", Group: localConfigTest.Group}
+	client, push := createConfigClientForWatch(t, param)
+	first, second := &recordingListener{}, &recordingListener{}
+
+	param.OnChange = first.onChange
+	assert.Nil(t, client.ListenConfig(param))
+	param.OnChange = second.onChange
+	assert.Nil(t, client.ListenConfig(param))
+
+	// two components listening without ListenerId, neither of them is removed by guessing
+	assert.NotNil(t, client.CancelListenConfig(param))
+	push("a=1")
+	waitListenersDispatched(t, client)
+	assert.Equal(t, []string{"a=1"}, first.get())
+	assert.Equal(t, []string{"a=1"}, second.get())
+}
+
+func TestRemoveConfigListener_Unlisten(t *testing.T) {
+	param := vo.ConfigParam{DataId: "listener.properties", Group: localConfigTest.Group}
+	proxy := &MockConfigProxyForUnlisten{}
+	client := createConfigClientTest()
+	client.configProxy = proxy
+	first, second := &recordingListener{}, &recordingListener{}
+
+	param.OnChange = first.onChange
+	firstHandle, err := client.AddConfigListener(param)
+	assert.Nil(t, err)
+	param.OnChange = second.onChange
+	secondHandle, err := client.AddConfigListener(param)
+	assert.Nil(t, err)
+
+	assert.Nil(t, client.RemoveConfigListener(firstHandle))
+	client.executeConfigUnlisten()
+	assert.Empty(t, proxy.requests)
+
+	assert.Nil(t, client.RemoveConfigListener(secondHandle))
+	assert.Equal(t, 0, client.cacheMap.Count())
+	client.executeConfigUnlisten()
+	proxy.mux.Lock()
+	defer proxy.mux.Unlock()
+	if assert.Len(t, proxy.requests, 1) {
+		assert.False(t, proxy.requests[0].Listen)
+		assert.Len(t, proxy.requests[0].ConfigListenContexts, 1)
+		assert.Equal(t, param.DataId, proxy.requests[0].ConfigListenContexts[0].DataId)
+	}
+}
+
+func TestRemoveConfigListener_ListenedAgain(t *testing.T) {
+	param := vo.ConfigParam{DataId: "listener.properties", Group: localConfigTest.Group}
+	proxy := &MockConfigProxyForUnlisten{}
+	client := createConfigClientTest()
+	client.configProxy = proxy
+	listener := &recordingListener{}
+
+	param.OnChange = listener.onChange
+	handle, err := client.AddConfigListener(param)
+	assert.Nil(t, err)
+	assert.Nil(t, client.RemoveConfigListener(handle))
+	_, err = client.AddConfigListener(param)
+	assert.Nil(t, err)
+
+	client.executeConfigUnlisten()
+	assert.Empty(t, proxy.requests)
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/v2/common/filter"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+)
+
+// RegisterConfigFilter adds filter to the filter chain of the client, the filters run by their order on the content
+// being read and in the reverse order on the content being published. Filters should be registered before the client
+// is used.
+func (client *ConfigClient) RegisterConfigFilter(configFilter filter.IConfigFilter) error {
+	if configFilter == nil {
+		return errors.New("[client.RegisterConfigFilter] filter can not be nil")
+	}
+	return filter.RegisterConfigFilterToChain(client.configFilterChainManager, configFilter)
+}
+
+// NewConfigPlaceholderFilter creates a filter resolving the placeholders of the configs read by client, the configs
+// referenced by ${nacos:...} are read and listened to through client. Register it with client.RegisterConfigFilter.
+func NewConfigPlaceholderFilter(client *ConfigClient, opts ...filter.PlaceholderOption) *filter.ConfigPlaceholderFilter {
+	return filter.NewConfigPlaceholderFilter(&placeholderSource{client: client}, opts...)
+}
+
+type placeholderSource struct {
+	client *ConfigClient
+}
+
+func (source *placeholderSource) GetConfig(dataId, group string) (string, error) {
+	client := source.client
+	content, encryptedDataKey, err := client.getConfigInner(client.ctx, vo.ConfigParam{DataId: dataId, Group: group})
+	if err != nil {
+		return "", err
+	}
+	param := &vo.ConfigParam{
+		DataId:           dataId,
+		Group:            group,
+		Content:          content,
+		EncryptedDataKey: encryptedDataKey,
+		UsageType:        vo.ResponseType,
+	}
+	// the placeholders are resolved by the filter itself, which keeps track of the references
+	if err = client.doFiltersWithoutPlaceholders(param); err != nil {
+		return "", err
+	}
+	return param.Content, nil
+}
+
+// doFiltersWithoutPlaceholders runs the filter chain leaving the placeholders unresolved, for content resolved by
+// the placeholder filter itself.
+func (client *ConfigClient) doFiltersWithoutPlaceholders(param *vo.ConfigParam) error {
+	return client.doFiltersSkipping(param, filter.PlaceholderFilterName)
+}
+
+// doFiltersToPublishAgain runs the filter chain leaving the placeholders and the secret references as they are, for
+// content that is published again, so no resolved secret is sent to the server. A signed config fails on a client
+// that verifies signatures only, the signature is removed when reading and couldn't be added again.
+func (client *ConfigClient) doFiltersToPublishAgain(param *vo.ConfigParam) error {
+	for _, configFilter := range client.configFilterChainManager.GetFilters() {
+		if signatureFilter, ok := configFilter.(*filter.ConfigSignatureFilter); ok && !signatureFilter.CanPublish(param.DataId) {
+			return errors.Errorf("config dataId=%s is signed, it can't be published again without a signing key",
+				param.DataId)
+		}
+	}
+	return client.doFiltersSkipping(param, filter.PlaceholderFilterName, filter.SecretFilterName)
+}
+
+func (client *ConfigClient) doFiltersSkipping(param *vo.ConfigParam, skipped ...string) error {
+	for _, configFilter := range client.configFilterChainManager.GetFilters() {
+		if util.Contains(skipped, configFilter.GetFilterName()) {
+			continue
+		}
+		if err := configFilter.DoFilter(param); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (source *placeholderSource) ListenConfig(dataId, group string, onChange func()) error {
+	_, err := source.client.AddConfigListener(vo.ConfigParam{
+		DataId: dataId,
+		Group:  group,
+		OnChange: func(namespace, group, dataId, data string) {
+			onChange()
+		},
+	})
+	return err
+}
+
+func (source *placeholderSource) Refresh(dataId, group string) {
+	source.client.refreshListeners(dataId, group)
+}
+
+// refreshListeners runs the filters on the current content of a listened config again, and notifies the listeners
+// if the result differs from what they have seen.
+func (client *ConfigClient) refreshListeners(dataId, group string) {
+	clientConfig, err := client.GetClientConfig()
+	if err != nil {
+		return
+	}
+	cacheKey := util.GetConfigCacheKey(dataId, group, clientConfig.NamespaceId)
+	v, ok := client.cacheMap.Get(cacheKey)
+	if !ok {
+		return
+	}
+	data := v.(cacheData)
+	decryptedContent, err := data.decryptContent(data.content, data.encryptedDataKey)
+	if err != nil {
+		logger.Errorf("do filters failed ,dataId=%s,group=%s,tenant=%s,err:%+v ", data.dataId, data.group,
+			data.tenant, err)
+		return
+	}
+	data.notifyListeners(cacheKey, decryptedContent)
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigPlaceholderFilter(t *testing.T) {
+	proxy := &MockConfigProxyWithContent{contents: map[string]string{
+		"common.yaml":    "redis:\n  addr: redis.local:6379\n",
+		"app.properties": "redis=${nacos:common.yaml#redis.addr}",
+	}}
+	client := createConfigClientTest()
+	client.configProxy = proxy
+	assert.Nil(t, client.RegisterConfigFilter(NewConfigPlaceholderFilter(client)))
+	param := vo.ConfigParam{DataId: "app.properties", Group: localConfigTest.Group}
+
+	content, err := client.GetConfig(param)
+	assert.Nil(t, err)
+	assert.Equal(t, "redis=redis.local:6379", content)
+
+	var mux sync.Mutex
+	var received []string
+	param.NotifyInitial = true
+	param.OnChange = func(namespace, group, dataId, data string) {
+		mux.Lock()
+		defer mux.Unlock()
+		received = append(received, data)
+	}
+	assert.Nil(t, client.ListenConfig(param))
+
+	// a change of the referenced config reaches the listeners of app.properties
+	proxy.mux.Lock()
+	proxy.contents["common.yaml"] = "redis:\n  addr: redis.prod:6379\n"
+	proxy.mux.Unlock()
+	clientConfig, _ := client.GetClientConfig()
+	data, ok := client.cacheMap.Get(util.GetConfigCacheKey("common.yaml", localConfigTest.Group, clientConfig.NamespaceId))
+	assert.True(t, ok)
+	client.refreshContentAndCheck(data.(cacheData), false)
+
+	assert.Eventually(t, func() bool {
+		mux.Lock()
+		defer mux.Unlock()
+		return len(received) == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"redis=redis.local:6379", "redis=redis.prod:6379"}, received)
+
+	// the stored content keeps its placeholders
+	raw, _, err := client.getConfigInner(client.ctx, vo.ConfigParam{DataId: "app.properties", Group: localConfigTest.Group})
+	assert.Nil(t, err)
+	assert.Equal(t, "redis=${nacos:common.yaml#redis.addr}", raw)
+	assert.NotNil(t, client.RegisterConfigFilter(nil))
+}
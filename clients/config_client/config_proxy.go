@@ -56,11 +56,11 @@ func NewConfigProxyWithRamCredentialProvider(ctx context.Context, serverConfig [
 	return &proxy, err
 }
 
-func (cp *ConfigProxy) requestProxy(rpcClient *rpc.RpcClient, request rpc_request.IRequest, timeoutMills uint64) (rpc_response.IResponse, error) {
+func (cp *ConfigProxy) requestProxy(ctx context.Context, rpcClient *rpc.RpcClient, request rpc_request.IRequest, timeoutMills uint64) (rpc_response.IResponse, error) {
 	start := time.Now()
 	cp.nacosServer.InjectSecurityInfo(request.GetHeaders(), security.BuildConfigResourceByRequest(request))
 	cp.injectCommHeader(request.GetHeaders())
-	response, err := rpcClient.Request(request, int64(timeoutMills))
+	response, err := rpcClient.RequestWithContext(ctx, request, int64(timeoutMills))
 	monitor.GetConfigRequestMonitor(constant.GRPC, request.GetRequestType(), rpc_response.GetGrpcResponseStatusCode(response)).Observe(float64(time.Now().Nanosecond() - start.Nanosecond()))
 	return response, err
 }
@@ -74,7 +74,7 @@ func (cp *ConfigProxy) injectCommHeader(param map[string]string) {
 	param[constant.CHARSET_KEY] = "utf-8"
 }
 
-func (cp *ConfigProxy) searchConfigProxy(param vo.SearchConfigParam, tenant, accessKey, secretKey string) (*model.ConfigPage, error) {
+func (cp *ConfigProxy) searchConfigProxy(ctx context.Context, param vo.SearchConfigParam, tenant, accessKey, secretKey string) (*model.ConfigPage, error) {
 	params := util.TransformObject2Param(param)
 	if len(tenant) > 0 {
 		params["tenant"] = tenant
@@ -87,13 +87,19 @@ func (cp *ConfigProxy) searchConfigProxy(param vo.SearchConfigParam, tenant, acc
 	}
 	var headers = map[string]string{}
 	var version = "v2"
-	result, err := cp.nacosServer.ReqConfigApi(constant.CONFIG_PATH, params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_PATH, params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if len(tenant) > 0 {
 			params["namespaceId"] = params["tenant"]
 		}
 		params["groupName"] = params["group"]
-		result, err = cp.nacosServer.ReqConfigApi("/v3/admin/cs/config/list", params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+		renameParam(params, "config_tags", "configTags")
+		renameParam(params, "types", "type")
+		renameParam(params, "config_detail", "configDetail")
+		result, err = cp.nacosServer.ReqConfigApiWithContext(ctx, "/v3/admin/cs/config/list", params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
 		if err != nil {
 			return nil, err
 		}
@@ -113,19 +119,187 @@ func (cp *ConfigProxy) searchConfigProxy(param vo.SearchConfigParam, tenant, acc
 	return &configPage, nil
 }
 
-func (cp *ConfigProxy) queryConfig(dataId, group, tenant string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error) {
+// renameParam moves the value of a request param to the name used by another api version.
+func renameParam(params map[string]string, from, to string) {
+	if v, ok := params[from]; ok {
+		params[to] = v
+		delete(params, from)
+	}
+}
+
+func (cp *ConfigProxy) queryBetaConfigProxy(ctx context.Context, dataId, group, tenant string) (*model.ConfigBeta, error) {
+	result, err := cp.betaConfigApi(ctx, dataId, group, tenant, http.MethodGet)
+	if err != nil {
+		return nil, err
+	}
+	var betaResult model.ConfigBetaResult
+	if err = json.Unmarshal([]byte(result), &betaResult); err != nil {
+		return nil, err
+	}
+	return betaResult.Data, nil
+}
+
+func (cp *ConfigProxy) stopBetaConfigProxy(ctx context.Context, dataId, group, tenant string) (bool, error) {
+	result, err := cp.betaConfigApi(ctx, dataId, group, tenant, http.MethodDelete)
+	if err != nil {
+		return false, err
+	}
+	var stopResult struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    bool   `json:"data"`
+	}
+	if err = json.Unmarshal([]byte(result), &stopResult); err != nil {
+		return false, err
+	}
+	return stopResult.Data, nil
+}
+
+// betaConfigApi calls the v1 beta api, falling back to the v3 admin api of nacos 3.x servers.
+func (cp *ConfigProxy) betaConfigApi(ctx context.Context, dataId, group, tenant, method string) (string, error) {
+	params := map[string]string{"dataId": dataId, "group": group, "beta": "true"}
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+	var headers = map[string]string{}
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_PATH, params, headers, method, cp.clientConfig.TimeoutMs)
+	if err == nil {
+		return result, nil
+	}
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	params = map[string]string{"dataId": dataId, "group": group, "groupName": group}
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+		params["namespaceId"] = tenant
+	}
+	return cp.nacosServer.ReqConfigApiWithContext(ctx, "/v3/admin/cs/config/beta", params, headers, method, cp.clientConfig.TimeoutMs)
+}
+
+func (cp *ConfigProxy) listConfigHistoryProxy(ctx context.Context, dataId, group, tenant string, page vo.PageParam) (*model.ConfigHistoryPage, error) {
+	params := map[string]string{
+		"search":   "accurate",
+		"dataId":   dataId,
+		"group":    group,
+		"pageNo":   strconv.Itoa(page.PageNo),
+		"pageSize": strconv.Itoa(page.PageSize),
+	}
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+	var headers = map[string]string{}
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_HISTORY_PATH, params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+	if err == nil {
+		var historyPage model.ConfigHistoryPage
+		if err = json.Unmarshal([]byte(result), &historyPage); err != nil {
+			return nil, err
+		}
+		return &historyPage, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	params["groupName"] = group
+	if len(tenant) > 0 {
+		params["namespaceId"] = tenant
+	}
+	result, err = cp.nacosServer.ReqConfigApiWithContext(ctx, "/v3/admin/cs/history/list", params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+	if err != nil {
+		return nil, err
+	}
+	var historyPageResult model.ConfigHistoryPageResult
+	if err = json.Unmarshal([]byte(result), &historyPageResult); err != nil {
+		return nil, err
+	}
+	return &historyPageResult.Data, nil
+}
+
+func (cp *ConfigProxy) getConfigHistoryProxy(ctx context.Context, dataId, group, tenant, historyId string) (*model.ConfigHistoryItem, error) {
+	params := map[string]string{"nid": historyId, "dataId": dataId, "group": group}
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+	var headers = map[string]string{}
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_HISTORY_PATH, params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+	if err == nil {
+		var historyItem model.ConfigHistoryItem
+		if err = json.Unmarshal([]byte(result), &historyItem); err != nil {
+			return nil, err
+		}
+		return &historyItem, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	params["groupName"] = group
+	if len(tenant) > 0 {
+		params["namespaceId"] = tenant
+	}
+	result, err = cp.nacosServer.ReqConfigApiWithContext(ctx, "/v3/admin/cs/history", params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+	if err != nil {
+		return nil, err
+	}
+	var historyResult model.ConfigHistoryResult
+	if err = json.Unmarshal([]byte(result), &historyResult); err != nil {
+		return nil, err
+	}
+	if historyResult.Data == nil {
+		return nil, errors.Errorf("config history %s not found", historyId)
+	}
+	return historyResult.Data, nil
+}
+
+// queryConfigMetadataProxy reads the metadata of a config with the v1 api, falling back to the v3 admin api of nacos
+// 3.x servers.
+func (cp *ConfigProxy) queryConfigMetadataProxy(ctx context.Context, dataId, group, tenant string) (*model.ConfigMetadata, error) {
+	params := map[string]string{"dataId": dataId, "group": group, "show": "all"}
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+	var headers = map[string]string{}
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_PATH, params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+	if err == nil {
+		var metadata model.ConfigMetadata
+		if err = json.Unmarshal([]byte(result), &metadata); err != nil {
+			return nil, err
+		}
+		return &metadata, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	params = map[string]string{"dataId": dataId, "groupName": group}
+	if len(tenant) > 0 {
+		params["namespaceId"] = tenant
+	}
+	result, err = cp.nacosServer.ReqConfigApiWithContext(ctx, "/v3/admin/cs/config", params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+	if err != nil {
+		return nil, err
+	}
+	var metadataResult model.ConfigMetadataResult
+	if err = json.Unmarshal([]byte(result), &metadataResult); err != nil {
+		return nil, err
+	}
+	if metadataResult.Data == nil {
+		return nil, errors.Errorf("config dataId=%s, group=%s not found", dataId, group)
+	}
+	return metadataResult.Data, nil
+}
+
+func (cp *ConfigProxy) queryConfig(ctx context.Context, dataId, group, tenant, tag string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error) {
 	if group == "" {
 		group = constant.DEFAULT_GROUP
 	}
 	configQueryRequest := rpc_request.NewConfigQueryRequest(group, dataId, tenant)
+	configQueryRequest.Tag = tag
 	configQueryRequest.Headers["notify"] = strconv.FormatBool(notify)
 	cacheKey := util.GetConfigCacheKey(dataId, group, tenant)
 	// use the same key of config file as the limit checker's key
-	if IsLimited(cacheKey) {
-		// return error when check limited
-		return nil, errors.New("ConfigQueryRequest is limited")
+	if err := client.acquireRateLimit(ctx, cacheKey); err != nil {
+		return nil, err
 	}
-	iResponse, err := cp.requestProxy(cp.getRpcClient(client), configQueryRequest, timeout)
+	iResponse, err := cp.requestProxy(ctx, cp.getRpcClient(client), configQueryRequest, timeout)
 	if err != nil {
 		return nil, err
 	}
@@ -133,9 +307,12 @@ func (cp *ConfigProxy) queryConfig(dataId, group, tenant string, timeout uint64,
 	if !ok {
 		return nil, errors.New("ConfigQueryRequest returns type error")
 	}
+	// the snapshot holds the untagged content only
 	if response.IsSuccess() {
-		cache.WriteConfigToFile(cacheKey, cp.clientConfig.CacheDir, response.Content)
-		cache.WriteEncryptedDataKeyToFile(cacheKey, cp.clientConfig.CacheDir, response.EncryptedDataKey)
+		if len(tag) == 0 {
+			cache.WriteConfigToFile(cacheKey, cp.clientConfig.CacheDir, response.Content)
+			cache.WriteEncryptedDataKeyToFile(cacheKey, cp.clientConfig.CacheDir, response.EncryptedDataKey)
+		}
 		if response.ContentType == "" {
 			response.ContentType = "text"
 		}
@@ -143,8 +320,10 @@ func (cp *ConfigProxy) queryConfig(dataId, group, tenant string, timeout uint64,
 	}
 
 	if response.GetErrorCode() == 300 {
-		cache.WriteConfigToFile(cacheKey, cp.clientConfig.CacheDir, "")
-		cache.WriteEncryptedDataKeyToFile(cacheKey, cp.clientConfig.CacheDir, "")
+		if len(tag) == 0 {
+			cache.WriteConfigToFile(cacheKey, cp.clientConfig.CacheDir, "")
+			cache.WriteEncryptedDataKeyToFile(cacheKey, cp.clientConfig.CacheDir, "")
+		}
 		response.SetSuccess(true)
 		return response, nil
 	}
@@ -186,6 +365,12 @@ func (cp *ConfigProxy) createRpcClient(ctx context.Context, taskId string, clien
 			// TODO fix the group/dataId empty problem
 			return rpc_request.NewConfigChangeNotifyRequest("", "", "")
 		}, &ConfigChangeNotifyRequestHandler{client: client})
+		rpcClient.RegisterServerRequestHandler(func() rpc_request.IRequest {
+			return rpc_request.NewConfigFuzzyWatchChangeNotifyRequest()
+		}, &ConfigFuzzyWatchChangeNotifyRequestHandler{client: client})
+		rpcClient.RegisterServerRequestHandler(func() rpc_request.IRequest {
+			return rpc_request.NewConfigFuzzyWatchSyncRequest()
+		}, &ConfigFuzzyWatchSyncRequestHandler{client: client})
 
 		configListener := NewConfigConnectionEventListener(client, taskId)
 		rpcClient.RegisterConnectionListener(configListener)
@@ -230,3 +415,48 @@ func (c *ConfigChangeNotifyRequestHandler) RequestReply(request rpc_request.IReq
 		Response: &rpc_response.Response{ResultCode: constant.RESPONSE_CODE_SUCCESS},
 	}
 }
+
+type ConfigFuzzyWatchChangeNotifyRequestHandler struct {
+	client *ConfigClient
+}
+
+func (c *ConfigFuzzyWatchChangeNotifyRequestHandler) Name() string {
+	return "ConfigFuzzyWatchChangeNotifyRequestHandler"
+}
+
+func (c *ConfigFuzzyWatchChangeNotifyRequestHandler) RequestReply(request rpc_request.IRequest, rpcClient *rpc.RpcClient) rpc_response.IResponse {
+	notifyRequest, ok := request.(*rpc_request.ConfigFuzzyWatchChangeNotifyRequest)
+	if !ok {
+		return nil
+	}
+	logger.Infof("%s [fuzzy-watch-push] config changed. groupKey=%s, changeType=%s", rpcClient.Name(),
+		notifyRequest.GroupKey, notifyRequest.ChangeType)
+	c.client.onFuzzyWatchNotify("", notifyRequest.GroupKey, notifyRequest.ChangeType)
+	return &rpc_response.ConfigFuzzyWatchChangeNotifyResponse{
+		Response: &rpc_response.Response{ResultCode: constant.RESPONSE_CODE_SUCCESS, Success: true},
+	}
+}
+
+type ConfigFuzzyWatchSyncRequestHandler struct {
+	client *ConfigClient
+}
+
+func (c *ConfigFuzzyWatchSyncRequestHandler) Name() string {
+	return "ConfigFuzzyWatchSyncRequestHandler"
+}
+
+func (c *ConfigFuzzyWatchSyncRequestHandler) RequestReply(request rpc_request.IRequest, rpcClient *rpc.RpcClient) rpc_response.IResponse {
+	syncRequest, ok := request.(*rpc_request.ConfigFuzzyWatchSyncRequest)
+	if !ok {
+		return nil
+	}
+	logger.Infof("%s [fuzzy-watch-push] sync pattern=%s, syncType=%s, batch=%d/%d, size=%d", rpcClient.Name(),
+		syncRequest.GroupKeyPattern, syncRequest.SyncType, syncRequest.CurrentBatch, syncRequest.TotalBatch,
+		len(syncRequest.Contexts))
+	for _, syncContext := range syncRequest.Contexts {
+		c.client.onFuzzyWatchNotify(syncRequest.GroupKeyPattern, syncContext.GroupKey, syncContext.ChangedType)
+	}
+	return &rpc_response.ConfigFuzzyWatchSyncResponse{
+		Response: &rpc_response.Response{ResultCode: constant.RESPONSE_CODE_SUCCESS, Success: true},
+	}
+}
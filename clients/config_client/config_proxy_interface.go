@@ -11,9 +11,14 @@ import (
 )
 
 type IConfigProxy interface {
-	queryConfig(dataId, group, tenant string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error)
-	searchConfigProxy(param vo.SearchConfigParam, tenant, accessKey, secretKey string) (*model.ConfigPage, error)
-	requestProxy(rpcClient *rpc.RpcClient, request rpc_request.IRequest, timeoutMills uint64) (rpc_response.IResponse, error)
+	queryConfig(ctx context.Context, dataId, group, tenant, tag string, timeout uint64, notify bool, client *ConfigClient) (*rpc_response.ConfigQueryResponse, error)
+	searchConfigProxy(ctx context.Context, param vo.SearchConfigParam, tenant, accessKey, secretKey string) (*model.ConfigPage, error)
+	queryBetaConfigProxy(ctx context.Context, dataId, group, tenant string) (*model.ConfigBeta, error)
+	stopBetaConfigProxy(ctx context.Context, dataId, group, tenant string) (bool, error)
+	listConfigHistoryProxy(ctx context.Context, dataId, group, tenant string, page vo.PageParam) (*model.ConfigHistoryPage, error)
+	getConfigHistoryProxy(ctx context.Context, dataId, group, tenant, historyId string) (*model.ConfigHistoryItem, error)
+	queryConfigMetadataProxy(ctx context.Context, dataId, group, tenant string) (*model.ConfigMetadata, error)
+	requestProxy(ctx context.Context, rpcClient *rpc.RpcClient, request rpc_request.IRequest, timeoutMills uint64) (rpc_response.IResponse, error)
 	createRpcClient(ctx context.Context, taskId string, client *ConfigClient) *rpc.RpcClient
 	getRpcClient(client *ConfigClient) *rpc.RpcClient
 }
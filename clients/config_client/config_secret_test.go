@@ -0,0 +1,97 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/filter"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_request"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSecretFilter(t *testing.T) (filter.IConfigFilter, string) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "db")
+	assert.Nil(t, os.WriteFile(secretFile, []byte("s3cret\n"), 0600))
+	secretFilter, err := filter.NewConfigSecretFilter(filter.WithSecretResolver(filter.NewFileSecretResolver(dir)))
+	assert.Nil(t, err)
+	return secretFilter, secretFile
+}
+
+func Test_SecretFilter(t *testing.T) {
+	secretFilter, secretFile := newTestSecretFilter(t)
+	client := createConfigClientTest()
+	client.configProxy = &MockConfigProxyWithContent{contents: map[string]string{
+		"db.properties": "db.password=${secret:file:" + secretFile + "}"}}
+	assert.Nil(t, client.RegisterConfigFilter(secretFilter))
+
+	content, err := client.GetConfig(vo.ConfigParam{DataId: "db.properties", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "db.password=s3cret", content)
+}
+
+func Test_SecretFilter_Snapshot(t *testing.T) {
+	secretFilter, secretFile := newTestSecretFilter(t)
+	client := createConfigClientForKms()
+	assert.Nil(t, client.RegisterConfigFilter(secretFilter))
+	clientConfig, _ := client.GetClientConfig()
+	cacheKey := util.GetConfigCacheKey("secret-snapshot", "group", clientConfig.NamespaceId)
+	stored := "db.password=${secret:file:" + secretFile + "}"
+	assert.Nil(t, cache.WriteConfigToFile(cacheKey, client.configCacheDir, stored))
+
+	// the secret is resolved again from the snapshot, which keeps the reference
+	content, err := client.GetConfig(vo.ConfigParam{DataId: "secret-snapshot", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "db.password=s3cret", content)
+	snapshot, err := cache.ReadConfigFromFile(cacheKey, client.configCacheDir)
+	assert.Nil(t, err)
+	assert.Equal(t, stored, snapshot)
+}
+
+type MockConfigProxyForSecretHistory struct {
+	MockConfigProxyForBeta
+	historyContent string
+}
+
+func (m *MockConfigProxyForSecretHistory) getConfigHistoryProxy(ctx context.Context, dataId, group, tenant, historyId string) (*model.ConfigHistoryItem, error) {
+	item, err := m.MockConfigProxyForBeta.getConfigHistoryProxy(ctx, dataId, group, tenant, historyId)
+	item.Content = m.historyContent
+	return item, err
+}
+
+func Test_SecretFilter_Rollback(t *testing.T) {
+	secretFilter, secretFile := newTestSecretFilter(t)
+	stored := "db.password=${secret:file:" + secretFile + "}"
+	proxy := &MockConfigProxyForSecretHistory{historyContent: stored}
+	client := createConfigClientTest()
+	client.configProxy = proxy
+	assert.Nil(t, client.RegisterConfigFilter(secretFilter))
+
+	// the reference is published again, not the secret
+	published, err := client.RollbackConfig(vo.ConfigParam{DataId: "db.properties", Group: "group"}, "12")
+	assert.Nil(t, err)
+	assert.True(t, published)
+	assert.Equal(t, stored, proxy.request.(*rpc_request.ConfigPublishRequest).Content)
+}
@@ -0,0 +1,31 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/v2/common/filter"
+	"github.com/pkg/errors"
+)
+
+// RegisterConfigValidator adds validator to the filter chain of the client, PublishConfig runs the validators on
+// the content before any filter and returns the *filter.ConfigValidationError of the first one rejecting it.
+func (client *ConfigClient) RegisterConfigValidator(validator filter.IConfigValidator) error {
+	if validator == nil {
+		return errors.New("[client.RegisterConfigValidator] validator can not be nil")
+	}
+	return filter.RegisterConfigValidatorToChain(client.configFilterChainManager, validator)
+}
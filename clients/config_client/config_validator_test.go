@@ -0,0 +1,52 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/filter"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterConfigValidator(t *testing.T) {
+	client := createConfigClientTest()
+	schemaValidator := filter.NewJsonSchemaValidator()
+	assert.Nil(t, schemaValidator.RegisterSchema("*.json", `{"required": ["port"]}`))
+	assert.Nil(t, client.RegisterConfigValidator(filter.NewConfigSyntaxValidator()))
+	assert.Nil(t, client.RegisterConfigValidator(schemaValidator))
+	assert.NotNil(t, client.RegisterConfigValidator(nil))
+
+	published, err := client.PublishConfig(vo.ConfigParam{DataId: "app.json", Group: "group", Content: `{"port": 80`})
+	assert.False(t, published)
+	validationErr := filter.AsConfigValidationError(err)
+	assert.NotNil(t, validationErr)
+	assert.Equal(t, filter.SyntaxValidatorName, validationErr.Validator)
+	assert.Equal(t, "group", validationErr.Group)
+
+	published, err = client.PublishConfig(vo.ConfigParam{DataId: "app.json", Group: "group", Content: `{"host": "a"}`})
+	assert.False(t, published)
+	validationErr = filter.AsConfigValidationError(err)
+	assert.NotNil(t, validationErr)
+	assert.Equal(t, filter.JsonSchemaValidatorName, validationErr.Validator)
+	assert.Equal(t, "port", validationErr.Violations[0].Path)
+
+	published, err = client.PublishConfig(vo.ConfigParam{DataId: "app.json", Group: "group", Content: `{"port": 80}`})
+	assert.Nil(t, err)
+	assert.True(t, published)
+}
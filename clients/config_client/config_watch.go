@@ -0,0 +1,164 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+const defaultWatchBufferSize = 16
+
+// WatchOverflowPolicy tells WatchConfig what to do with a change when the channel of the watch is full.
+type WatchOverflowPolicy int
+
+const (
+	// WatchKeepLatest folds the changes waiting in the channel and the new one into a single change, the
+	// reader skips the intermediate contents but always ends up with the latest one. It is the default.
+	WatchKeepLatest WatchOverflowPolicy = iota
+	// WatchBlock waits until the reader takes the change or the watch ends. The waiting change holds one of
+	// the ListenerWorkerNum goroutines, so a stuck reader slows down the listeners of other configs.
+	WatchBlock
+)
+
+// ConfigEvent is a change of a config watched by WatchConfig.
+type ConfigEvent struct {
+	*model.ConfigChangeEvent
+	// Merged is the number of changes folded into this one because the reader fell behind, see WatchKeepLatest
+	Merged int
+}
+
+type WatchOption func(*configWatcher)
+
+// WithWatchBufferSize sets the capacity of the channel returned by WatchConfig, default is 16.
+func WithWatchBufferSize(size int) WatchOption {
+	return func(watcher *configWatcher) {
+		watcher.bufferSize = size
+	}
+}
+
+// WithWatchOverflowPolicy sets what happens to a change when the channel is full, default is WatchKeepLatest.
+func WithWatchOverflowPolicy(policy WatchOverflowPolicy) WatchOption {
+	return func(watcher *configWatcher) {
+		watcher.overflow = policy
+	}
+}
+
+// configWatcher feeds the changes of one config to the channel of a WatchConfig call, through a listener
+// sharing the cache entry of the config with the other listeners.
+type configWatcher struct {
+	ctx        context.Context
+	clientDone <-chan struct{}
+	bufferSize int
+	overflow   WatchOverflowPolicy
+	events     chan ConfigEvent
+	mux        sync.Mutex
+	closed     bool
+}
+
+func (client *ConfigClient) WatchConfig(ctx context.Context, param vo.ConfigParam, opts ...WatchOption) (<-chan ConfigEvent, error) {
+	watcher := &configWatcher{
+		ctx:        ctx,
+		bufferSize: defaultWatchBufferSize,
+	}
+	if client.ctx != nil {
+		watcher.clientDone = client.ctx.Done()
+	}
+	for _, opt := range opts {
+		opt(watcher)
+	}
+	if watcher.bufferSize <= 0 {
+		watcher.bufferSize = 1
+	}
+	watcher.events = make(chan ConfigEvent, watcher.bufferSize)
+
+	listener := newConfigListener(nil, watcher.send)
+	listener.isWatch = true
+	handle, err := client.addListener(ctx, param, listener)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-watcher.clientDone:
+		}
+		_ = client.RemoveConfigListener(handle)
+		watcher.close()
+		logger.Infof("Stop watching config DataId:%s Group:%s", param.DataId, param.Group)
+	}()
+	return watcher.events, nil
+}
+
+// send runs on the listener dispatcher, which hands it the changes of the watcher one at a time.
+func (watcher *configWatcher) send(event *model.ConfigChangeEvent) {
+	watcher.mux.Lock()
+	defer watcher.mux.Unlock()
+	if watcher.closed {
+		return
+	}
+	configEvent := ConfigEvent{ConfigChangeEvent: event}
+	if watcher.overflow == WatchBlock {
+		select {
+		case watcher.events <- configEvent:
+		case <-watcher.ctx.Done():
+		case <-watcher.clientDone:
+		}
+		return
+	}
+	select {
+	case watcher.events <- configEvent:
+		return
+	default:
+	}
+	// the channel is full, fold everything waiting in it into the new change
+	var merged *model.ConfigChangeEvent
+	count := 0
+	for drained := false; !drained; {
+		select {
+		case waiting := <-watcher.events:
+			merged = foldChangeEvent(merged, waiting.ConfigChangeEvent)
+			count += waiting.Merged + 1
+		default:
+			drained = true
+		}
+	}
+	if merged = foldChangeEvent(merged, event); merged != nil {
+		watcher.events <- ConfigEvent{ConfigChangeEvent: merged, Merged: count}
+	}
+}
+
+func (watcher *configWatcher) close() {
+	watcher.mux.Lock()
+	defer watcher.mux.Unlock()
+	if !watcher.closed {
+		watcher.closed = true
+		close(watcher.events)
+	}
+}
+
+// foldChangeEvent is mergeChangeEvent that also starts from nothing, pending is nil then.
+func foldChangeEvent(pending, next *model.ConfigChangeEvent) *model.ConfigChangeEvent {
+	if pending == nil {
+		return next
+	}
+	return mergeChangeEvent(pending, next, true)
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/util"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+func createConfigClientForWatch(t *testing.T, param vo.ConfigParam) (*ConfigClient, func(content string)) {
+	proxy := &MockConfigProxyWithContent{}
+	client := createConfigClientTest()
+	client.configProxy = proxy
+	clientConfig, _ := client.GetClientConfig()
+	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId)
+	return client, func(content string) {
+		proxy.mux.Lock()
+		proxy.content = content
+		proxy.mux.Unlock()
+		data, ok := client.cacheMap.Get(key)
+		assert.True(t, ok)
+		client.refreshContentAndCheck(data.(cacheData), false)
+	}
+}
+
+func receiveConfigEvent(t *testing.T, events <-chan ConfigEvent) ConfigEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no config event received")
+		return ConfigEvent{}
+	}
+}
+
+func waitListenersDispatched(t *testing.T, client *ConfigClient) {
+	assert.Eventually(t, func() bool {
+		client.listenerDispatcher.mux.Lock()
+		defer client.listenerDispatcher.mux.Unlock()
+		return len(client.listenerDispatcher.queues) == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestWatchConfig_SharedEntry(t *testing.T) {
+	param := vo.ConfigParam{DataId: "watch.properties", Group: localConfigTest.Group}
+	client, push := createConfigClientForWatch(t, param)
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+
+	events1, err := client.WatchConfig(ctx1, param)
+	assert.Nil(t, err)
+	events2, err := client.WatchConfig(ctx2, param)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, client.cacheMap.Count())
+
+	push("a=1")
+	for _, events := range []<-chan ConfigEvent{events1, events2} {
+		event := receiveConfigEvent(t, events)
+		assert.Equal(t, model.ConfigAdded, event.ChangeType)
+		assert.Equal(t, "a=1", event.NewContent)
+	}
+
+	// a listener joins the entry, cancelling it leaves the watches alone
+	listenParam := param
+	listenParam.OnChange = func(namespace, group, dataId, data string) {}
+	assert.Nil(t, client.ListenConfig(listenParam))
+	assert.Nil(t, client.CancelListenConfig(listenParam))
+	assert.Equal(t, 1, client.cacheMap.Count())
+
+	cancel1()
+	_, ok := <-events1
+	assert.False(t, ok)
+	assert.Equal(t, 1, client.cacheMap.Count())
+
+	push("a=2")
+	event := receiveConfigEvent(t, events2)
+	assert.Equal(t, "a=1", event.OldContent)
+	assert.Equal(t, []model.ConfigChangeItem{{Key: "a", OldValue: "1", NewValue: "2", Type: model.ConfigModified}},
+		event.ChangedItems)
+
+	cancel2()
+	_, ok = <-events2
+	assert.False(t, ok)
+	assert.True(t, client.cacheMap.IsEmpty())
+}
+
+func TestWatchConfig_NotifyInitial(t *testing.T) {
+	param := vo.ConfigParam{DataId: "watch-initial", Group: localConfigTest.Group, NotifyInitial: true}
+	client := createConfigClientWithContent("hello")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := client.WatchConfig(ctx, param)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	event := <-events
+	assert.Equal(t, model.ConfigAdded, event.ChangeType)
+	assert.Equal(t, "hello", event.NewContent)
+
+	_, err = createConfigClientForKms().WatchConfig(ctx, vo.ConfigParam{DataId: "watch-initial-without-snapshot",
+		Group: localConfigTest.Group, NotifyInitial: true})
+	assert.NotNil(t, err)
+}
+
+func TestWatchConfig_KeepLatest(t *testing.T) {
+	param := vo.ConfigParam{DataId: "watch-latest.properties", Group: localConfigTest.Group}
+	client, push := createConfigClientForWatch(t, param)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := client.WatchConfig(ctx, param, WithWatchBufferSize(1))
+	assert.Nil(t, err)
+	push("a=1")
+	push("a=2")
+	push("a=3")
+	waitListenersDispatched(t, client)
+
+	assert.Len(t, events, 1)
+	event := <-events
+	assert.Equal(t, 2, event.Merged)
+	assert.Equal(t, model.ConfigAdded, event.ChangeType)
+	assert.Equal(t, "", event.OldContent)
+	assert.Equal(t, "a=3", event.NewContent)
+}
+
+func TestWatchConfig_Block(t *testing.T) {
+	param := vo.ConfigParam{DataId: "watch-block", Group: localConfigTest.Group}
+	client, push := createConfigClientForWatch(t, param)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := client.WatchConfig(ctx, param, WithWatchBufferSize(1), WithWatchOverflowPolicy(WatchBlock))
+	assert.Nil(t, err)
+	push("1")
+	push("2")
+	push("3")
+	for _, content := range []string{"1", "2", "3"} {
+		event := receiveConfigEvent(t, events)
+		assert.Equal(t, content, event.NewContent)
+		assert.Equal(t, 0, event.Merged)
+	}
+
+	// a blocked change gives up when the watch ends
+	push("4")
+	push("5")
+	assert.Eventually(t, func() bool { return len(events) == 1 }, time.Second, time.Millisecond)
+	cancel()
+	waitListenersDispatched(t, client)
+}
@@ -17,13 +17,149 @@
 package config_client
 
 import (
+	"container/list"
+	"context"
 	"sync"
 	"time"
 
 	"github.com/nacos-group/nacos-sdk-go/v2/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/monitor"
+	"github.com/pkg/errors"
 	"golang.org/x/time/rate"
 )
 
+const (
+	defaultRateLimitKeyQps   = 5
+	defaultRateLimitKeyBurst = 5
+	defaultRateLimitMaxKeys  = 10000
+	rateLimitScopeKey        = "key"
+	rateLimitScopeGlobal     = "global"
+)
+
+// ErrRateLimited is returned, possibly wrapped, for config queries rejected by the rate limiter.
+var ErrRateLimited = errors.New("ConfigQueryRequest is limited")
+
+// rateLimiter limits the config queries of one client, per config and for the client as a whole.
+// The limiters of the configs are kept in LRU order, the least recently queried ones are evicted past MaxKeys.
+type rateLimiter struct {
+	keyQps   rate.Limit
+	keyBurst int
+	global   *rate.Limiter
+	maxWait  time.Duration
+	maxKeys  int
+	mux      sync.Mutex
+	keys     map[string]*list.Element
+	lru      *list.List
+}
+
+type keyRateLimiter struct {
+	key     string
+	limiter *rate.Limiter
+}
+
+// newRateLimiter returns nil if the rate limit is disabled.
+func newRateLimiter(config *constant.RateLimitConfig) *rateLimiter {
+	if config == nil {
+		config = &constant.RateLimitConfig{}
+	}
+	if config.Disable {
+		return nil
+	}
+	limiter := &rateLimiter{
+		keyQps:   rate.Limit(config.KeyQps),
+		keyBurst: config.KeyBurst,
+		maxWait:  time.Duration(config.MaxWaitMs) * time.Millisecond,
+		maxKeys:  config.MaxKeys,
+		keys:     make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+	if limiter.keyQps <= 0 {
+		limiter.keyQps = defaultRateLimitKeyQps
+	}
+	if limiter.keyBurst <= 0 {
+		limiter.keyBurst = defaultRateLimitKeyBurst
+	}
+	if limiter.maxKeys <= 0 {
+		limiter.maxKeys = defaultRateLimitMaxKeys
+	}
+	if config.GlobalQps > 0 {
+		globalBurst := config.GlobalBurst
+		if globalBurst <= 0 {
+			globalBurst = int(config.GlobalQps)
+		}
+		if globalBurst <= 0 {
+			globalBurst = 1
+		}
+		limiter.global = rate.NewLimiter(rate.Limit(config.GlobalQps), globalBurst)
+	}
+	return limiter
+}
+
+// acquire takes a token of key and of the client, waiting up to MaxWaitMs for them. It fails with
+// ErrRateLimited if the wait would be longer, or with the error of ctx if ctx ends first.
+func (limiter *rateLimiter) acquire(ctx context.Context, key string) error {
+	now := time.Now()
+	keyReservation := limiter.getKeyLimiter(key).ReserveN(now, 1)
+	delay, scope := keyReservation.DelayFrom(now), rateLimitScopeKey
+	var globalReservation *rate.Reservation
+	if limiter.global != nil {
+		globalReservation = limiter.global.ReserveN(now, 1)
+		if globalDelay := globalReservation.DelayFrom(now); globalDelay > delay {
+			delay, scope = globalDelay, rateLimitScopeGlobal
+		}
+	}
+	cancel := func() {
+		keyReservation.CancelAt(now)
+		if globalReservation != nil {
+			globalReservation.CancelAt(now)
+		}
+	}
+	if delay > limiter.maxWait {
+		cancel()
+		monitor.GetConfigRateLimitedMonitor(scope).Inc()
+		return errors.Wrapf(ErrRateLimited, "%s rate limit exceeded, key=%s", scope, key)
+	}
+	if delay <= 0 {
+		return nil
+	}
+	monitor.GetConfigRateLimitWaitMonitor().Inc()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		cancel()
+		return ctx.Err()
+	}
+}
+
+func (limiter *rateLimiter) getKeyLimiter(key string) *rate.Limiter {
+	limiter.mux.Lock()
+	defer limiter.mux.Unlock()
+	if element, ok := limiter.keys[key]; ok {
+		limiter.lru.MoveToFront(element)
+		return element.Value.(*keyRateLimiter).limiter
+	}
+	entry := &keyRateLimiter{key: key, limiter: rate.NewLimiter(limiter.keyQps, limiter.keyBurst)}
+	limiter.keys[key] = limiter.lru.PushFront(entry)
+	for limiter.lru.Len() > limiter.maxKeys {
+		oldest := limiter.lru.Back()
+		limiter.lru.Remove(oldest)
+		delete(limiter.keys, oldest.Value.(*keyRateLimiter).key)
+	}
+	return entry.limiter
+}
+
+// acquireRateLimit applies the rate limit of the client to a query of the config cached under key.
+func (client *ConfigClient) acquireRateLimit(ctx context.Context, key string) error {
+	if client == nil || client.rateLimiter == nil {
+		return nil
+	}
+	return client.rateLimiter.acquire(ctx, key)
+}
+
 type rateLimiterCheck struct {
 	rateLimiterCache cache.ConcurrentMap // cache
 	mux              sync.Mutex
@@ -39,6 +175,8 @@ func init() {
 }
 
 // IsLimited return true when request is limited
+//
+// Deprecated: config queries are limited by the client itself, see constant.ClientConfig.RateLimit.
 func IsLimited(checkKey string) bool {
 	checker.mux.Lock()
 	defer checker.mux.Unlock()
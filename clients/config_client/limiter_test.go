@@ -17,9 +17,13 @@
 package config_client
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -46,3 +50,61 @@ func TestLimiter(t *testing.T) {
 		}
 	}
 }
+
+func TestLimiterIsPerClient(t *testing.T) {
+	client := createConfigClientTest()
+	other := createConfigClientTest()
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, client.acquireRateLimit(context.Background(), "dataId@@group@@"))
+	}
+	assert.True(t, errors.Is(client.acquireRateLimit(context.Background(), "dataId@@group@@"), ErrRateLimited))
+	assert.Nil(t, other.acquireRateLimit(context.Background(), "dataId@@group@@"))
+}
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		assert.Nil(t, newRateLimiter(&constant.RateLimitConfig{Disable: true}))
+		client := &ConfigClient{}
+		assert.Nil(t, client.acquireRateLimit(context.Background(), "key"))
+	})
+
+	t.Run("global", func(t *testing.T) {
+		limiter := newRateLimiter(&constant.RateLimitConfig{GlobalQps: 2})
+		assert.Nil(t, limiter.acquire(context.Background(), "key1"))
+		assert.Nil(t, limiter.acquire(context.Background(), "key2"))
+		err := limiter.acquire(context.Background(), "key3")
+		assert.True(t, errors.Is(err, ErrRateLimited))
+		assert.Contains(t, err.Error(), "global")
+		// the rejected query gives the token of its key back
+		assert.Equal(t, 5, int(limiter.getKeyLimiter("key3").Tokens()))
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		limiter := newRateLimiter(&constant.RateLimitConfig{KeyQps: 20, KeyBurst: 1, MaxWaitMs: 500})
+		assert.Nil(t, limiter.acquire(context.Background(), "key"))
+		start := time.Now()
+		assert.Nil(t, limiter.acquire(context.Background(), "key"))
+		assert.True(t, time.Since(start) >= 40*time.Millisecond)
+	})
+
+	t.Run("wait canceled", func(t *testing.T) {
+		limiter := newRateLimiter(&constant.RateLimitConfig{KeyQps: 1, KeyBurst: 1, MaxWaitMs: 5000})
+		assert.Nil(t, limiter.acquire(context.Background(), "key"))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, limiter.acquire(ctx, "key"))
+	})
+
+	t.Run("evict idle keys", func(t *testing.T) {
+		limiter := newRateLimiter(&constant.RateLimitConfig{KeyBurst: 1, MaxKeys: 2})
+		assert.Nil(t, limiter.acquire(context.Background(), "key1"))
+		assert.Nil(t, limiter.acquire(context.Background(), "key2"))
+		assert.NotNil(t, limiter.acquire(context.Background(), "key1"))
+		assert.Nil(t, limiter.acquire(context.Background(), "key3"))
+		assert.Equal(t, 2, limiter.lru.Len())
+		_, ok := limiter.keys["key2"]
+		assert.False(t, ok)
+		// key2 comes back with a full bucket
+		assert.Nil(t, limiter.acquire(context.Background(), "key2"))
+	})
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/monitor"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+const (
+	defaultListenerWorkerNum = 8
+	defaultListenerQueueSize = 64
+)
+
+// listenerTask is one change waiting to be delivered to one listener.
+type listenerTask struct {
+	event    *model.ConfigChangeEvent
+	withDiff bool
+	handler  vo.ConfigChangeListener
+}
+
+// listenerQueue holds the pending changes of one listener, at most one worker runs them at a time.
+type listenerQueue struct {
+	key       string
+	tasks     []*listenerTask
+	scheduled bool
+}
+
+// listenerDispatcher runs config listeners on a fixed number of workers. Changes of the same listener are
+// delivered one by one in the order they were dispatched, a panicking listener is recovered and counted.
+// With coalesce on, changes piling up behind a busy listener are merged into one carrying the latest content.
+// Without it, changes are merged that way only once queueSize of them are pending, so a stuck listener can't
+// hold an unbounded backlog.
+type listenerDispatcher struct {
+	mux       sync.Mutex
+	cond      *sync.Cond
+	queues    map[string]*listenerQueue
+	ready     []*listenerQueue
+	queueSize int
+	coalesce  bool
+	closed    bool
+}
+
+func newListenerDispatcher(ctx context.Context, workerNum, queueSize int, coalesce bool) *listenerDispatcher {
+	if workerNum <= 0 {
+		workerNum = defaultListenerWorkerNum
+	}
+	if queueSize <= 0 {
+		queueSize = defaultListenerQueueSize
+	}
+	dispatcher := &listenerDispatcher{
+		queues:    make(map[string]*listenerQueue),
+		queueSize: queueSize,
+		coalesce:  coalesce,
+	}
+	dispatcher.cond = sync.NewCond(&dispatcher.mux)
+	for i := 0; i < workerNum; i++ {
+		go dispatcher.work()
+	}
+	go func() {
+		<-ctx.Done()
+		dispatcher.close()
+	}()
+	return dispatcher
+}
+
+// dispatch queues event for the listener identified by key. withDiff tells whether ChangedItems has to be
+// recomputed when the event is merged with a pending one.
+func (dispatcher *listenerDispatcher) dispatch(key string, event *model.ConfigChangeEvent, withDiff bool,
+	handler vo.ConfigChangeListener) {
+	dispatcher.mux.Lock()
+	defer dispatcher.mux.Unlock()
+	if dispatcher.closed {
+		logger.Debugf("listener dispatcher is closed, drop change of dataId=%s, group=%s", event.DataId, event.Group)
+		return
+	}
+	queue, ok := dispatcher.queues[key]
+	if !ok {
+		queue = &listenerQueue{key: key}
+		dispatcher.queues[key] = queue
+	}
+	full := len(queue.tasks) >= dispatcher.queueSize
+	if (dispatcher.coalesce || full) && len(queue.tasks) > 0 {
+		if full && !dispatcher.coalesce {
+			monitor.GetListenerQueueFullMonitor().Inc()
+			logger.Debugf("listener queue of key=%s is full, merge change of dataId=%s, group=%s into the last pending one",
+				key, event.DataId, event.Group)
+		}
+		pending := queue.tasks[len(queue.tasks)-1]
+		pending.handler = handler
+		pending.withDiff = pending.withDiff || withDiff
+		merged := mergeChangeEvent(pending.event, event, pending.withDiff)
+		monitor.GetListenerCoalescedMonitor().Inc()
+		if merged != nil {
+			pending.event = merged
+			return
+		}
+		// the pending changes cancel each other out, the still scheduled queue is dropped by take or done
+		queue.tasks = queue.tasks[:len(queue.tasks)-1]
+		monitor.GetListenerPendingMonitor().Dec()
+		return
+	}
+	queue.tasks = append(queue.tasks, &listenerTask{event: event, withDiff: withDiff, handler: handler})
+	monitor.GetListenerPendingMonitor().Inc()
+	if !queue.scheduled {
+		queue.scheduled = true
+		dispatcher.ready = append(dispatcher.ready, queue)
+		dispatcher.cond.Signal()
+	}
+}
+
+func (dispatcher *listenerDispatcher) work() {
+	for {
+		queue, task := dispatcher.take()
+		if task == nil {
+			return
+		}
+		runListener(queue.key, task)
+		dispatcher.done(queue)
+	}
+}
+
+// take blocks until a queue is ready and pops its first task, the queue stays scheduled until done.
+func (dispatcher *listenerDispatcher) take() (*listenerQueue, *listenerTask) {
+	dispatcher.mux.Lock()
+	defer dispatcher.mux.Unlock()
+	for {
+		for len(dispatcher.ready) == 0 && !dispatcher.closed {
+			dispatcher.cond.Wait()
+		}
+		if dispatcher.closed {
+			return nil, nil
+		}
+		queue := dispatcher.ready[0]
+		dispatcher.ready[0] = nil
+		dispatcher.ready = dispatcher.ready[1:]
+		if len(queue.tasks) == 0 {
+			queue.scheduled = false
+			delete(dispatcher.queues, queue.key)
+			continue
+		}
+		task := queue.tasks[0]
+		queue.tasks[0] = nil
+		queue.tasks = queue.tasks[1:]
+		monitor.GetListenerPendingMonitor().Dec()
+		return queue, task
+	}
+}
+
+func (dispatcher *listenerDispatcher) done(queue *listenerQueue) {
+	dispatcher.mux.Lock()
+	defer dispatcher.mux.Unlock()
+	if len(queue.tasks) > 0 {
+		dispatcher.ready = append(dispatcher.ready, queue)
+		dispatcher.cond.Signal()
+		return
+	}
+	queue.scheduled = false
+	delete(dispatcher.queues, queue.key)
+}
+
+func (dispatcher *listenerDispatcher) close() {
+	dispatcher.mux.Lock()
+	defer dispatcher.mux.Unlock()
+	dispatcher.closed = true
+	dispatcher.cond.Broadcast()
+}
+
+func runListener(key string, task *listenerTask) {
+	defer func() {
+		if r := recover(); r != nil {
+			monitor.GetListenerPanicMonitor().Inc()
+			logger.Errorf("config listener panic, key=%s, dataId=%s, group=%s, err:%v\n%s", key,
+				task.event.DataId, task.event.Group, r, debug.Stack())
+		}
+	}()
+	task.handler(task.event)
+}
+
+// mergeChangeEvent folds next into pending as if the listener had only seen the latest content,
+// nil is returned if the content ends up unchanged.
+func mergeChangeEvent(pending, next *model.ConfigChangeEvent, withDiff bool) *model.ConfigChangeEvent {
+	merged := *next
+	merged.OldContent = pending.OldContent
+	merged.OldMd5 = pending.OldMd5
+	merged.ChangedItems = nil
+	switch {
+	case next.ChangeType == model.ConfigDeleted:
+		if pending.ChangeType == model.ConfigAdded {
+			return nil
+		}
+	case len(merged.OldMd5) == 0:
+		merged.ChangeType = model.ConfigAdded
+	case merged.OldMd5 == merged.NewMd5:
+		return nil
+	default:
+		merged.ChangeType = model.ConfigModified
+	}
+	if withDiff {
+		merged.ChangedItems = diffConfigContent(diffConfigType(merged.ContentType, merged.DataId),
+			merged.OldContent, merged.NewContent)
+	}
+	return &merged
+}
+
+// dispatchListener hands event to the dispatcher, clients not built by NewConfigClient fall back to a goroutine.
+func (client *ConfigClient) dispatchListener(key string, event *model.ConfigChangeEvent, withDiff bool,
+	handler vo.ConfigChangeListener) {
+	if client.listenerDispatcher == nil {
+		go runListener(key, &listenerTask{event: event, withDiff: withDiff, handler: handler})
+		return
+	}
+	client.listenerDispatcher.dispatch(key, event, withDiff, handler)
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/monitor"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestChangeEvent(oldContent, newContent string) *model.ConfigChangeEvent {
+	event := &model.ConfigChangeEvent{
+		DataId:     "dispatcher.properties",
+		Group:      "DEFAULT_GROUP",
+		OldContent: oldContent,
+		NewContent: newContent,
+		NewMd5:     newContent,
+		ChangeType: model.ConfigModified,
+	}
+	if len(oldContent) > 0 {
+		event.OldMd5 = oldContent
+	} else {
+		event.ChangeType = model.ConfigAdded
+	}
+	return event
+}
+
+func Test_ListenerDispatcherKeepsOrderPerKey(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dispatcher := newListenerDispatcher(ctx, 4, 0, false)
+
+	var mux sync.Mutex
+	received := make(map[string][]string)
+	var wg sync.WaitGroup
+	handler := func(key string) func(event *model.ConfigChangeEvent) {
+		return func(event *model.ConfigChangeEvent) {
+			defer wg.Done()
+			time.Sleep(time.Millisecond)
+			mux.Lock()
+			received[key] = append(received[key], event.NewContent)
+			mux.Unlock()
+		}
+	}
+	for i := 0; i < 20; i++ {
+		for _, key := range []string{"a", "b", "c"} {
+			wg.Add(1)
+			dispatcher.dispatch(key, newTestChangeEvent("", strconv.Itoa(i)), false, handler(key))
+		}
+	}
+	wg.Wait()
+
+	for _, key := range []string{"a", "b", "c"} {
+		assert.Equal(t, 20, len(received[key]))
+		for i, content := range received[key] {
+			assert.Equal(t, strconv.Itoa(i), content)
+		}
+	}
+}
+
+func Test_ListenerDispatcherRecoversPanic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dispatcher := newListenerDispatcher(ctx, 1, 0, false)
+
+	done := make(chan string, 1)
+	dispatcher.dispatch("a", newTestChangeEvent("", "1"), false, func(event *model.ConfigChangeEvent) {
+		panic("listener failed")
+	})
+	dispatcher.dispatch("a", newTestChangeEvent("1", "2"), false, func(event *model.ConfigChangeEvent) {
+		done <- event.NewContent
+	})
+
+	select {
+	case content := <-done:
+		assert.Equal(t, "2", content)
+	case <-time.After(3 * time.Second):
+		t.Fatal("listener after a panicking one was not called")
+	}
+}
+
+func Test_ListenerDispatcherCoalesce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dispatcher := newListenerDispatcher(ctx, 2, 0, true)
+
+	block := make(chan struct{})
+	received := make(chan *model.ConfigChangeEvent, 10)
+	handler := func(event *model.ConfigChangeEvent) {
+		<-block
+		received <- event
+	}
+	dispatcher.dispatch("a", newTestChangeEvent("", "a=1"), true, handler)
+	// wait for the first change to be taken, the following ones pile up behind it
+	assert.Eventually(t, func() bool {
+		dispatcher.mux.Lock()
+		defer dispatcher.mux.Unlock()
+		return len(dispatcher.queues["a"].tasks) == 0
+	}, 3*time.Second, time.Millisecond)
+	dispatcher.dispatch("a", newTestChangeEvent("a=1", "a=2"), true, handler)
+	dispatcher.dispatch("a", newTestChangeEvent("a=2", "a=3"), true, handler)
+	close(block)
+
+	first := <-received
+	assert.Equal(t, "a=1", first.NewContent)
+	second := <-received
+	assert.Equal(t, "a=1", second.OldContent)
+	assert.Equal(t, "a=3", second.NewContent)
+	assert.Equal(t, model.ConfigModified, second.ChangeType)
+	assert.Equal(t, []model.ConfigChangeItem{{Key: "a", OldValue: "1", NewValue: "3", Type: model.ConfigModified}},
+		second.ChangedItems)
+	select {
+	case event := <-received:
+		t.Fatalf("unexpected change %s", event.NewContent)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func Test_ListenerDispatcherQueueFull(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dispatcher := newListenerDispatcher(ctx, 1, 2, false)
+	queueFull := testutil.ToFloat64(monitor.GetListenerQueueFullMonitor())
+
+	block := make(chan struct{})
+	received := make(chan *model.ConfigChangeEvent, 10)
+	handler := func(event *model.ConfigChangeEvent) {
+		<-block
+		received <- event
+	}
+	dispatcher.dispatch("a", newTestChangeEvent("", "a=1"), true, handler)
+	assert.Eventually(t, func() bool {
+		dispatcher.mux.Lock()
+		defer dispatcher.mux.Unlock()
+		return len(dispatcher.queues["a"].tasks) == 0
+	}, 3*time.Second, time.Millisecond)
+	// the queue takes two changes, the next ones are merged into the last of them
+	for i := 1; i < 5; i++ {
+		dispatcher.dispatch("a", newTestChangeEvent("a="+strconv.Itoa(i), "a="+strconv.Itoa(i+1)), true, handler)
+	}
+	dispatcher.mux.Lock()
+	assert.Len(t, dispatcher.queues["a"].tasks, 2)
+	dispatcher.mux.Unlock()
+	assert.Equal(t, float64(2), testutil.ToFloat64(monitor.GetListenerQueueFullMonitor())-queueFull)
+	close(block)
+
+	assert.Equal(t, "a=1", (<-received).NewContent)
+	second := <-received
+	assert.Equal(t, "a=1", second.OldContent)
+	assert.Equal(t, "a=2", second.NewContent)
+	third := <-received
+	assert.Equal(t, "a=2", third.OldContent)
+	assert.Equal(t, "a=5", third.NewContent)
+	assert.Equal(t, []model.ConfigChangeItem{{Key: "a", OldValue: "2", NewValue: "5", Type: model.ConfigModified}},
+		third.ChangedItems)
+	select {
+	case event := <-received:
+		t.Fatalf("unexpected change %s", event.NewContent)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func Test_ListenerDispatcherClose(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcher := newListenerDispatcher(ctx, 1, 0, false)
+	cancel()
+	assert.Eventually(t, func() bool {
+		dispatcher.mux.Lock()
+		defer dispatcher.mux.Unlock()
+		return dispatcher.closed
+	}, 3*time.Second, time.Millisecond)
+
+	called := make(chan struct{}, 1)
+	dispatcher.dispatch("a", newTestChangeEvent("", "1"), false, func(event *model.ConfigChangeEvent) {
+		called <- struct{}{}
+	})
+	select {
+	case <-called:
+		t.Fatal("listener called after the dispatcher was closed")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func Test_MergeChangeEvent(t *testing.T) {
+	t.Run("added then modified", func(t *testing.T) {
+		merged := mergeChangeEvent(newTestChangeEvent("", "1"), newTestChangeEvent("1", "2"), false)
+		assert.Equal(t, model.ConfigAdded, merged.ChangeType)
+		assert.Equal(t, "", merged.OldContent)
+		assert.Equal(t, "2", merged.NewContent)
+	})
+
+	t.Run("changed back", func(t *testing.T) {
+		assert.Nil(t, mergeChangeEvent(newTestChangeEvent("1", "2"), newTestChangeEvent("2", "1"), false))
+	})
+
+	t.Run("added then deleted", func(t *testing.T) {
+		deleted := newTestChangeEvent("1", "")
+		deleted.ChangeType = model.ConfigDeleted
+		assert.Nil(t, mergeChangeEvent(newTestChangeEvent("", "1"), deleted, false))
+	})
+
+	t.Run("modified then deleted", func(t *testing.T) {
+		deleted := newTestChangeEvent("2", "")
+		deleted.ChangeType = model.ConfigDeleted
+		merged := mergeChangeEvent(newTestChangeEvent("1", "2"), deleted, false)
+		assert.Equal(t, model.ConfigDeleted, merged.ChangeType)
+		assert.Equal(t, "1", merged.OldContent)
+	})
+}
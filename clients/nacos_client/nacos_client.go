@@ -49,6 +49,14 @@ func (client *NacosClient) SetClientConfig(config constant.ClientConfig) (err er
 		config.UpdateThreadNum = 20
 	}
 
+	if config.ListenerWorkerNum <= 0 {
+		config.ListenerWorkerNum = 8
+	}
+
+	if config.ListenerQueueSize <= 0 {
+		config.ListenerQueueSize = 64
+	}
+
 	if len(config.LogLevel) == 0 {
 		config.LogLevel = "info"
 	}
@@ -38,6 +38,9 @@ import (
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
 )
 
+// listAllServicesPageSize is the page size of ListAllServices if none is given
+const listAllServicesPageSize = 100
+
 // NamingClient ...
 type NamingClient struct {
 	nacos_client.INacosClient
@@ -103,6 +106,11 @@ func initLogger(clientConfig constant.ClientConfig) error {
 
 // RegisterInstance ...
 func (sc *NamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool, error) {
+	return sc.RegisterInstanceWithContext(context.Background(), param)
+}
+
+// RegisterInstanceWithContext ...
+func (sc *NamingClient) RegisterInstanceWithContext(ctx context.Context, param vo.RegisterInstanceParam) (bool, error) {
 	if param.ServiceName == "" {
 		return false, errors.New("serviceName cannot be empty!")
 	}
@@ -122,10 +130,15 @@ func (sc *NamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool,
 		Weight:      param.Weight,
 		Ephemeral:   param.Ephemeral,
 	}
-	return sc.serviceProxy.RegisterInstance(param.ServiceName, param.GroupName, instance)
+	return sc.serviceProxy.RegisterInstance(ctx, param.ServiceName, param.GroupName, instance)
 }
 
 func (sc *NamingClient) BatchRegisterInstance(param vo.BatchRegisterInstanceParam) (bool, error) {
+	return sc.BatchRegisterInstanceWithContext(context.Background(), param)
+}
+
+// BatchRegisterInstanceWithContext ...
+func (sc *NamingClient) BatchRegisterInstanceWithContext(ctx context.Context, param vo.BatchRegisterInstanceParam) (bool, error) {
 	if param.ServiceName == "" {
 		return false, errors.New("serviceName cannot be empty!")
 	}
@@ -152,11 +165,16 @@ func (sc *NamingClient) BatchRegisterInstance(param vo.BatchRegisterInstancePara
 		})
 	}
 
-	return sc.serviceProxy.BatchRegisterInstance(param.ServiceName, param.GroupName, modelInstances)
+	return sc.serviceProxy.BatchRegisterInstance(ctx, param.ServiceName, param.GroupName, modelInstances)
 }
 
 // DeregisterInstance ...
 func (sc *NamingClient) DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error) {
+	return sc.DeregisterInstanceWithContext(context.Background(), param)
+}
+
+// DeregisterInstanceWithContext ...
+func (sc *NamingClient) DeregisterInstanceWithContext(ctx context.Context, param vo.DeregisterInstanceParam) (bool, error) {
 	if param.ServiceName == "" {
 		return false, errors.New("serviceName cannot be empty!")
 	}
@@ -169,11 +187,16 @@ func (sc *NamingClient) DeregisterInstance(param vo.DeregisterInstanceParam) (bo
 		ClusterName: param.Cluster,
 		Ephemeral:   param.Ephemeral,
 	}
-	return sc.serviceProxy.DeregisterInstance(param.ServiceName, param.GroupName, instance)
+	return sc.serviceProxy.DeregisterInstance(ctx, param.ServiceName, param.GroupName, instance)
 }
 
 // UpdateInstance ...
 func (sc *NamingClient) UpdateInstance(param vo.UpdateInstanceParam) (bool, error) {
+	return sc.UpdateInstanceWithContext(context.Background(), param)
+}
+
+// UpdateInstanceWithContext ...
+func (sc *NamingClient) UpdateInstanceWithContext(ctx context.Context, param vo.UpdateInstanceParam) (bool, error) {
 	if param.ServiceName == "" {
 		return false, errors.New("serviceName cannot be empty!")
 	}
@@ -194,12 +217,17 @@ func (sc *NamingClient) UpdateInstance(param vo.UpdateInstanceParam) (bool, erro
 		Ephemeral:   param.Ephemeral,
 	}
 
-	return sc.serviceProxy.RegisterInstance(param.ServiceName, param.GroupName, instance)
+	return sc.serviceProxy.RegisterInstance(ctx, param.ServiceName, param.GroupName, instance)
 
 }
 
 // GetService Get service info by Group and DataId, clusters was optional
 func (sc *NamingClient) GetService(param vo.GetServiceParam) (service model.Service, err error) {
+	return sc.GetServiceWithContext(context.Background(), param)
+}
+
+// GetServiceWithContext ...
+func (sc *NamingClient) GetServiceWithContext(ctx context.Context, param vo.GetServiceParam) (service model.Service, err error) {
 	if param.ServiceName == "" {
 		return model.Service{}, errors.New("serviceName cannot be empty!")
 	}
@@ -211,7 +239,7 @@ func (sc *NamingClient) GetService(param vo.GetServiceParam) (service model.Serv
 	clusters := strings.Join(param.Clusters, ",")
 	service, ok = sc.serviceInfoHolder.GetServiceInfo(param.ServiceName, param.GroupName, "")
 	if !ok {
-		service, err = sc.serviceProxy.Subscribe(param.ServiceName, param.GroupName, "")
+		service, err = sc.serviceProxy.Subscribe(ctx, param.ServiceName, param.GroupName, "")
 	}
 	service.Clusters = clusters
 	service.Hosts = clusterSelector.SelectInstance(&service)
@@ -220,6 +248,11 @@ func (sc *NamingClient) GetService(param vo.GetServiceParam) (service model.Serv
 
 // GetAllServicesInfo Get all instance by Namespace and Group with page
 func (sc *NamingClient) GetAllServicesInfo(param vo.GetAllServiceInfoParam) (model.ServiceList, error) {
+	return sc.GetAllServicesInfoWithContext(context.Background(), param)
+}
+
+// GetAllServicesInfoWithContext ...
+func (sc *NamingClient) GetAllServicesInfoWithContext(ctx context.Context, param vo.GetAllServiceInfoParam) (model.ServiceList, error) {
 	if len(param.GroupName) == 0 {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
@@ -231,12 +264,57 @@ func (sc *NamingClient) GetAllServicesInfo(param vo.GetAllServiceInfoParam) (mod
 			param.NameSpace = clientConfig.NamespaceId
 		}
 	}
-	services, err := sc.serviceProxy.GetServiceList(param.PageNo, param.PageSize, param.GroupName, param.NameSpace, &model.ExpressionSelector{})
+	services, err := sc.serviceProxy.GetServiceList(ctx, param.PageNo, param.PageSize, param.GroupName, param.NameSpace, &model.ExpressionSelector{})
 	return services, err
 }
 
+// ListAllServices Get all service names by Namespace and Group, see ListAllServicesWithContext
+func (sc *NamingClient) ListAllServices(param vo.GetAllServiceInfoParam) func(yield func(string, error) bool) {
+	return sc.ListAllServicesWithContext(context.Background(), param)
+}
+
+// ListAllServicesWithContext returns an iterator of iter.Seq2[string, error] shape over the service names. The pages
+// are fetched one by one as the iteration proceeds, starting from param.PageNo, and the iteration stops after
+// param.MaxItems services. A failed page is yielded as an error and ends the iteration.
+func (sc *NamingClient) ListAllServicesWithContext(ctx context.Context, param vo.GetAllServiceInfoParam) func(yield func(string, error) bool) {
+	return func(yield func(string, error) bool) {
+		pageParam := param
+		if pageParam.PageNo == 0 {
+			pageParam.PageNo = 1
+		}
+		if pageParam.PageSize == 0 {
+			pageParam.PageSize = listAllServicesPageSize
+		}
+		var count uint32
+		for ; param.MaxItems == 0 || count < param.MaxItems; pageParam.PageNo++ {
+			services, err := sc.GetAllServicesInfoWithContext(ctx, pageParam)
+			if err != nil {
+				yield("", err)
+				return
+			}
+			for _, service := range services.Doms {
+				if param.MaxItems > 0 && count >= param.MaxItems {
+					return
+				}
+				count++
+				if !yield(service, nil) {
+					return
+				}
+			}
+			if len(services.Doms) == 0 || int64(pageParam.PageNo)*int64(pageParam.PageSize) >= services.Count {
+				return
+			}
+		}
+	}
+}
+
 // SelectAllInstances Get all instance by DataId 和 Group
 func (sc *NamingClient) SelectAllInstances(param vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	return sc.SelectAllInstancesWithContext(context.Background(), param)
+}
+
+// SelectAllInstancesWithContext ...
+func (sc *NamingClient) SelectAllInstancesWithContext(ctx context.Context, param vo.SelectAllInstancesParam) ([]model.Instance, error) {
 	if param.ServiceName == "" {
 		return nil, errors.New("serviceName cannot be empty!")
 	}
@@ -251,7 +329,7 @@ func (sc *NamingClient) SelectAllInstances(param vo.SelectAllInstancesParam) ([]
 	clusterSelector := naming_cache.NewClusterSelector(param.Clusters)
 	service, ok = sc.serviceInfoHolder.GetServiceInfo(param.ServiceName, param.GroupName, "")
 	if !ok {
-		service, err = sc.serviceProxy.Subscribe(param.ServiceName, param.GroupName, "")
+		service, err = sc.serviceProxy.Subscribe(ctx, param.ServiceName, param.GroupName, "")
 	}
 	if err != nil {
 		return []model.Instance{}, err
@@ -265,6 +343,11 @@ func (sc *NamingClient) SelectAllInstances(param vo.SelectAllInstancesParam) ([]
 
 // SelectInstances Get all instance by DataId, Group and Health
 func (sc *NamingClient) SelectInstances(param vo.SelectInstancesParam) ([]model.Instance, error) {
+	return sc.SelectInstancesWithContext(context.Background(), param)
+}
+
+// SelectInstancesWithContext ...
+func (sc *NamingClient) SelectInstancesWithContext(ctx context.Context, param vo.SelectInstancesParam) ([]model.Instance, error) {
 	if param.ServiceName == "" {
 		return nil, errors.New("serviceName cannot be empty!")
 	}
@@ -279,7 +362,7 @@ func (sc *NamingClient) SelectInstances(param vo.SelectInstancesParam) ([]model.
 	clusterSelector := naming_cache.NewClusterSelector(param.Clusters)
 	service, ok = sc.serviceInfoHolder.GetServiceInfo(param.ServiceName, param.GroupName, "")
 	if !ok {
-		service, err = sc.serviceProxy.Subscribe(param.ServiceName, param.GroupName, "")
+		service, err = sc.serviceProxy.Subscribe(ctx, param.ServiceName, param.GroupName, "")
 		if err != nil {
 			return nil, err
 		}
@@ -305,6 +388,11 @@ func (sc *NamingClient) selectInstances(service model.Service, healthy bool) ([]
 
 // SelectOneHealthyInstance Get one healthy instance by DataId and Group
 func (sc *NamingClient) SelectOneHealthyInstance(param vo.SelectOneHealthInstanceParam) (*model.Instance, error) {
+	return sc.SelectOneHealthyInstanceWithContext(context.Background(), param)
+}
+
+// SelectOneHealthyInstanceWithContext ...
+func (sc *NamingClient) SelectOneHealthyInstanceWithContext(ctx context.Context, param vo.SelectOneHealthInstanceParam) (*model.Instance, error) {
 	if param.ServiceName == "" {
 		return nil, errors.New("serviceName cannot be empty!")
 	}
@@ -319,7 +407,7 @@ func (sc *NamingClient) SelectOneHealthyInstance(param vo.SelectOneHealthInstanc
 	clusterSelector := naming_cache.NewClusterSelector(param.Clusters)
 	service, ok = sc.serviceInfoHolder.GetServiceInfo(param.ServiceName, param.GroupName, "")
 	if !ok {
-		service, err = sc.serviceProxy.Subscribe(param.ServiceName, param.GroupName, "")
+		service, err = sc.serviceProxy.Subscribe(ctx, param.ServiceName, param.GroupName, "")
 		if err != nil {
 			return nil, err
 		}
@@ -354,6 +442,11 @@ func (sc *NamingClient) selectOneHealthyInstances(service model.Service) (*model
 
 // Subscribe ...
 func (sc *NamingClient) Subscribe(param *vo.SubscribeParam) error {
+	return sc.SubscribeWithContext(context.Background(), param)
+}
+
+// SubscribeWithContext ...
+func (sc *NamingClient) SubscribeWithContext(ctx context.Context, param *vo.SubscribeParam) error {
 	if param.ServiceName == "" {
 		return errors.New("serviceName cannot be empty!")
 	}
@@ -363,12 +456,17 @@ func (sc *NamingClient) Subscribe(param *vo.SubscribeParam) error {
 	clusterSelector := naming_cache.NewClusterSelector(param.Clusters)
 	callbackWrapper := naming_cache.NewSubscribeCallbackFuncWrapper(clusterSelector, &param.SubscribeCallback)
 	sc.serviceInfoHolder.RegisterCallback(util.GetGroupName(param.ServiceName, param.GroupName), "", callbackWrapper)
-	_, err := sc.serviceProxy.Subscribe(param.ServiceName, param.GroupName, "")
+	_, err := sc.serviceProxy.Subscribe(ctx, param.ServiceName, param.GroupName, "")
 	return err
 }
 
 // Unsubscribe ...
 func (sc *NamingClient) Unsubscribe(param *vo.SubscribeParam) (err error) {
+	return sc.UnsubscribeWithContext(context.Background(), param)
+}
+
+// UnsubscribeWithContext ...
+func (sc *NamingClient) UnsubscribeWithContext(ctx context.Context, param *vo.SubscribeParam) (err error) {
 	if param.ServiceName == "" {
 		return errors.New("serviceName cannot be empty!")
 	}
@@ -377,7 +475,7 @@ func (sc *NamingClient) Unsubscribe(param *vo.SubscribeParam) (err error) {
 	serviceFullName := util.GetGroupName(param.ServiceName, param.GroupName)
 	sc.serviceInfoHolder.DeregisterCallback(serviceFullName, "", callbackWrapper)
 	if !sc.serviceInfoHolder.IsSubscribed(serviceFullName, "") {
-		err = sc.serviceProxy.Unsubscribe(param.ServiceName, param.GroupName, "")
+		err = sc.serviceProxy.Unsubscribe(ctx, param.ServiceName, param.GroupName, "")
 	}
 
 	return err
@@ -17,6 +17,8 @@
 package naming_client
 
 import (
+	"context"
+
 	"github.com/nacos-group/nacos-sdk-go/v2/model"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
 )
@@ -112,9 +114,53 @@ type INamingClient interface {
 	// GetAllServicesInfo use to get all service info by page
 	GetAllServicesInfo(param vo.GetAllServiceInfoParam) (model.ServiceList, error)
 
+	// ListAllServices use to get the names of all services, the pages are fetched lazily
+	// the result has the shape of iter.Seq2[string, error], range over it with go 1.23 or later
+	// PageNo optional, the page to start from, default:1
+	// PageSize optional, default:100
+	// MaxItems optional, stop after that many services, default is all of them
+	ListAllServices(param vo.GetAllServiceInfoParam) func(yield func(string, error) bool)
+
 	// ServerHealthy use to check the connectivity to server
 	ServerHealthy() bool
 
+	// RegisterInstanceWithContext is RegisterInstance bound to ctx, it stops waiting and returns ctx.Err()
+	// once ctx is cancelled or its deadline expires, the other ...WithContext methods behave the same way
+	RegisterInstanceWithContext(ctx context.Context, param vo.RegisterInstanceParam) (bool, error)
+
+	// BatchRegisterInstanceWithContext is BatchRegisterInstance bound to ctx
+	BatchRegisterInstanceWithContext(ctx context.Context, param vo.BatchRegisterInstanceParam) (bool, error)
+
+	// DeregisterInstanceWithContext is DeregisterInstance bound to ctx
+	DeregisterInstanceWithContext(ctx context.Context, param vo.DeregisterInstanceParam) (bool, error)
+
+	// UpdateInstanceWithContext is UpdateInstance bound to ctx
+	UpdateInstanceWithContext(ctx context.Context, param vo.UpdateInstanceParam) (bool, error)
+
+	// GetServiceWithContext is GetService bound to ctx
+	GetServiceWithContext(ctx context.Context, param vo.GetServiceParam) (model.Service, error)
+
+	// SelectAllInstancesWithContext is SelectAllInstances bound to ctx
+	SelectAllInstancesWithContext(ctx context.Context, param vo.SelectAllInstancesParam) ([]model.Instance, error)
+
+	// SelectInstancesWithContext is SelectInstances bound to ctx
+	SelectInstancesWithContext(ctx context.Context, param vo.SelectInstancesParam) ([]model.Instance, error)
+
+	// SelectOneHealthyInstanceWithContext is SelectOneHealthyInstance bound to ctx
+	SelectOneHealthyInstanceWithContext(ctx context.Context, param vo.SelectOneHealthInstanceParam) (*model.Instance, error)
+
+	// SubscribeWithContext is Subscribe bound to ctx
+	SubscribeWithContext(ctx context.Context, param *vo.SubscribeParam) error
+
+	// UnsubscribeWithContext is Unsubscribe bound to ctx
+	UnsubscribeWithContext(ctx context.Context, param *vo.SubscribeParam) error
+
+	// GetAllServicesInfoWithContext is GetAllServicesInfo bound to ctx
+	GetAllServicesInfoWithContext(ctx context.Context, param vo.GetAllServiceInfoParam) (model.ServiceList, error)
+
+	// ListAllServicesWithContext is ListAllServices bound to ctx
+	ListAllServicesWithContext(ctx context.Context, param vo.GetAllServiceInfoParam) func(yield func(string, error) bool)
+
 	//CloseClient close the GRPC client
 	CloseClient()
 }
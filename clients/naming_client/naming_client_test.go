@@ -17,6 +17,9 @@
 package naming_client
 
 import (
+	"context"
+	"errors"
+	"strconv"
 	"testing"
 
 	"github.com/nacos-group/nacos-sdk-go/v2/common/http_agent"
@@ -41,19 +44,19 @@ type MockNamingProxy struct {
 	unsubscribeParams []string // 记录调用参数
 }
 
-func (m *MockNamingProxy) RegisterInstance(serviceName string, groupName string, instance model.Instance) (bool, error) {
+func (m *MockNamingProxy) RegisterInstance(ctx context.Context, serviceName string, groupName string, instance model.Instance) (bool, error) {
 	return true, nil
 }
 
-func (m *MockNamingProxy) BatchRegisterInstance(serviceName string, groupName string, instances []model.Instance) (bool, error) {
+func (m *MockNamingProxy) BatchRegisterInstance(ctx context.Context, serviceName string, groupName string, instances []model.Instance) (bool, error) {
 	return true, nil
 }
 
-func (m *MockNamingProxy) DeregisterInstance(serviceName string, groupName string, instance model.Instance) (bool, error) {
+func (m *MockNamingProxy) DeregisterInstance(ctx context.Context, serviceName string, groupName string, instance model.Instance) (bool, error) {
 	return true, nil
 }
 
-func (m *MockNamingProxy) GetServiceList(pageNo uint32, pageSize uint32, groupName, namespaceId string, selector *model.ExpressionSelector) (model.ServiceList, error) {
+func (m *MockNamingProxy) GetServiceList(ctx context.Context, pageNo uint32, pageSize uint32, groupName, namespaceId string, selector *model.ExpressionSelector) (model.ServiceList, error) {
 	return model.ServiceList{Doms: []string{""}}, nil
 }
 
@@ -61,15 +64,15 @@ func (m *MockNamingProxy) ServerHealthy() bool {
 	return true
 }
 
-func (m *MockNamingProxy) QueryInstancesOfService(serviceName, groupName, clusters string, udpPort int, healthyOnly bool) (*model.Service, error) {
+func (m *MockNamingProxy) QueryInstancesOfService(ctx context.Context, serviceName, groupName, clusters string, udpPort int, healthyOnly bool) (*model.Service, error) {
 	return &model.Service{}, nil
 }
 
-func (m *MockNamingProxy) Subscribe(serviceName, groupName, clusters string) (model.Service, error) {
+func (m *MockNamingProxy) Subscribe(ctx context.Context, serviceName, groupName, clusters string) (model.Service, error) {
 	return model.Service{}, nil
 }
 
-func (m *MockNamingProxy) Unsubscribe(serviceName, groupName, clusters string) error {
+func (m *MockNamingProxy) Unsubscribe(ctx context.Context, serviceName, groupName, clusters string) error {
 	m.unsubscribeCalled = true
 	m.unsubscribeParams = []string{serviceName, groupName, clusters}
 	return nil
@@ -77,6 +80,24 @@ func (m *MockNamingProxy) Unsubscribe(serviceName, groupName, clusters string) e
 
 func (m *MockNamingProxy) CloseClient() {}
 
+type MockNamingProxyForServiceList struct {
+	MockNamingProxy
+	total int
+	pages []uint32
+}
+
+func (m *MockNamingProxyForServiceList) GetServiceList(ctx context.Context, pageNo uint32, pageSize uint32, groupName, namespaceId string, selector *model.ExpressionSelector) (model.ServiceList, error) {
+	m.pages = append(m.pages, pageNo)
+	if m.total < 0 {
+		return model.ServiceList{}, errors.New("list failed")
+	}
+	services := model.ServiceList{Count: int64(m.total)}
+	for i := int((pageNo - 1) * pageSize); i < m.total && i < int(pageNo*pageSize); i++ {
+		services.Doms = append(services.Doms, "service-"+strconv.Itoa(i))
+	}
+	return services, nil
+}
+
 func NewTestNamingClient() *NamingClient {
 	nc := nacos_client.NacosClient{}
 	_ = nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
@@ -629,3 +650,37 @@ func TestNamingClient_Unsubscribe_Integration_Test(t *testing.T) {
 	assert.True(t, mockProxy.unsubscribeCalled)
 
 }
+
+func TestListAllServices(t *testing.T) {
+	client := NewTestNamingClient()
+	proxy := &MockNamingProxyForServiceList{total: 25}
+	client.serviceProxy = proxy
+
+	var services []string
+	client.ListAllServices(vo.GetAllServiceInfoParam{PageSize: 10})(func(service string, err error) bool {
+		assert.Nil(t, err)
+		services = append(services, service)
+		return true
+	})
+	assert.Len(t, services, 25)
+	assert.Equal(t, "service-24", services[24])
+	assert.Equal(t, []uint32{1, 2, 3}, proxy.pages)
+
+	proxy.pages = nil
+	count := 0
+	client.ListAllServices(vo.GetAllServiceInfoParam{PageSize: 10, MaxItems: 10})(func(service string, err error) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, 10, count)
+	assert.Equal(t, []uint32{1}, proxy.pages)
+
+	proxy.total = -1
+	var errs []error
+	client.ListAllServices(vo.GetAllServiceInfoParam{})(func(service string, err error) bool {
+		errs = append(errs, err)
+		return true
+	})
+	assert.Len(t, errs, 1)
+	assert.NotNil(t, errs[0])
+}
@@ -17,6 +17,7 @@
 package naming_grpc
 
 import (
+	"context"
 	"strings"
 
 	"github.com/nacos-group/nacos-sdk-go/v2/clients/naming_client/naming_proxy"
@@ -62,9 +63,9 @@ func (c *ConnectionEventListener) redoSubscribe() {
 		var err error
 		var service model.Service
 		if len(info) > 2 {
-			service, err = c.clientProxy.Subscribe(info[1], info[0], info[2])
+			service, err = c.clientProxy.Subscribe(context.Background(), info[1], info[0], info[2])
 		} else {
-			service, err = c.clientProxy.Subscribe(info[1], info[0], "")
+			service, err = c.clientProxy.Subscribe(context.Background(), info[1], info[0], "")
 		}
 		if err != nil {
 			logger.Warnf("redo subscribe service:%s faild:%+v", info[1], err)
@@ -80,13 +81,13 @@ func (c *ConnectionEventListener) redoRegisterEachService() {
 		serviceName := info[1]
 		groupName := info[0]
 		if instance, ok := v.(model.Instance); ok {
-			if _, err := c.clientProxy.RegisterInstance(serviceName, groupName, instance); err != nil {
+			if _, err := c.clientProxy.RegisterInstance(context.Background(), serviceName, groupName, instance); err != nil {
 				logger.Warnf("redo register service:%s groupName:%s faild:%s", info[1], info[0], err.Error())
 				continue
 			}
 		}
 		if instances, ok := v.([]model.Instance); ok {
-			if _, err := c.clientProxy.BatchRegisterInstance(serviceName, groupName, instances); err != nil {
+			if _, err := c.clientProxy.BatchRegisterInstance(context.Background(), serviceName, groupName, instances); err != nil {
 				logger.Warnf("redo batch register service:%s groupName:%s faild:%s", info[1], info[0], err.Error())
 				continue
 			}
@@ -27,7 +27,7 @@ func TestRedoSubscribe(t *testing.T) {
 	for _, v := range cases {
 		fullServiceName := util.GetGroupName(v.serviceName, v.groupName)
 		evListener.CacheSubscriberForRedo(fullServiceName, v.clusters)
-		mockProxy.EXPECT().Subscribe(v.serviceName, v.groupName, v.clusters)
+		mockProxy.EXPECT().Subscribe(gomock.Any(), v.serviceName, v.groupName, v.clusters)
 		evListener.redoSubscribe()
 		evListener.RemoveSubscriberForRedo(fullServiceName, v.clusters)
 	}
@@ -82,21 +82,21 @@ func NewNamingGrpcProxy(ctx context.Context, clientCfg constant.ClientConfig, na
 	return &srvProxy, nil
 }
 
-func (proxy *NamingGrpcProxy) requestToServer(request rpc_request.IRequest) (rpc_response.IResponse, error) {
+func (proxy *NamingGrpcProxy) requestToServer(ctx context.Context, request rpc_request.IRequest) (rpc_response.IResponse, error) {
 	start := time.Now()
 	proxy.nacosServer.InjectSecurityInfo(request.GetHeaders(), security.BuildNamingResourceByRequest(request))
-	response, err := proxy.rpcClient.GetRpcClient().Request(request, int64(proxy.clientConfig.TimeoutMs))
+	response, err := proxy.rpcClient.GetRpcClient().RequestWithContext(ctx, request, int64(proxy.clientConfig.TimeoutMs))
 	monitor.GetNamingRequestMonitor(constant.GRPC, request.GetRequestType(), rpc_response.GetGrpcResponseStatusCode(response)).Observe(float64(time.Now().Nanosecond() - start.Nanosecond()))
 	return response, err
 }
 
 // RegisterInstance ...
-func (proxy *NamingGrpcProxy) RegisterInstance(serviceName string, groupName string, instance model.Instance) (bool, error) {
+func (proxy *NamingGrpcProxy) RegisterInstance(ctx context.Context, serviceName string, groupName string, instance model.Instance) (bool, error) {
 	logger.Infof("register instance namespaceId:<%s>,serviceName:<%s> with instance:<%s>",
 		proxy.clientConfig.NamespaceId, serviceName, util.ToJsonString(instance))
 	proxy.eventListener.CacheInstanceForRedo(serviceName, groupName, instance)
 	instanceRequest := rpc_request.NewInstanceRequest(proxy.clientConfig.NamespaceId, serviceName, groupName, "registerInstance", instance)
-	response, err := proxy.requestToServer(instanceRequest)
+	response, err := proxy.requestToServer(ctx, instanceRequest)
 	if err != nil {
 		return false, err
 	}
@@ -104,12 +104,12 @@ func (proxy *NamingGrpcProxy) RegisterInstance(serviceName string, groupName str
 }
 
 // BatchRegisterInstance ...
-func (proxy *NamingGrpcProxy) BatchRegisterInstance(serviceName string, groupName string, instances []model.Instance) (bool, error) {
+func (proxy *NamingGrpcProxy) BatchRegisterInstance(ctx context.Context, serviceName string, groupName string, instances []model.Instance) (bool, error) {
 	logger.Infof("batch register instance namespaceId:<%s>,serviceName:<%s> with instance:<%s>",
 		proxy.clientConfig.NamespaceId, serviceName, util.ToJsonString(instances))
 	proxy.eventListener.CacheInstancesForRedo(serviceName, groupName, instances)
 	batchInstanceRequest := rpc_request.NewBatchInstanceRequest(proxy.clientConfig.NamespaceId, serviceName, groupName, "batchRegisterInstance", instances)
-	response, err := proxy.requestToServer(batchInstanceRequest)
+	response, err := proxy.requestToServer(ctx, batchInstanceRequest)
 	if err != nil {
 		return false, err
 	}
@@ -117,11 +117,11 @@ func (proxy *NamingGrpcProxy) BatchRegisterInstance(serviceName string, groupNam
 }
 
 // DeregisterInstance ...
-func (proxy *NamingGrpcProxy) DeregisterInstance(serviceName string, groupName string, instance model.Instance) (bool, error) {
+func (proxy *NamingGrpcProxy) DeregisterInstance(ctx context.Context, serviceName string, groupName string, instance model.Instance) (bool, error) {
 	logger.Infof("deregister instance namespaceId:<%s>,serviceName:<%s> with instance:<%s:%d@%s>",
 		proxy.clientConfig.NamespaceId, serviceName, instance.Ip, instance.Port, instance.ClusterName)
 	instanceRequest := rpc_request.NewInstanceRequest(proxy.clientConfig.NamespaceId, serviceName, groupName, "deregisterInstance", instance)
-	response, err := proxy.requestToServer(instanceRequest)
+	response, err := proxy.requestToServer(ctx, instanceRequest)
 	proxy.eventListener.RemoveInstanceForRedo(serviceName, groupName, instance)
 	if err != nil {
 		return false, err
@@ -130,7 +130,7 @@ func (proxy *NamingGrpcProxy) DeregisterInstance(serviceName string, groupName s
 }
 
 // GetServiceList ...
-func (proxy *NamingGrpcProxy) GetServiceList(pageNo uint32, pageSize uint32, groupName, namespaceId string, selector *model.ExpressionSelector) (model.ServiceList, error) {
+func (proxy *NamingGrpcProxy) GetServiceList(ctx context.Context, pageNo uint32, pageSize uint32, groupName, namespaceId string, selector *model.ExpressionSelector) (model.ServiceList, error) {
 	var selectorStr string
 	if selector != nil {
 		switch selector.Type {
@@ -140,7 +140,7 @@ func (proxy *NamingGrpcProxy) GetServiceList(pageNo uint32, pageSize uint32, gro
 			break
 		}
 	}
-	response, err := proxy.requestToServer(rpc_request.NewServiceListRequest(namespaceId, "",
+	response, err := proxy.requestToServer(ctx, rpc_request.NewServiceListRequest(namespaceId, "",
 		groupName, int(pageNo), int(pageSize), selectorStr))
 	if err != nil {
 		return model.ServiceList{}, err
@@ -158,8 +158,8 @@ func (proxy *NamingGrpcProxy) ServerHealthy() bool {
 }
 
 // QueryInstancesOfService ...
-func (proxy *NamingGrpcProxy) QueryInstancesOfService(serviceName, groupName, cluster string, udpPort int, healthyOnly bool) (*model.Service, error) {
-	response, err := proxy.requestToServer(rpc_request.NewServiceQueryRequest(proxy.clientConfig.NamespaceId, serviceName, groupName, cluster,
+func (proxy *NamingGrpcProxy) QueryInstancesOfService(ctx context.Context, serviceName, groupName, cluster string, udpPort int, healthyOnly bool) (*model.Service, error) {
+	response, err := proxy.requestToServer(ctx, rpc_request.NewServiceQueryRequest(proxy.clientConfig.NamespaceId, serviceName, groupName, cluster,
 		healthyOnly, udpPort))
 	if err != nil {
 		return nil, err
@@ -173,14 +173,14 @@ func (proxy *NamingGrpcProxy) IsSubscribed(serviceName, groupName string, cluste
 }
 
 // Subscribe ...
-func (proxy *NamingGrpcProxy) Subscribe(serviceName, groupName string, clusters string) (model.Service, error) {
+func (proxy *NamingGrpcProxy) Subscribe(ctx context.Context, serviceName, groupName string, clusters string) (model.Service, error) {
 	logger.Infof("Subscribe Service namespaceId:<%s>, serviceName:<%s>, groupName:<%s>, clusters:<%s>",
 		proxy.clientConfig.NamespaceId, serviceName, groupName, clusters)
 	proxy.eventListener.CacheSubscriberForRedo(util.GetGroupName(serviceName, groupName), clusters)
 	request := rpc_request.NewSubscribeServiceRequest(proxy.clientConfig.NamespaceId, serviceName,
 		groupName, clusters, true)
 	request.Headers["app"] = proxy.clientConfig.AppName
-	response, err := proxy.requestToServer(request)
+	response, err := proxy.requestToServer(ctx, request)
 	if err != nil {
 		return model.Service{}, err
 	}
@@ -189,11 +189,11 @@ func (proxy *NamingGrpcProxy) Subscribe(serviceName, groupName string, clusters
 }
 
 // Unsubscribe ...
-func (proxy *NamingGrpcProxy) Unsubscribe(serviceName, groupName, clusters string) error {
+func (proxy *NamingGrpcProxy) Unsubscribe(ctx context.Context, serviceName, groupName, clusters string) error {
 	logger.Infof("Unsubscribe Service namespaceId:<%s>, serviceName:<%s>, groupName:<%s>, clusters:<%s>",
 		proxy.clientConfig.NamespaceId, serviceName, groupName, clusters)
 	proxy.eventListener.RemoveSubscriberForRedo(util.GetGroupName(serviceName, groupName), clusters)
-	_, err := proxy.requestToServer(rpc_request.NewSubscribeServiceRequest(proxy.clientConfig.NamespaceId, serviceName, groupName,
+	_, err := proxy.requestToServer(ctx, rpc_request.NewSubscribeServiceRequest(proxy.clientConfig.NamespaceId, serviceName, groupName,
 		clusters, false))
 	return err
 }
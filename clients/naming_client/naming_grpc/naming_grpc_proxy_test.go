@@ -1,6 +1,17 @@
 package naming_grpc
 
-import "github.com/nacos-group/nacos-sdk-go/v2/model"
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/nacos_server"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/stretchr/testify/assert"
+)
 
 type MockNamingGrpc struct {
 }
@@ -38,3 +49,45 @@ func (m *MockNamingGrpc) Unsubscribe(serviceName, groupName, clusters string) er
 }
 
 func (m *MockNamingGrpc) CloseClient() {}
+
+// newNamingGrpcProxyNotConnected builds a proxy whose rpc client never connects, so requests only end by their context
+// or timeout.
+func newNamingGrpcProxyNotConnected(t *testing.T) *NamingGrpcProxy {
+	clientConfig := *constant.NewClientConfig(constant.WithTimeoutMs(10000))
+	nacosServer, err := nacos_server.NewNacosServer(context.Background(),
+		[]constant.ServerConfig{*constant.NewServerConfig("127.0.0.1", 8848)}, clientConfig, &http_agent.HttpAgent{},
+		1000, "", nil)
+	assert.Nil(t, err)
+	proxy := &NamingGrpcProxy{
+		clientConfig: clientConfig,
+		nacosServer:  nacosServer,
+		rpcClient:    &rpc.GrpcClient{RpcClient: &rpc.RpcClient{}},
+	}
+	proxy.eventListener = NewConnectionEventListener(proxy)
+	return proxy
+}
+
+func TestNamingGrpcProxy_Cancelled(t *testing.T) {
+	proxy := newNamingGrpcProxyNotConnected(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := proxy.Subscribe(ctx, "DEMO", "DEFAULT_GROUP", "")
+	assert.Equal(t, context.Canceled, err)
+	service, err := proxy.QueryInstancesOfService(ctx, "DEMO", "DEFAULT_GROUP", "", 0, true)
+	assert.Nil(t, service)
+	assert.Equal(t, context.Canceled, err)
+	registered, err := proxy.RegisterInstance(ctx, "DEMO", "DEFAULT_GROUP", model.Instance{Ip: "10.0.0.1", Port: 80})
+	assert.False(t, registered)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestNamingGrpcProxy_DeadlineStopsRetry(t *testing.T) {
+	proxy := newNamingGrpcProxyNotConnected(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := proxy.Subscribe(ctx, "DEMO", "DEFAULT_GROUP", "")
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
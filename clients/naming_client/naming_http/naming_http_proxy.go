@@ -59,7 +59,7 @@ func NewNamingHttpProxy(ctx context.Context, clientCfg constant.ClientConfig, na
 }
 
 // RegisterInstance ...
-func (proxy *NamingHttpProxy) RegisterInstance(serviceName string, groupName string, instance model.Instance) (bool, error) {
+func (proxy *NamingHttpProxy) RegisterInstance(ctx context.Context, serviceName string, groupName string, instance model.Instance) (bool, error) {
 	logger.Infof("register instance namespaceId:<%s>,serviceName:<%s> with instance:<%s>",
 		proxy.clientConfig.NamespaceId, serviceName, util.ToJsonString(instance))
 	serviceName = util.GetGroupName(serviceName, groupName)
@@ -76,7 +76,7 @@ func (proxy *NamingHttpProxy) RegisterInstance(serviceName string, groupName str
 	params["healthy"] = strconv.FormatBool(instance.Healthy)
 	params["metadata"] = util.ToJsonString(instance.Metadata)
 	params["ephemeral"] = strconv.FormatBool(instance.Ephemeral)
-	_, err := proxy.nacosServer.ReqApi(constant.SERVICE_PATH, params, http.MethodPost, proxy.clientConfig)
+	_, err := proxy.nacosServer.ReqApiWithContext(ctx, constant.SERVICE_PATH, params, http.MethodPost, proxy.clientConfig)
 	if err != nil {
 		return false, err
 	}
@@ -96,12 +96,12 @@ func (proxy *NamingHttpProxy) RegisterInstance(serviceName string, groupName str
 	return true, nil
 }
 
-func (proxy *NamingHttpProxy) BatchRegisterInstance(serviceName string, groupName string, instances []model.Instance) (bool, error) {
+func (proxy *NamingHttpProxy) BatchRegisterInstance(ctx context.Context, serviceName string, groupName string, instances []model.Instance) (bool, error) {
 	panic("implement me")
 }
 
 // DeregisterInstance ...
-func (proxy *NamingHttpProxy) DeregisterInstance(serviceName string, groupName string, instance model.Instance) (bool, error) {
+func (proxy *NamingHttpProxy) DeregisterInstance(ctx context.Context, serviceName string, groupName string, instance model.Instance) (bool, error) {
 	serviceName = util.GetGroupName(serviceName, groupName)
 	logger.Infof("deregister instance namespaceId:<%s>,serviceName:<%s> with instance:<%s:%d@%s>",
 		proxy.clientConfig.NamespaceId, serviceName, instance.Ip, instance.Port, instance.ClusterName)
@@ -113,7 +113,7 @@ func (proxy *NamingHttpProxy) DeregisterInstance(serviceName string, groupName s
 	params["ip"] = instance.Ip
 	params["port"] = strconv.Itoa(int(instance.Port))
 	params["ephemeral"] = strconv.FormatBool(instance.Ephemeral)
-	_, err := proxy.nacosServer.ReqApi(constant.SERVICE_PATH, params, http.MethodDelete, proxy.clientConfig)
+	_, err := proxy.nacosServer.ReqApiWithContext(ctx, constant.SERVICE_PATH, params, http.MethodDelete, proxy.clientConfig)
 	if err != nil {
 		return false, err
 	}
@@ -121,7 +121,7 @@ func (proxy *NamingHttpProxy) DeregisterInstance(serviceName string, groupName s
 }
 
 // GetServiceList ...
-func (proxy *NamingHttpProxy) GetServiceList(pageNo uint32, pageSize uint32, groupName, namespaceId string, selector *model.ExpressionSelector) (model.ServiceList, error) {
+func (proxy *NamingHttpProxy) GetServiceList(ctx context.Context, pageNo uint32, pageSize uint32, groupName, namespaceId string, selector *model.ExpressionSelector) (model.ServiceList, error) {
 	params := map[string]string{}
 	params["namespaceId"] = namespaceId
 	params["groupName"] = groupName
@@ -141,7 +141,7 @@ func (proxy *NamingHttpProxy) GetServiceList(pageNo uint32, pageSize uint32, gro
 	serviceList := model.ServiceList{}
 
 	api := constant.SERVICE_BASE_PATH + "/service/list"
-	result, err := proxy.nacosServer.ReqApi(api, params, http.MethodGet, proxy.clientConfig)
+	result, err := proxy.nacosServer.ReqApiWithContext(ctx, api, params, http.MethodGet, proxy.clientConfig)
 	if err != nil {
 		return serviceList, err
 	}
@@ -185,7 +185,7 @@ func (proxy *NamingHttpProxy) ServerHealthy() bool {
 }
 
 // QueryInstancesOfService ...
-func (proxy *NamingHttpProxy) QueryInstancesOfService(serviceName, groupName, clusters string, udpPort int, healthyOnly bool) (*model.Service, error) {
+func (proxy *NamingHttpProxy) QueryInstancesOfService(ctx context.Context, serviceName, groupName, clusters string, udpPort int, healthyOnly bool) (*model.Service, error) {
 	param := make(map[string]string)
 	param["namespaceId"] = proxy.clientConfig.NamespaceId
 	param["serviceName"] = util.GetGroupName(serviceName, groupName)
@@ -195,7 +195,7 @@ func (proxy *NamingHttpProxy) QueryInstancesOfService(serviceName, groupName, cl
 	param["healthyOnly"] = strconv.FormatBool(healthyOnly)
 	param["clientIP"] = util.LocalIP()
 	api := constant.SERVICE_PATH + "/list"
-	result, err := proxy.nacosServer.ReqApi(api, param, http.MethodGet, proxy.clientConfig)
+	result, err := proxy.nacosServer.ReqApiWithContext(ctx, api, param, http.MethodGet, proxy.clientConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -204,12 +204,12 @@ func (proxy *NamingHttpProxy) QueryInstancesOfService(serviceName, groupName, cl
 }
 
 // Subscribe ...
-func (proxy *NamingHttpProxy) Subscribe(serviceName, groupName, clusters string) (model.Service, error) {
+func (proxy *NamingHttpProxy) Subscribe(ctx context.Context, serviceName, groupName, clusters string) (model.Service, error) {
 	return model.Service{}, nil
 }
 
 // Unsubscribe ...
-func (proxy *NamingHttpProxy) Unsubscribe(serviceName, groupName, clusters string) error {
+func (proxy *NamingHttpProxy) Unsubscribe(ctx context.Context, serviceName, groupName, clusters string) error {
 	return nil
 }
 
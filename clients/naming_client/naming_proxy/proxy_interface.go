@@ -17,26 +17,27 @@
 package naming_proxy
 
 import (
+	"context"
 	"github.com/nacos-group/nacos-sdk-go/v2/model"
 )
 
 // INamingProxy ...
 type INamingProxy interface {
-	RegisterInstance(serviceName string, groupName string, instance model.Instance) (bool, error)
+	RegisterInstance(ctx context.Context, serviceName string, groupName string, instance model.Instance) (bool, error)
 
-	BatchRegisterInstance(serviceName string, groupName string, instances []model.Instance) (bool, error)
+	BatchRegisterInstance(ctx context.Context, serviceName string, groupName string, instances []model.Instance) (bool, error)
 
-	DeregisterInstance(serviceName string, groupName string, instance model.Instance) (bool, error)
+	DeregisterInstance(ctx context.Context, serviceName string, groupName string, instance model.Instance) (bool, error)
 
-	GetServiceList(pageNo uint32, pageSize uint32, groupName, namespaceId string, selector *model.ExpressionSelector) (model.ServiceList, error)
+	GetServiceList(ctx context.Context, pageNo uint32, pageSize uint32, groupName, namespaceId string, selector *model.ExpressionSelector) (model.ServiceList, error)
 
 	ServerHealthy() bool
 
-	QueryInstancesOfService(serviceName, groupName, clusters string, udpPort int, healthyOnly bool) (*model.Service, error)
+	QueryInstancesOfService(ctx context.Context, serviceName, groupName, clusters string, udpPort int, healthyOnly bool) (*model.Service, error)
 
-	Subscribe(serviceName, groupName, clusters string) (model.Service, error)
+	Subscribe(ctx context.Context, serviceName, groupName, clusters string) (model.Service, error)
 
-	Unsubscribe(serviceName, groupName, clusters string) error
+	Unsubscribe(ctx context.Context, serviceName, groupName, clusters string) error
 
 	CloseClient()
 }
@@ -5,6 +5,7 @@
 package naming_proxy
 
 import (
+	"context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,18 +36,18 @@ func (m *MockINamingProxy) EXPECT() *MockINamingProxyMockRecorder {
 }
 
 // BatchRegisterInstance mocks base method.
-func (m *MockINamingProxy) BatchRegisterInstance(serviceName, groupName string, instances []model.Instance) (bool, error) {
+func (m *MockINamingProxy) BatchRegisterInstance(ctx context.Context, serviceName, groupName string, instances []model.Instance) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BatchRegisterInstance", serviceName, groupName, instances)
+	ret := m.ctrl.Call(m, "BatchRegisterInstance", ctx, serviceName, groupName, instances)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // BatchRegisterInstance indicates an expected call of BatchRegisterInstance.
-func (mr *MockINamingProxyMockRecorder) BatchRegisterInstance(serviceName, groupName, instances interface{}) *gomock.Call {
+func (mr *MockINamingProxyMockRecorder) BatchRegisterInstance(ctx, serviceName, groupName, instances interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchRegisterInstance", reflect.TypeOf((*MockINamingProxy)(nil).BatchRegisterInstance), serviceName, groupName, instances)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchRegisterInstance", reflect.TypeOf((*MockINamingProxy)(nil).BatchRegisterInstance), ctx, serviceName, groupName, instances)
 }
 
 // CloseClient mocks base method.
@@ -62,63 +63,63 @@ func (mr *MockINamingProxyMockRecorder) CloseClient() *gomock.Call {
 }
 
 // DeregisterInstance mocks base method.
-func (m *MockINamingProxy) DeregisterInstance(serviceName, groupName string, instance model.Instance) (bool, error) {
+func (m *MockINamingProxy) DeregisterInstance(ctx context.Context, serviceName, groupName string, instance model.Instance) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeregisterInstance", serviceName, groupName, instance)
+	ret := m.ctrl.Call(m, "DeregisterInstance", ctx, serviceName, groupName, instance)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeregisterInstance indicates an expected call of DeregisterInstance.
-func (mr *MockINamingProxyMockRecorder) DeregisterInstance(serviceName, groupName, instance interface{}) *gomock.Call {
+func (mr *MockINamingProxyMockRecorder) DeregisterInstance(ctx, serviceName, groupName, instance interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterInstance", reflect.TypeOf((*MockINamingProxy)(nil).DeregisterInstance), serviceName, groupName, instance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterInstance", reflect.TypeOf((*MockINamingProxy)(nil).DeregisterInstance), ctx, serviceName, groupName, instance)
 }
 
 // GetServiceList mocks base method.
-func (m *MockINamingProxy) GetServiceList(pageNo, pageSize uint32, groupName, namespaceId string, selector *model.ExpressionSelector) (model.ServiceList, error) {
+func (m *MockINamingProxy) GetServiceList(ctx context.Context, pageNo, pageSize uint32, groupName, namespaceId string, selector *model.ExpressionSelector) (model.ServiceList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetServiceList", pageNo, pageSize, groupName, namespaceId, selector)
+	ret := m.ctrl.Call(m, "GetServiceList", ctx, pageNo, pageSize, groupName, namespaceId, selector)
 	ret0, _ := ret[0].(model.ServiceList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetServiceList indicates an expected call of GetServiceList.
-func (mr *MockINamingProxyMockRecorder) GetServiceList(pageNo, pageSize, groupName, namespaceId, selector interface{}) *gomock.Call {
+func (mr *MockINamingProxyMockRecorder) GetServiceList(ctx, pageNo, pageSize, groupName, namespaceId, selector interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceList", reflect.TypeOf((*MockINamingProxy)(nil).GetServiceList), pageNo, pageSize, groupName, namespaceId, selector)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceList", reflect.TypeOf((*MockINamingProxy)(nil).GetServiceList), ctx, pageNo, pageSize, groupName, namespaceId, selector)
 }
 
 // QueryInstancesOfService mocks base method.
-func (m *MockINamingProxy) QueryInstancesOfService(serviceName, groupName, clusters string, udpPort int, healthyOnly bool) (*model.Service, error) {
+func (m *MockINamingProxy) QueryInstancesOfService(ctx context.Context, serviceName, groupName, clusters string, udpPort int, healthyOnly bool) (*model.Service, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryInstancesOfService", serviceName, groupName, clusters, udpPort, healthyOnly)
+	ret := m.ctrl.Call(m, "QueryInstancesOfService", ctx, serviceName, groupName, clusters, udpPort, healthyOnly)
 	ret0, _ := ret[0].(*model.Service)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryInstancesOfService indicates an expected call of QueryInstancesOfService.
-func (mr *MockINamingProxyMockRecorder) QueryInstancesOfService(serviceName, groupName, clusters, udpPort, healthyOnly interface{}) *gomock.Call {
+func (mr *MockINamingProxyMockRecorder) QueryInstancesOfService(ctx, serviceName, groupName, clusters, udpPort, healthyOnly interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryInstancesOfService", reflect.TypeOf((*MockINamingProxy)(nil).QueryInstancesOfService), serviceName, groupName, clusters, udpPort, healthyOnly)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryInstancesOfService", reflect.TypeOf((*MockINamingProxy)(nil).QueryInstancesOfService), ctx, serviceName, groupName, clusters, udpPort, healthyOnly)
 }
 
 // RegisterInstance mocks base method.
-func (m *MockINamingProxy) RegisterInstance(serviceName, groupName string, instance model.Instance) (bool, error) {
+func (m *MockINamingProxy) RegisterInstance(ctx context.Context, serviceName, groupName string, instance model.Instance) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterInstance", serviceName, groupName, instance)
+	ret := m.ctrl.Call(m, "RegisterInstance", ctx, serviceName, groupName, instance)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RegisterInstance indicates an expected call of RegisterInstance.
-func (mr *MockINamingProxyMockRecorder) RegisterInstance(serviceName, groupName, instance interface{}) *gomock.Call {
+func (mr *MockINamingProxyMockRecorder) RegisterInstance(ctx, serviceName, groupName, instance interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterInstance", reflect.TypeOf((*MockINamingProxy)(nil).RegisterInstance), serviceName, groupName, instance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterInstance", reflect.TypeOf((*MockINamingProxy)(nil).RegisterInstance), ctx, serviceName, groupName, instance)
 }
 
 // ServerHealthy mocks base method.
@@ -136,30 +137,30 @@ func (mr *MockINamingProxyMockRecorder) ServerHealthy() *gomock.Call {
 }
 
 // Subscribe mocks base method.
-func (m *MockINamingProxy) Subscribe(serviceName, groupName, clusters string) (model.Service, error) {
+func (m *MockINamingProxy) Subscribe(ctx context.Context, serviceName, groupName, clusters string) (model.Service, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Subscribe", serviceName, groupName, clusters)
+	ret := m.ctrl.Call(m, "Subscribe", ctx, serviceName, groupName, clusters)
 	ret0, _ := ret[0].(model.Service)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Subscribe indicates an expected call of Subscribe.
-func (mr *MockINamingProxyMockRecorder) Subscribe(serviceName, groupName, clusters interface{}) *gomock.Call {
+func (mr *MockINamingProxyMockRecorder) Subscribe(ctx, serviceName, groupName, clusters interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockINamingProxy)(nil).Subscribe), serviceName, groupName, clusters)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockINamingProxy)(nil).Subscribe), ctx, serviceName, groupName, clusters)
 }
 
 // Unsubscribe mocks base method.
-func (m *MockINamingProxy) Unsubscribe(serviceName, groupName, clusters string) error {
+func (m *MockINamingProxy) Unsubscribe(ctx context.Context, serviceName, groupName, clusters string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Unsubscribe", serviceName, groupName, clusters)
+	ret := m.ctrl.Call(m, "Unsubscribe", ctx, serviceName, groupName, clusters)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Unsubscribe indicates an expected call of Unsubscribe.
-func (mr *MockINamingProxyMockRecorder) Unsubscribe(serviceName, groupName, clusters interface{}) *gomock.Call {
+func (mr *MockINamingProxyMockRecorder) Unsubscribe(ctx, serviceName, groupName, clusters interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockINamingProxy)(nil).Unsubscribe), serviceName, groupName, clusters)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockINamingProxy)(nil).Unsubscribe), ctx, serviceName, groupName, clusters)
 }
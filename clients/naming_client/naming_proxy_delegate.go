@@ -88,37 +88,37 @@ func (proxy *NamingProxyDelegate) getExecuteClientProxy(instance model.Instance)
 	return namingProxy
 }
 
-func (proxy *NamingProxyDelegate) RegisterInstance(serviceName string, groupName string, instance model.Instance) (bool, error) {
-	return proxy.getExecuteClientProxy(instance).RegisterInstance(serviceName, groupName, instance)
+func (proxy *NamingProxyDelegate) RegisterInstance(ctx context.Context, serviceName string, groupName string, instance model.Instance) (bool, error) {
+	return proxy.getExecuteClientProxy(instance).RegisterInstance(ctx, serviceName, groupName, instance)
 }
 
-func (proxy *NamingProxyDelegate) BatchRegisterInstance(serviceName string, groupName string, instances []model.Instance) (bool, error) {
-	return proxy.grpcClientProxy.BatchRegisterInstance(serviceName, groupName, instances)
+func (proxy *NamingProxyDelegate) BatchRegisterInstance(ctx context.Context, serviceName string, groupName string, instances []model.Instance) (bool, error) {
+	return proxy.grpcClientProxy.BatchRegisterInstance(ctx, serviceName, groupName, instances)
 }
 
-func (proxy *NamingProxyDelegate) DeregisterInstance(serviceName string, groupName string, instance model.Instance) (bool, error) {
-	return proxy.getExecuteClientProxy(instance).DeregisterInstance(serviceName, groupName, instance)
+func (proxy *NamingProxyDelegate) DeregisterInstance(ctx context.Context, serviceName string, groupName string, instance model.Instance) (bool, error) {
+	return proxy.getExecuteClientProxy(instance).DeregisterInstance(ctx, serviceName, groupName, instance)
 }
 
-func (proxy *NamingProxyDelegate) GetServiceList(pageNo uint32, pageSize uint32, groupName, namespaceId string, selector *model.ExpressionSelector) (model.ServiceList, error) {
-	return proxy.grpcClientProxy.GetServiceList(pageNo, pageSize, groupName, namespaceId, selector)
+func (proxy *NamingProxyDelegate) GetServiceList(ctx context.Context, pageNo uint32, pageSize uint32, groupName, namespaceId string, selector *model.ExpressionSelector) (model.ServiceList, error) {
+	return proxy.grpcClientProxy.GetServiceList(ctx, pageNo, pageSize, groupName, namespaceId, selector)
 }
 
 func (proxy *NamingProxyDelegate) ServerHealthy() bool {
 	return proxy.grpcClientProxy.ServerHealthy() || proxy.httpClientProxy.ServerHealthy()
 }
 
-func (proxy *NamingProxyDelegate) QueryInstancesOfService(serviceName, groupName, clusters string, udpPort int, healthyOnly bool) (*model.Service, error) {
-	return proxy.grpcClientProxy.QueryInstancesOfService(serviceName, groupName, clusters, udpPort, healthyOnly)
+func (proxy *NamingProxyDelegate) QueryInstancesOfService(ctx context.Context, serviceName, groupName, clusters string, udpPort int, healthyOnly bool) (*model.Service, error) {
+	return proxy.grpcClientProxy.QueryInstancesOfService(ctx, serviceName, groupName, clusters, udpPort, healthyOnly)
 }
 
-func (proxy *NamingProxyDelegate) Subscribe(serviceName, groupName string, clusters string) (model.Service, error) {
+func (proxy *NamingProxyDelegate) Subscribe(ctx context.Context, serviceName, groupName string, clusters string) (model.Service, error) {
 	var err error
 	isSubscribed := proxy.grpcClientProxy.IsSubscribed(serviceName, groupName, clusters)
 	serviceNameWithGroup := util.GetServiceCacheKey(util.GetGroupName(serviceName, groupName), clusters)
 	serviceInfo, ok := proxy.serviceInfoHolder.ServiceInfoMap.Load(serviceNameWithGroup)
 	if !isSubscribed || !ok {
-		serviceInfo, err = proxy.grpcClientProxy.Subscribe(serviceName, groupName, clusters)
+		serviceInfo, err = proxy.grpcClientProxy.Subscribe(ctx, serviceName, groupName, clusters)
 		if err != nil {
 			return model.Service{}, err
 		}
@@ -129,9 +129,9 @@ func (proxy *NamingProxyDelegate) Subscribe(serviceName, groupName string, clust
 	return service, nil
 }
 
-func (proxy *NamingProxyDelegate) Unsubscribe(serviceName, groupName, clusters string) error {
+func (proxy *NamingProxyDelegate) Unsubscribe(ctx context.Context, serviceName, groupName, clusters string) error {
 	proxy.serviceInfoHolder.StopUpdateIfContain(util.GetGroupName(serviceName, groupName), clusters)
-	return proxy.grpcClientProxy.Unsubscribe(serviceName, groupName, clusters)
+	return proxy.grpcClientProxy.Unsubscribe(ctx, serviceName, groupName, clusters)
 }
 
 func (proxy *NamingProxyDelegate) CloseClient() {
@@ -74,7 +74,7 @@ func (s *ServiceInfoUpdater) asyncUpdateService() {
 }
 
 func (s *ServiceInfoUpdater) updateServiceNow(serviceName, groupName, clusters string) {
-	result, err := s.namingProxy.QueryInstancesOfService(serviceName, groupName, clusters, 0, false)
+	result, err := s.namingProxy.QueryInstancesOfService(s.ctx, serviceName, groupName, clusters, 0, false)
 
 	if err != nil {
 		logger.Errorf("QueryInstances error, serviceName:%s, cluster:%s, err:%v", serviceName, clusters, err)
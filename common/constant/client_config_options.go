@@ -30,6 +30,8 @@ func NewClientConfig(opts ...ClientOption) *ClientConfig {
 		OpenKMS:              false,
 		CacheDir:             file.GetCurrentPath() + string(os.PathSeparator) + "cache",
 		UpdateThreadNum:      20,
+		ListenerWorkerNum:    8,
+		ListenerQueueSize:    64,
 		NotLoadCacheAtStart:  false,
 		UpdateCacheWhenEmpty: false,
 		LogDir:               file.GetCurrentPath() + string(os.PathSeparator) + "log",
@@ -155,6 +157,28 @@ func WithKMSConfig(kmsConfig *KMSConfig) ClientOption {
 	}
 }
 
+// WithVaultKMSConfig ...
+func WithVaultKMSConfig(vaultKMSConfig *VaultKMSConfig) ClientOption {
+	return func(config *ClientConfig) {
+		config.KMSVersion = KMSVault
+		config.VaultKMSConfig = vaultKMSConfig
+	}
+}
+
+// WithKMSDataKeyCache ...
+func WithKMSDataKeyCache(cacheConfig KMSDataKeyCacheConfig) ClientOption {
+	return func(config *ClientConfig) {
+		config.KMSDataKeyCache = &cacheConfig
+	}
+}
+
+// WithLocalKMSConfig ...
+func WithLocalKMSConfig(localKMSConfig *LocalKMSConfig) ClientOption {
+	return func(config *ClientConfig) {
+		config.LocalKMSConfig = localKMSConfig
+	}
+}
+
 // WithCacheDir ...
 func WithCacheDir(cacheDir string) ClientOption {
 	return func(config *ClientConfig) {
@@ -176,6 +200,34 @@ func WithUpdateThreadNum(updateThreadNum int) ClientOption {
 	}
 }
 
+// WithListenerWorkerNum ...
+func WithListenerWorkerNum(listenerWorkerNum int) ClientOption {
+	return func(config *ClientConfig) {
+		config.ListenerWorkerNum = listenerWorkerNum
+	}
+}
+
+// WithListenerCoalesce ...
+func WithListenerCoalesce(listenerCoalesce bool) ClientOption {
+	return func(config *ClientConfig) {
+		config.ListenerCoalesce = listenerCoalesce
+	}
+}
+
+// WithListenerQueueSize ...
+func WithListenerQueueSize(listenerQueueSize int) ClientOption {
+	return func(config *ClientConfig) {
+		config.ListenerQueueSize = listenerQueueSize
+	}
+}
+
+// WithRateLimit ...
+func WithRateLimit(rateLimit RateLimitConfig) ClientOption {
+	return func(config *ClientConfig) {
+		config.RateLimit = &rateLimit
+	}
+}
+
 // WithNotLoadCacheAtStart ...
 func WithNotLoadCacheAtStart(notLoadCacheAtStart bool) ClientOption {
 	return func(config *ClientConfig) {
@@ -33,6 +33,9 @@ func TestNewClientConfig(t *testing.T) {
 	assert.Equal(t, config.LogLevel, "info")
 	assert.Equal(t, config.BeatInterval, int64(5000))
 	assert.Equal(t, config.UpdateThreadNum, 20)
+	assert.Equal(t, config.ListenerWorkerNum, 8)
+	assert.Equal(t, config.ListenerCoalesce, false)
+	assert.Equal(t, config.ListenerQueueSize, 64)
 
 	assert.Equal(t, config.LogDir, file.GetCurrentPath()+string(os.PathSeparator)+"log")
 	assert.Equal(t, config.CacheDir, file.GetCurrentPath()+string(os.PathSeparator)+"cache")
@@ -57,6 +60,10 @@ func TestNewClientConfigWithOptions(t *testing.T) {
 		WithLogLevel("error"),
 		WithBeatInterval(int64(2000)),
 		WithUpdateThreadNum(30),
+		WithListenerWorkerNum(4),
+		WithListenerCoalesce(true),
+		WithListenerQueueSize(16),
+		WithRateLimit(RateLimitConfig{KeyQps: 10, MaxWaitMs: 100}),
 
 		WithLogDir("/tmp/nacos/log"),
 		WithCacheDir("/tmp/nacos/cache"),
@@ -78,6 +85,10 @@ func TestNewClientConfigWithOptions(t *testing.T) {
 	assert.Equal(t, config.LogLevel, "error")
 	assert.Equal(t, config.BeatInterval, int64(2000))
 	assert.Equal(t, config.UpdateThreadNum, 30)
+	assert.Equal(t, config.ListenerWorkerNum, 4)
+	assert.Equal(t, config.ListenerCoalesce, true)
+	assert.Equal(t, config.ListenerQueueSize, 16)
+	assert.Equal(t, config.RateLimit, &RateLimitConfig{KeyQps: 10, MaxWaitMs: 100})
 
 	assert.Equal(t, config.LogDir, "/tmp/nacos/log")
 	assert.Equal(t, config.CacheDir, "/tmp/nacos/cache")
@@ -42,6 +42,9 @@ type ClientConfig struct {
 	KMSVersion           KMSVersion   // kms client version. https://help.aliyun.com/document_detail/380927.html
 	KMSv3Config          *KMSv3Config //KMSv3 configuration. https://help.aliyun.com/document_detail/601596.html
 	KMSConfig            *KMSConfig
+	VaultKMSConfig       *VaultKMSConfig          // hashicorp vault transit configuration, used when KMSVersion is Vault
+	KMSDataKeyCache      *KMSDataKeyCacheConfig   // cache of the data keys decrypted by kms, default is no cache
+	LocalKMSConfig       *LocalKMSConfig          // local keyring encrypting the cipher-local-aes-256 configs, works without OpenKMS
 	CacheDir             string                   // the directory for persist nacos service info,default value is current path
 	DisableUseSnapShot   bool                     // It's a switch, default is false, means that when get remote config fail, use local cache file instead
 	UpdateThreadNum      int                      // the number of goroutine for update nacos service info,default value is 20
@@ -62,6 +65,10 @@ type ClientConfig struct {
 	ClusterName          string                   // the address server  clusterName
 	AppConnLabels        map[string]string        // app conn labels
 	ClientIP             string                   // the custom client ip, if not set, will use local ip auto detected
+	ListenerWorkerNum    int                      // the number of goroutine for running config listeners,default value is 8
+	ListenerCoalesce     bool                     // a busy config listener only receives the latest of its pending changes
+	ListenerQueueSize    int                      // the max number of pending changes of a config listener, further ones are merged into the last,default value is 64
+	RateLimit            *RateLimitConfig         // the rate limit of config queries, default is 5 queries per second for each config
 }
 
 type ClientLogSamplingConfig struct {
@@ -97,6 +104,16 @@ type ClientLogRollingConfig struct {
 	Compress bool
 }
 
+type RateLimitConfig struct {
+	Disable     bool    // turn off the rate limit of config queries
+	KeyQps      float64 // the queries per second allowed for each config, default value is 5
+	KeyBurst    int     // the queries of each config allowed at once, default value is 5
+	GlobalQps   float64 // the queries per second allowed for the whole client, 0 means unlimited
+	GlobalBurst int     // the queries of the whole client allowed at once, default value is GlobalQps
+	MaxWaitMs   uint64  // how long a limited query waits for its turn, 0 means it fails at once
+	MaxKeys     int     // the number of configs whose limiter is kept, the idle ones are evicted first, default value is 10000
+}
+
 type TLSConfig struct {
 	Appointed          bool   // Appointed or not ,if false,will get from env.
 	Enable             bool   // enable tls
@@ -120,6 +137,36 @@ type KMSConfig struct {
 	CaContent string
 }
 
+type VaultKMSConfig struct {
+	Address             string // the address of vault, e.g. https://vault.example.com:8200
+	Namespace           string // the vault enterprise namespace, optional
+	TransitMount        string // the mount path of the transit secrets engine, default value is transit
+	CaContent           string // the ca certificate verifying vault, the system ones are used when empty
+	TimeoutMs           uint64 // timeout for requesting vault, default value is 10000ms
+	Token               string // the token of token auth
+	AppRoleId           string // the role_id of approle auth, used when Token is empty
+	AppRoleSecretId     string // the secret_id of approle auth
+	AppRoleMount        string // the mount path of approle auth, default value is approle
+	KubernetesRole      string // the role of kubernetes auth, used when Token and AppRoleId are empty
+	KubernetesTokenFile string // the service account token of kubernetes auth, default value is /var/run/secrets/kubernetes.io/serviceaccount/token
+	KubernetesMount     string // the mount path of kubernetes auth, default value is kubernetes
+}
+
+type KMSDataKeyCacheConfig struct {
+	TtlMs            uint64 // how long a decrypted data key is used before kms is asked again, default value is 600000ms
+	StaleTtlMs       uint64 // how long past its ttl a data key is used while kms fails, default value is 3600000ms
+	MaxEntries       int    // the number of data keys kept, the least recently used are evicted first, default value is 1000
+	ZeroOnEvict      bool   // overwrite the evicted data keys in memory with zeros
+	FailureThreshold int    // the consecutive kms failures that stop calling kms for a while, default value is 5
+	OpenDurationMs   uint64 // how long kms isn't called after failing, default value is 30000ms
+}
+
+type LocalKMSConfig struct {
+	KeyringFile  string // the path of the keyring file
+	KeyringEnv   string // the environment variable holding the keyring when KeyringFile is empty, default value is NACOS_LOCAL_KEYRING
+	PrimaryKeyId string // the master key encrypting the configs being published, default value is the primary key of the keyring
+}
+
 type RamConfig struct {
 	SecurityToken         string
 	SignatureRegionId     string
@@ -23,6 +23,7 @@ type KMSVersion string
 const (
 	KMSv1               KMSVersion = "KMSv1"
 	KMSv3               KMSVersion = "KMSv3"
+	KMSVault            KMSVersion = "Vault"
 	DEFAULT_KMS_VERSION KMSVersion = "" //to fit original version
 	UNKNOWN_KMS_VERSION KMSVersion = "UNKNOWN_KMS_VERSION"
 )
@@ -56,6 +57,7 @@ const (
 	CONFIG_PATH                       = CONFIG_BASE_PATH + "/configs"
 	CONFIG_AGG_PATH                   = "/datum.do"
 	CONFIG_LISTEN_PATH                = CONFIG_BASE_PATH + "/configs/listener"
+	CONFIG_HISTORY_PATH               = CONFIG_BASE_PATH + "/history"
 	SERVICE_BASE_PATH                 = "/v1/ns"
 	SERVICE_PATH                      = SERVICE_BASE_PATH + "/instance"
 	SERVICE_INFO_PATH                 = SERVICE_BASE_PATH + "/service"
@@ -119,3 +121,16 @@ const (
 	CONFIG_BATCH_LISTEN_REQUEST_NAME  = "ConfigBatchListenRequest"
 	CONFIG_CHANGE_NOTIFY_REQUEST_NAME = "ConfigChangeNotifyRequest"
 )
+
+const (
+	NO_HANDLER                                    = 302
+	FUZZY_WATCH_SYNC_INTERVAL                     = 30 * time.Second
+	FUZZY_WATCH_PATTERN_SPLITTER                  = ">>"
+	FUZZY_WATCH_TYPE_WATCH                        = "ADD_WATCH"
+	FUZZY_WATCH_TYPE_CANCEL                       = "CANCEL_WATCH"
+	FUZZY_WATCH_ADD_CONFIG                        = "ADD_CONFIG"
+	FUZZY_WATCH_DELETE_CONFIG                     = "DELETE_CONFIG"
+	CONFIG_FUZZY_WATCH_REQUEST_NAME               = "ConfigFuzzyWatchRequest"
+	CONFIG_FUZZY_WATCH_CHANGE_NOTIFY_REQUEST_NAME = "ConfigFuzzyWatchChangeNotifyRequest"
+	CONFIG_FUZZY_WATCH_SYNC_REQUEST_NAME          = "ConfigFuzzyWatchSyncRequest"
+)
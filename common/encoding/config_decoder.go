@@ -0,0 +1,224 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoding
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ConfigTypeText       = "text"
+	ConfigTypeJson       = "json"
+	ConfigTypeYaml       = "yaml"
+	ConfigTypeProperties = "properties"
+	ConfigTypeToml       = "toml"
+	ConfigTypeXml        = "xml"
+)
+
+// ConfigDecoder decodes config content into target, which must be a non-nil pointer.
+type ConfigDecoder func(content []byte, target interface{}) error
+
+var (
+	decoderMux sync.RWMutex
+	decoders   = map[string]ConfigDecoder{
+		ConfigTypeJson:       decodeJson,
+		ConfigTypeYaml:       decodeYaml,
+		ConfigTypeProperties: decodeProperties,
+		ConfigTypeToml:       decodeToml,
+		ConfigTypeXml:        decodeXml,
+	}
+)
+
+// RegisterConfigDecoder adds or replaces the decoder used for configType.
+func RegisterConfigDecoder(configType string, decoder ConfigDecoder) {
+	decoderMux.Lock()
+	defer decoderMux.Unlock()
+	decoders[NormalizeConfigType(configType)] = decoder
+}
+
+// GetConfigDecoder returns the decoder registered for configType.
+func GetConfigDecoder(configType string) (ConfigDecoder, error) {
+	decoderMux.RLock()
+	defer decoderMux.RUnlock()
+	decoder, ok := decoders[NormalizeConfigType(configType)]
+	if !ok {
+		return nil, errors.Errorf("no decoder registered for config type [%s]", configType)
+	}
+	return decoder, nil
+}
+
+// DecodeConfig decodes content into target with the decoder registered for configType.
+func DecodeConfig(configType string, content string, target interface{}) error {
+	decoder, err := GetConfigDecoder(configType)
+	if err != nil {
+		return err
+	}
+	return decoder([]byte(content), target)
+}
+
+// DecodeConfigToMap decodes content into a generic tree of maps, slices and scalars.
+func DecodeConfigToMap(configType string, content string) (map[string]interface{}, error) {
+	if NormalizeConfigType(configType) == ConfigTypeXml {
+		return nil, errors.New("xml content can not be decoded into a map")
+	}
+	result := make(map[string]interface{})
+	if len(strings.TrimSpace(content)) == 0 {
+		return result, nil
+	}
+	if err := DecodeConfig(configType, content, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FlattenConfig decodes content into a flat map, nested keys are joined with "." and list elements
+// are addressed as key[index]. Properties content is returned as parsed.
+func FlattenConfig(configType string, content string) (map[string]string, error) {
+	if NormalizeConfigType(configType) == ConfigTypeProperties {
+		return ParseProperties(content)
+	}
+	tree, err := DecodeConfigToMap(configType, content)
+	if err != nil {
+		return nil, err
+	}
+	return FlattenMap(tree), nil
+}
+
+// FlattenMap flattens a tree decoded by DecodeConfigToMap the way FlattenConfig does.
+func FlattenMap(tree map[string]interface{}) map[string]string {
+	result := make(map[string]string)
+	flattenValue(result, "", tree)
+	return result
+}
+
+func flattenValue(result map[string]string, key string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if len(key) > 0 {
+				k = key + "." + k
+			}
+			flattenValue(result, k, child)
+		}
+	case map[interface{}]interface{}:
+		for k, child := range v {
+			childKey := fmt.Sprint(k)
+			if len(key) > 0 {
+				childKey = key + "." + childKey
+			}
+			flattenValue(result, childKey, child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenValue(result, fmt.Sprintf("%s[%d]", key, i), child)
+		}
+	case []map[string]interface{}:
+		for i, child := range v {
+			flattenValue(result, fmt.Sprintf("%s[%d]", key, i), child)
+		}
+	case nil:
+		result[key] = ""
+	default:
+		result[key] = fmt.Sprint(v)
+	}
+}
+
+// NormalizeConfigType lower cases configType and folds its aliases, e.g. yml to yaml.
+func NormalizeConfigType(configType string) string {
+	configType = strings.ToLower(strings.TrimSpace(configType))
+	if configType == "yml" {
+		return ConfigTypeYaml
+	}
+	return configType
+}
+
+// ResolveConfigType returns configType if set, otherwise the type implied by the extension of dataId.
+func ResolveConfigType(configType, dataId string) string {
+	if len(configType) > 0 {
+		return NormalizeConfigType(configType)
+	}
+	ext := strings.TrimPrefix(path.Ext(dataId), ".")
+	if len(ext) == 0 {
+		return ConfigTypeText
+	}
+	return NormalizeConfigType(ext)
+}
+
+func decodeJson(content []byte, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	return decoder.Decode(target)
+}
+
+func decodeYaml(content []byte, target interface{}) error {
+	return yaml.Unmarshal(content, target)
+}
+
+func decodeToml(content []byte, target interface{}) error {
+	return toml.Unmarshal(content, target)
+}
+
+func decodeXml(content []byte, target interface{}) error {
+	return xml.Unmarshal(content, target)
+}
+
+// decodeProperties turns the flat dotted keys into a yaml mapping tree, so that values are resolved
+// against the field types of target the same way yaml content is.
+func decodeProperties(content []byte, target interface{}) error {
+	properties, err := ParseProperties(string(content))
+	if err != nil {
+		return err
+	}
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range sortedKeys(properties) {
+		if err = putPropertyNode(root, strings.Split(key, "."), properties[key]); err != nil {
+			return errors.Wrapf(err, "property [%s]", key)
+		}
+	}
+	return root.Decode(target)
+}
+
+func putPropertyNode(parent *yaml.Node, keys []string, value string) error {
+	for i := 0; i < len(parent.Content); i += 2 {
+		if parent.Content[i].Value != keys[0] {
+			continue
+		}
+		child := parent.Content[i+1]
+		if len(keys) == 1 || child.Kind != yaml.MappingNode {
+			return errors.New("conflicts with another property")
+		}
+		return putPropertyNode(child, keys[1:], value)
+	}
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: keys[0]}
+	if len(keys) == 1 {
+		parent.Content = append(parent.Content, keyNode, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+		return nil
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	parent.Content = append(parent.Content, keyNode, child)
+	return putPropertyNode(child, keys[1:], value)
+}
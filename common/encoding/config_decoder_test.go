@@ -0,0 +1,94 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type decoderTestConfig struct {
+	Server struct {
+		Host string `json:"host" yaml:"host" toml:"host" xml:"host"`
+		Port int    `json:"port" yaml:"port" toml:"port" xml:"port"`
+	} `json:"server" yaml:"server" toml:"server" xml:"server"`
+	Debug bool `json:"debug" yaml:"debug" toml:"debug" xml:"debug"`
+}
+
+func TestDecodeConfig(t *testing.T) {
+	contents := map[string]string{
+		ConfigTypeJson:       `{"server":{"host":"127.0.0.1","port":8848},"debug":true}`,
+		ConfigTypeYaml:       "server:\n  host: 127.0.0.1\n  port: 8848\ndebug: true\n",
+		ConfigTypeProperties: "# comment\nserver.host=127.0.0.1\nserver.port : 8848\ndebug true\n",
+		ConfigTypeToml:       "debug = true\n[server]\nhost = \"127.0.0.1\"\nport = 8848\n",
+		ConfigTypeXml:        "<config><server><host>127.0.0.1</host><port>8848</port></server><debug>true</debug></config>",
+	}
+	for configType, content := range contents {
+		t.Run(configType, func(t *testing.T) {
+			var config decoderTestConfig
+			assert.Nil(t, DecodeConfig(configType, content, &config))
+			assert.Equal(t, "127.0.0.1", config.Server.Host)
+			assert.Equal(t, 8848, config.Server.Port)
+			assert.True(t, config.Debug)
+		})
+	}
+}
+
+func TestDecodeConfig_Unsupported(t *testing.T) {
+	var config decoderTestConfig
+	assert.NotNil(t, DecodeConfig(ConfigTypeText, "hello", &config))
+}
+
+func TestDecodeConfigToMap(t *testing.T) {
+	result, err := DecodeConfigToMap("yml", "a:\n  b: 1\n")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": 1}}, result)
+
+	result, err = DecodeConfigToMap(ConfigTypeProperties, "a.b=x\na.c=y")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": "x", "c": "y"}}, result)
+
+	_, err = DecodeConfigToMap(ConfigTypeProperties, "a=x\na.c=y")
+	assert.NotNil(t, err)
+}
+
+func TestResolveConfigType(t *testing.T) {
+	assert.Equal(t, ConfigTypeYaml, ResolveConfigType("", "application.yml"))
+	assert.Equal(t, ConfigTypeJson, ResolveConfigType("JSON", "application.yml"))
+	assert.Equal(t, ConfigTypeText, ResolveConfigType("", "application"))
+}
+
+func TestParseProperties(t *testing.T) {
+	properties, err := ParseProperties("! comment\n  a.b = 1\nmulti=first \\\n    second\nescaped\\=key=\\u4e2d\\t")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"a.b":         "1",
+		"multi":       "first second",
+		"escaped=key": "中\t",
+	}, properties)
+}
+
+func TestFlattenConfig(t *testing.T) {
+	result, err := FlattenConfig(ConfigTypeJson, `{"a":{"b":1,"c":[true,{"d":null}]}}`)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"a.b": "1", "a.c[0]": "true", "a.c[1].d": ""}, result)
+
+	result, err = FlattenConfig(ConfigTypeProperties, "a.b=1")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"a.b": "1"}, result)
+}
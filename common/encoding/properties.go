@@ -0,0 +1,125 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encoding
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ParseProperties parses java style properties content, later keys win over earlier ones.
+func ParseProperties(content string) (map[string]string, error) {
+	result := make(map[string]string)
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if len(line) == 0 || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		// a line ending with an odd number of backslashes continues on the next line
+		for hasContinuation(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		key, value := splitProperty(line)
+		unescapedKey, err := unescapeProperty(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", i+1)
+		}
+		unescapedValue, err := unescapeProperty(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", i+1)
+		}
+		result[unescapedKey] = unescapedValue
+	}
+	return result, nil
+}
+
+func hasContinuation(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':':
+			return line[:i], strings.TrimLeft(line[i+1:], " \t\f")
+		case ' ', '\t', '\f':
+			value := strings.TrimLeft(line[i:], " \t\f")
+			if len(value) > 0 && (value[0] == '=' || value[0] == ':') {
+				value = strings.TrimLeft(value[1:], " \t\f")
+			}
+			return line[:i], value
+		}
+	}
+	return line, ""
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", errors.New("malformed \\uxxxx encoding")
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", errors.New("malformed \\uxxxx encoding")
+			}
+			sb.WriteRune(rune(r))
+			i += 4
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String(), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
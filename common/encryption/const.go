@@ -23,7 +23,10 @@ const (
 
 	KmsAes128AlgorithmName = "cipher-kms-aes-128"
 	KmsAes256AlgorithmName = "cipher-kms-aes-256"
-	KmsAlgorithmName       = "cipher"
+	// the gcm algorithms are matched before the ecb ones of the same key size, their name being longer
+	KmsAes128GcmAlgorithmName = "cipher-kms-aes-128-gcm"
+	KmsAes256GcmAlgorithmName = "cipher-kms-aes-256-gcm"
+	KmsAlgorithmName          = "cipher"
 
 	kmsAes128KeySpec = "AES_128"
 	kmsAes256KeySpec = "AES_256"
@@ -37,6 +40,23 @@ const (
 	maskUnit32Width = 32
 
 	KmsHandlerName = "KmsHandler"
+
+	LocalAes256GcmAlgorithmName = "cipher-local-aes-256"
+	// DefaultLocalKeyringEnv is the environment variable holding the local keyring when no keyring file is set
+	DefaultLocalKeyringEnv = "NACOS_LOCAL_KEYRING"
+
+	localMasterKeySize  = 32
+	localKeyIdSeparator = ":"
+
+	defaultVaultTransitMount        = "transit"
+	defaultVaultAppRoleMount        = "approle"
+	defaultVaultKubernetesMount     = "kubernetes"
+	defaultVaultKubernetesTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	defaultVaultTimeoutMs           = 10000
+	// vaultKeyNameSeparator separates the transit key name from the vault ciphertext, which doesn't name its key
+	vaultKeyNameSeparator = ":"
+	// vaultDataKeyMarker marks the ciphertext of a data key, whose plaintext is kept base64 encoded
+	vaultDataKeyMarker = "datakey:"
 )
 
 var (
@@ -49,6 +69,14 @@ var (
 	PluginNotFoundError = fmt.Errorf("cannot find encryption plugin by dataId prefix")
 )
 
+var (
+	KmsCircuitOpenError = fmt.Errorf("kms is unavailable, data key not cached")
+)
+
+var (
+	EmptyLocalKeyringError = fmt.Errorf("local keyring has no key")
+)
+
 var (
 	EmptyEncryptedDataKeyError = fmt.Errorf("empty encrypted data key error")
 	EmptyPlainDataKeyError     = fmt.Errorf("empty plain data key error")
@@ -65,4 +93,6 @@ var (
 	EmptyClientKeyContentKmsV3ClientInitError = fmt.Errorf("init kmsV3 client failed with empty client key content")
 	EmptyCaVerifyKmsV3ClientInitError         = fmt.Errorf("init kmsV3 client failed with empty ca verify")
 	EmptyEndpointKmsRamClientInitError        = fmt.Errorf("init kmsRam client failed with empty endpoint")
+	EmptyAddressVaultClientInitError          = fmt.Errorf("init vault client failed with empty address")
+	EmptyAuthVaultClientInitError             = fmt.Errorf("init vault client failed with no token, approle or kubernetes role")
 )
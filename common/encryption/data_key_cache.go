@@ -0,0 +1,295 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/monitor"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultDataKeyCacheTtl        = 10 * time.Minute
+	defaultDataKeyCacheStaleTtl   = time.Hour
+	defaultDataKeyCacheMaxEntries = 1000
+	defaultKmsFailureThreshold    = 5
+	defaultKmsOpenDuration        = 30 * time.Second
+)
+
+// DataKeyCacheStats are the counters of a DataKeyCache since it was created.
+type DataKeyCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	StaleHits uint64 // cached keys served past their ttl because KMS failed
+	Evictions uint64
+	Entries   int
+}
+
+type dataKeyEntry struct {
+	hash       string
+	plainKey   []byte
+	expireAt   time.Time
+	staleUntil time.Time
+}
+
+type dataKeyCall struct {
+	done     chan struct{}
+	plainKey string
+	err      error
+}
+
+// DataKeyCache keeps the data keys decrypted by KMS, keyed by the sha256 of their encrypted form, so that the configs
+// sharing a data key are decrypted with a single KMS call. A cached key is used for its ttl, and past it as long as
+// KMS fails, for at most the stale ttl. Concurrent lookups of the same key wait for a single KMS call.
+//
+// A circuit breaker stops calling KMS after a number of consecutive failures, the lookups are served from the cache,
+// stale keys included, or fail at once with KmsCircuitOpenError until a trial call succeeds after the open duration.
+type DataKeyCache struct {
+	mux         sync.Mutex
+	entries     map[string]*list.Element
+	lru         *list.List
+	calls       map[string]*dataKeyCall
+	ttl         time.Duration
+	staleTtl    time.Duration
+	maxEntries  int
+	zeroOnEvict bool
+	breaker     kmsCircuitBreaker
+	stats       DataKeyCacheStats
+	now         func() time.Time
+}
+
+func NewDataKeyCache(config constant.KMSDataKeyCacheConfig) *DataKeyCache {
+	cache := &DataKeyCache{
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+		calls:       make(map[string]*dataKeyCall),
+		ttl:         time.Duration(config.TtlMs) * time.Millisecond,
+		staleTtl:    time.Duration(config.StaleTtlMs) * time.Millisecond,
+		maxEntries:  config.MaxEntries,
+		zeroOnEvict: config.ZeroOnEvict,
+		breaker: kmsCircuitBreaker{
+			failureThreshold: config.FailureThreshold,
+			openDuration:     time.Duration(config.OpenDurationMs) * time.Millisecond,
+		},
+		now: time.Now,
+	}
+	if cache.ttl <= 0 {
+		cache.ttl = defaultDataKeyCacheTtl
+	}
+	if cache.staleTtl <= 0 {
+		cache.staleTtl = defaultDataKeyCacheStaleTtl
+	}
+	if cache.maxEntries <= 0 {
+		cache.maxEntries = defaultDataKeyCacheMaxEntries
+	}
+	if cache.breaker.failureThreshold <= 0 {
+		cache.breaker.failureThreshold = defaultKmsFailureThreshold
+	}
+	if cache.breaker.openDuration <= 0 {
+		cache.breaker.openDuration = defaultKmsOpenDuration
+	}
+	return cache
+}
+
+// Get returns the plain data key of encryptedDataKey, calling decrypt when it isn't cached or its ttl passed.
+func (c *DataKeyCache) Get(encryptedDataKey string, decrypt func(encryptedDataKey string) (string, error)) (string, error) {
+	sum := sha256.Sum256([]byte(encryptedDataKey))
+	hash := hex.EncodeToString(sum[:])
+
+	c.mux.Lock()
+	now := c.now()
+	entry := c.lookup(hash, now)
+	if entry != nil && now.Before(entry.expireAt) {
+		c.hit(false)
+		plainKey := string(entry.plainKey)
+		c.mux.Unlock()
+		return plainKey, nil
+	}
+	if call, ok := c.calls[hash]; ok {
+		c.mux.Unlock()
+		<-call.done
+		return call.plainKey, call.err
+	}
+	if !c.breaker.allow(now) {
+		defer c.mux.Unlock()
+		if entry != nil {
+			c.hit(true)
+			return string(entry.plainKey), nil
+		}
+		return "", KmsCircuitOpenError
+	}
+	c.stats.Misses++
+	monitor.GetKmsDataKeyCacheMonitor("miss").Inc()
+	call := &dataKeyCall{done: make(chan struct{})}
+	c.calls[hash] = call
+	c.mux.Unlock()
+
+	plainKey, err := decrypt(encryptedDataKey)
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	delete(c.calls, hash)
+	defer close(call.done)
+	now = c.now()
+	if err != nil {
+		if c.breaker.onFailure(now) {
+			logger.Warnf("kms failed %d times in a row, stop calling it for %s: %v", c.breaker.failures,
+				c.breaker.openDuration, err)
+			monitor.GetKmsDataKeyCacheMonitor("breakerOpen").Inc()
+		}
+		if entry = c.lookup(hash, now); entry != nil {
+			logger.Warnf("kms failed, serve the cached data key past its ttl: %v", err)
+			c.hit(true)
+			call.plainKey = string(entry.plainKey)
+			return call.plainKey, nil
+		}
+		call.err = err
+		return "", err
+	}
+	c.breaker.onSuccess()
+	if len(plainKey) == 0 {
+		call.err = EmptyPlainDataKeyError
+		return "", call.err
+	}
+	c.put(hash, plainKey, now)
+	call.plainKey = plainKey
+	return plainKey, nil
+}
+
+func (c *DataKeyCache) Stats() DataKeyCacheStats {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	return stats
+}
+
+// Clear evicts all the cached keys.
+func (c *DataKeyCache) Clear() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for c.lru.Len() > 0 {
+		c.evict(c.lru.Back())
+	}
+}
+
+// lookup returns the entry of hash unless it's past its stale ttl, in which case it's evicted.
+func (c *DataKeyCache) lookup(hash string, now time.Time) *dataKeyEntry {
+	element, ok := c.entries[hash]
+	if !ok {
+		return nil
+	}
+	entry := element.Value.(*dataKeyEntry)
+	if !now.Before(entry.staleUntil) {
+		c.evict(element)
+		return nil
+	}
+	c.lru.MoveToFront(element)
+	return entry
+}
+
+func (c *DataKeyCache) put(hash, plainKey string, now time.Time) {
+	if element, ok := c.entries[hash]; ok {
+		c.evict(element)
+	}
+	entry := &dataKeyEntry{
+		hash:       hash,
+		plainKey:   []byte(plainKey),
+		expireAt:   now.Add(c.ttl),
+		staleUntil: now.Add(c.ttl + c.staleTtl),
+	}
+	c.entries[hash] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		c.evict(c.lru.Back())
+	}
+}
+
+func (c *DataKeyCache) evict(element *list.Element) {
+	entry := c.lru.Remove(element).(*dataKeyEntry)
+	delete(c.entries, entry.hash)
+	if c.zeroOnEvict {
+		for i := range entry.plainKey {
+			entry.plainKey[i] = 0
+		}
+	}
+	c.stats.Evictions++
+	monitor.GetKmsDataKeyCacheMonitor("evict").Inc()
+}
+
+func (c *DataKeyCache) hit(stale bool) {
+	if stale {
+		c.stats.StaleHits++
+		monitor.GetKmsDataKeyCacheMonitor("staleHit").Inc()
+		return
+	}
+	c.stats.Hits++
+	monitor.GetKmsDataKeyCacheMonitor("hit").Inc()
+}
+
+// kmsCircuitBreaker opens after failureThreshold consecutive failures, once openDuration passed a single trial call
+// is let through, which closes it again on success.
+type kmsCircuitBreaker struct {
+	failureThreshold int
+	openDuration     time.Duration
+	failures         int
+	openUntil        time.Time
+	trialInFlight    bool
+}
+
+func (b *kmsCircuitBreaker) allow(now time.Time) bool {
+	if b.failures < b.failureThreshold {
+		return true
+	}
+	if now.Before(b.openUntil) || b.trialInFlight {
+		return false
+	}
+	b.trialInFlight = true
+	return true
+}
+
+// onFailure reports whether the failure opened the breaker.
+func (b *kmsCircuitBreaker) onFailure(now time.Time) bool {
+	b.trialInFlight = false
+	b.failures++
+	if b.failures < b.failureThreshold {
+		return false
+	}
+	b.openUntil = now.Add(b.openDuration)
+	return true
+}
+
+func (b *kmsCircuitBreaker) onSuccess() {
+	b.failures = 0
+	b.trialInFlight = false
+}
+
+func decryptDataKeyWith(cache *DataKeyCache, kmsClient KmsClient, encryptedDataKey string) (string, error) {
+	if kmsClient == nil {
+		return "", errors.New("kms client is not initialized")
+	}
+	if cache == nil {
+		return kmsClient.Decrypt(encryptedDataKey)
+	}
+	return cache.Get(encryptedDataKey, kmsClient.Decrypt)
+}
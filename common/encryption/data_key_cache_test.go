@@ -0,0 +1,213 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// fakeKmsClient decrypts a data key by prefixing it with "plain-"
+type fakeKmsClient struct {
+	calls  int32
+	failed atomic.Bool
+}
+
+func (f *fakeKmsClient) Decrypt(cipherContent string) (string, error) {
+	atomic.AddInt32(&f.calls, 1)
+	if f.failed.Load() {
+		return "", errors.New("kms throttled")
+	}
+	return "plain-" + cipherContent, nil
+}
+
+func (f *fakeKmsClient) Encrypt(content string, keyId string) (string, error) {
+	return "", nil
+}
+
+func (f *fakeKmsClient) GenerateDataKey(keyId, keySpec string) (string, string, error) {
+	return "", "", nil
+}
+
+func (f *fakeKmsClient) GetKmsVersion() constant.KMSVersion {
+	return constant.KMSv1
+}
+
+func (f *fakeKmsClient) setKmsVersion(constant.KMSVersion) {}
+
+func newTestDataKeyCache(config constant.KMSDataKeyCacheConfig) (*DataKeyCache, *fakeClock) {
+	cache := NewDataKeyCache(config)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cache.now = clock.Now
+	return cache, clock
+}
+
+func TestDataKeyCache_Ttl(t *testing.T) {
+	cache, clock := newTestDataKeyCache(constant.KMSDataKeyCacheConfig{TtlMs: 1000})
+	kms := &fakeKmsClient{}
+	for i := 0; i < 3; i++ {
+		plainKey, err := cache.Get("key-a", kms.Decrypt)
+		assert.Nil(t, err)
+		assert.Equal(t, "plain-key-a", plainKey)
+	}
+	assert.Equal(t, int32(1), kms.calls)
+	stats := cache.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, 1, stats.Entries)
+
+	clock.Advance(time.Second)
+	_, err := cache.Get("key-a", kms.Decrypt)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), kms.calls)
+
+	// the entries past their stale ttl are dropped
+	clock.Advance(2 * time.Hour)
+	kms.failed.Store(true)
+	_, err = cache.Get("key-a", kms.Decrypt)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, cache.Stats().Entries)
+}
+
+func TestDataKeyCache_Bounded(t *testing.T) {
+	cache, _ := newTestDataKeyCache(constant.KMSDataKeyCacheConfig{MaxEntries: 2, ZeroOnEvict: true})
+	kms := &fakeKmsClient{}
+	_, _ = cache.Get("key-a", kms.Decrypt)
+	evicted := cache.lru.Front().Value.(*dataKeyEntry).plainKey
+	_, _ = cache.Get("key-b", kms.Decrypt)
+	_, _ = cache.Get("key-c", kms.Decrypt)
+	stats := cache.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, make([]byte, len("plain-key-a")), evicted)
+
+	// key-b was used last, so key-c is the one evicted
+	_, _ = cache.Get("key-b", kms.Decrypt)
+	_, _ = cache.Get("key-d", kms.Decrypt)
+	calls := kms.calls
+	_, _ = cache.Get("key-b", kms.Decrypt)
+	assert.Equal(t, calls, kms.calls)
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Stats().Entries)
+}
+
+func TestDataKeyCache_SingleCall(t *testing.T) {
+	cache, _ := newTestDataKeyCache(constant.KMSDataKeyCacheConfig{})
+	release := make(chan struct{})
+	var calls int32
+	decrypt := func(encryptedDataKey string) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "plain", nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			plainKey, err := cache.Get("key-a", decrypt)
+			assert.Nil(t, err)
+			assert.Equal(t, "plain", plainKey)
+		}()
+	}
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestDataKeyCache_CircuitBreaker(t *testing.T) {
+	cache, clock := newTestDataKeyCache(constant.KMSDataKeyCacheConfig{TtlMs: 1000, FailureThreshold: 2, OpenDurationMs: 5000})
+	kms := &fakeKmsClient{}
+	_, err := cache.Get("key-a", kms.Decrypt)
+	assert.Nil(t, err)
+
+	// past its ttl the cached key is served while kms fails
+	clock.Advance(2 * time.Second)
+	kms.failed.Store(true)
+	for i := 0; i < 2; i++ {
+		plainKey, err := cache.Get("key-a", kms.Decrypt)
+		assert.Nil(t, err)
+		assert.Equal(t, "plain-key-a", plainKey)
+	}
+	assert.Equal(t, int32(3), kms.calls)
+	assert.Equal(t, uint64(2), cache.Stats().StaleHits)
+
+	// the breaker is open, kms isn't called anymore
+	plainKey, err := cache.Get("key-a", kms.Decrypt)
+	assert.Nil(t, err)
+	assert.Equal(t, "plain-key-a", plainKey)
+	_, err = cache.Get("key-b", kms.Decrypt)
+	assert.Equal(t, KmsCircuitOpenError, err)
+	assert.Equal(t, int32(3), kms.calls)
+
+	// a trial call is let through after the open duration, its failure opens the breaker again
+	clock.Advance(5 * time.Second)
+	_, err = cache.Get("key-b", kms.Decrypt)
+	assert.NotNil(t, err)
+	assert.NotEqual(t, KmsCircuitOpenError, err)
+	_, err = cache.Get("key-b", kms.Decrypt)
+	assert.Equal(t, KmsCircuitOpenError, err)
+	assert.Equal(t, int32(4), kms.calls)
+
+	// and its success closes it
+	clock.Advance(5 * time.Second)
+	kms.failed.Store(false)
+	plainKey, err = cache.Get("key-b", kms.Decrypt)
+	assert.Nil(t, err)
+	assert.Equal(t, "plain-key-b", plainKey)
+	_, err = cache.Get("key-c", kms.Decrypt)
+	assert.Nil(t, err)
+}
+
+func TestKmsPlugin_DataKeyCache(t *testing.T) {
+	kms := &fakeKmsClient{}
+	plugin := &KmsAes256Plugin{kmsPlugin{kmsClient: kms, dataKeyCache: NewDataKeyCache(constant.KMSDataKeyCacheConfig{})}}
+	for i := 0; i < 3; i++ {
+		param := &HandlerParam{DataId: "cipher-kms-aes-256-app", EncryptedDataKey: "key-a"}
+		plainKey, err := plugin.DecryptSecretKey(param)
+		assert.Nil(t, err)
+		assert.Equal(t, "plain-key-a", plainKey)
+		assert.Equal(t, "plain-key-a", param.PlainDataKey)
+	}
+	assert.Equal(t, int32(1), kms.calls)
+
+	uncached := &KmsAes256Plugin{kmsPlugin{kmsClient: kms}}
+	_, err := uncached.DecryptSecretKey(&HandlerParam{EncryptedDataKey: "key-a"})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), kms.calls)
+}
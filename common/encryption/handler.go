@@ -29,6 +29,7 @@ import (
 type HandlerParam struct {
 	DataId           string `json:"dataId"`  //required
 	Content          string `json:"content"` //required
+	Group            string `json:"group"`
 	EncryptedDataKey string `json:"encryptedDataKey"`
 	PlainDataKey     string `json:"plainDataKey"`
 	KeyId            string `json:"keyId"`
@@ -62,7 +63,15 @@ func newKmsHandler() *KmsHandler {
 	return kmsHandler
 }
 
+// RegisterConfigEncryptionKmsPlugins registers the local plugin when clientConfig has a LocalKMSConfig, and the
+// plugins of the kms client of KMSVersion, Alibaba Cloud KMS or Vault, unless only the local one is configured.
 func RegisterConfigEncryptionKmsPlugins(encryptionHandler Handler, clientConfig constant.ClientConfig) {
+	if clientConfig.LocalKMSConfig != nil {
+		registerConfigEncryptionLocalPlugin(encryptionHandler, clientConfig.LocalKMSConfig)
+		if !clientConfig.OpenKMS {
+			return
+		}
+	}
 	innerKmsClient, err := innerNewKmsClient(clientConfig)
 	if innerKmsClient == nil {
 		err = errors.New("create kms client failed.")
@@ -73,16 +82,30 @@ func RegisterConfigEncryptionKmsPlugins(encryptionHandler Handler, clientConfig
 	if err != nil {
 		logger.Error(err)
 	}
-	if err := encryptionHandler.RegisterPlugin(&KmsAes128Plugin{kmsPlugin{kmsClient: innerKmsClient}}); err != nil {
+	var dataKeyCache *DataKeyCache
+	if clientConfig.KMSDataKeyCache != nil {
+		dataKeyCache = NewDataKeyCache(*clientConfig.KMSDataKeyCache)
+	}
+	if err := encryptionHandler.RegisterPlugin(&KmsAes128Plugin{kmsPlugin{kmsClient: innerKmsClient, dataKeyCache: dataKeyCache}}); err != nil {
 		logger.Errorf("failed to register encryption plugin[%s] to %s", KmsAes128AlgorithmName, encryptionHandler.GetHandlerName())
 	} else {
 		logger.Debugf("successfully register encryption plugin[%s] to %s", KmsAes128AlgorithmName, encryptionHandler.GetHandlerName())
 	}
-	if err := encryptionHandler.RegisterPlugin(&KmsAes256Plugin{kmsPlugin{kmsClient: innerKmsClient}}); err != nil {
+	if err := encryptionHandler.RegisterPlugin(&KmsAes256Plugin{kmsPlugin{kmsClient: innerKmsClient, dataKeyCache: dataKeyCache}}); err != nil {
 		logger.Errorf("failed to register encryption plugin[%s] to %s", KmsAes256AlgorithmName, encryptionHandler.GetHandlerName())
 	} else {
 		logger.Debugf("successfully register encryption plugin[%s] to %s", KmsAes256AlgorithmName, encryptionHandler.GetHandlerName())
 	}
+	if err := encryptionHandler.RegisterPlugin(NewKmsAes128GcmPlugin(innerKmsClient, dataKeyCache)); err != nil {
+		logger.Errorf("failed to register encryption plugin[%s] to %s", KmsAes128GcmAlgorithmName, encryptionHandler.GetHandlerName())
+	} else {
+		logger.Debugf("successfully register encryption plugin[%s] to %s", KmsAes128GcmAlgorithmName, encryptionHandler.GetHandlerName())
+	}
+	if err := encryptionHandler.RegisterPlugin(NewKmsAes256GcmPlugin(innerKmsClient, dataKeyCache)); err != nil {
+		logger.Errorf("failed to register encryption plugin[%s] to %s", KmsAes256GcmAlgorithmName, encryptionHandler.GetHandlerName())
+	} else {
+		logger.Debugf("successfully register encryption plugin[%s] to %s", KmsAes256GcmAlgorithmName, encryptionHandler.GetHandlerName())
+	}
 	if err := encryptionHandler.RegisterPlugin(&KmsBasePlugin{kmsPlugin{kmsClient: innerKmsClient}}); err != nil {
 		logger.Errorf("failed to register encryption plugin[%s] to %s", KmsAlgorithmName, encryptionHandler.GetHandlerName())
 	} else {
@@ -90,6 +113,20 @@ func RegisterConfigEncryptionKmsPlugins(encryptionHandler Handler, clientConfig
 	}
 }
 
+func registerConfigEncryptionLocalPlugin(encryptionHandler Handler, config *constant.LocalKMSConfig) {
+	keyring, err := LoadLocalKeyring(config)
+	if err != nil {
+		logger.Errorf("failed to load local keyring: %v", err)
+		return
+	}
+	if err := encryptionHandler.RegisterPlugin(NewLocalAesGcmPlugin(keyring)); err != nil {
+		logger.Errorf("failed to register encryption plugin[%s] to %s", LocalAes256GcmAlgorithmName, encryptionHandler.GetHandlerName())
+	} else {
+		logger.Debugf("successfully register encryption plugin[%s] to %s with primary key [%s]", LocalAes256GcmAlgorithmName,
+			encryptionHandler.GetHandlerName(), keyring.PrimaryKeyId())
+	}
+}
+
 type KmsHandler struct {
 	encryptionPlugins map[string]Plugin
 }
@@ -194,12 +231,19 @@ func (d *KmsHandler) contentParamCheck(content string) error {
 	return nil
 }
 
+// NewKmsClient creates the kms client of clientConfig.KMSVersion, like the one decrypting the cipher- configs.
+func NewKmsClient(clientConfig constant.ClientConfig) (KmsClient, error) {
+	return innerNewKmsClient(clientConfig)
+}
+
 func innerNewKmsClient(clientConfig constant.ClientConfig) (kmsClient KmsClient, err error) {
 	switch clientConfig.KMSVersion {
 	case constant.KMSv1, constant.DEFAULT_KMS_VERSION:
 		kmsClient, err = newKmsRamClient(clientConfig)
 	case constant.KMSv3:
 		kmsClient, err = newKmsV3Client(clientConfig)
+	case constant.KMSVault:
+		kmsClient, err = NewVaultKmsClient(clientConfig.VaultKMSConfig)
 	default:
 		err = fmt.Errorf("init kms client failed. unknown kms version:%s\n", clientConfig.KMSVersion)
 	}
@@ -24,7 +24,8 @@ import (
 )
 
 type kmsPlugin struct {
-	kmsClient KmsClient
+	kmsClient    KmsClient
+	dataKeyCache *DataKeyCache
 }
 
 func (k *kmsPlugin) Encrypt(param *HandlerParam) error {
@@ -78,6 +79,28 @@ func (k *kmsPlugin) GenerateSecretKey(param *HandlerParam) (string, error) {
 	return "", nil
 }
 
+// generateSecretKey asks kms for a new data key of keySpec, returned both plain and encrypted.
+func (k *kmsPlugin) generateSecretKey(param *HandlerParam, keySpec string) (string, error) {
+	var keyId string
+	var err error
+	if keyId, err = k.keyIdParamCheck(param.KeyId); err != nil {
+		return "", err
+	}
+	plainSecretKey, encryptedSecretKey, err := k.kmsClient.GenerateDataKey(keyId, keySpec)
+	if err != nil {
+		return "", err
+	}
+	param.PlainDataKey = plainSecretKey
+	param.EncryptedDataKey = encryptedSecretKey
+	if len(param.PlainDataKey) == 0 {
+		return "", EmptyPlainDataKeyError
+	}
+	if len(param.EncryptedDataKey) == 0 {
+		return "", EmptyEncryptedDataKeyError
+	}
+	return plainSecretKey, nil
+}
+
 func (k *kmsPlugin) EncryptSecretKey(param *HandlerParam) (string, error) {
 	var keyId string
 	var err error
@@ -102,7 +125,7 @@ func (k *kmsPlugin) DecryptSecretKey(param *HandlerParam) (string, error) {
 	if len(param.EncryptedDataKey) == 0 {
 		return "", EmptyEncryptedDataKeyError
 	}
-	plainDataKey, err := k.kmsClient.Decrypt(param.EncryptedDataKey)
+	plainDataKey, err := decryptDataKeyWith(k.dataKeyCache, k.kmsClient, param.EncryptedDataKey)
 	if err != nil {
 		return "", err
 	}
@@ -175,24 +198,7 @@ func (k *KmsAes128Plugin) AlgorithmName() string {
 }
 
 func (k *KmsAes128Plugin) GenerateSecretKey(param *HandlerParam) (string, error) {
-	var keyId string
-	var err error
-	if keyId, err = k.keyIdParamCheck(param.KeyId); err != nil {
-		return "", err
-	}
-	plainSecretKey, encryptedSecretKey, err := k.kmsClient.GenerateDataKey(keyId, kmsAes128KeySpec)
-	if err != nil {
-		return "", err
-	}
-	param.PlainDataKey = plainSecretKey
-	param.EncryptedDataKey = encryptedSecretKey
-	if len(param.PlainDataKey) == 0 {
-		return "", EmptyPlainDataKeyError
-	}
-	if len(param.EncryptedDataKey) == 0 {
-		return "", EmptyEncryptedDataKeyError
-	}
-	return plainSecretKey, nil
+	return k.kmsPlugin.generateSecretKey(param, kmsAes128KeySpec)
 }
 
 func (k *KmsAes128Plugin) EncryptSecretKey(param *HandlerParam) (string, error) {
@@ -221,32 +227,99 @@ func (k *KmsAes256Plugin) AlgorithmName() string {
 }
 
 func (k *KmsAes256Plugin) GenerateSecretKey(param *HandlerParam) (string, error) {
-	var keyId string
-	var err error
-	if keyId, err = k.keyIdParamCheck(param.KeyId); err != nil {
-		return "", err
+	return k.kmsPlugin.generateSecretKey(param, kmsAes256KeySpec)
+}
+
+func (k *KmsAes256Plugin) EncryptSecretKey(param *HandlerParam) (string, error) {
+	return k.kmsPlugin.EncryptSecretKey(param)
+}
+
+func (k *KmsAes256Plugin) DecryptSecretKey(param *HandlerParam) (string, error) {
+	return k.kmsPlugin.DecryptSecretKey(param)
+}
+
+// kmsGcmPlugin encrypts the content with AES-GCM under a kms data key, a random nonce is put before the ciphertext.
+// The dataId and group of the config are authenticated with the content, so it can't be moved to another config.
+type kmsGcmPlugin struct {
+	kmsPlugin
+	keySpec string
+}
+
+func (k *kmsGcmPlugin) Encrypt(param *HandlerParam) error {
+	if err := k.encryptionParamCheck(*param); err != nil {
+		return err
 	}
-	plainSecretKey, encryptedSecretKey, err := k.kmsClient.GenerateDataKey(keyId, kmsAes256KeySpec)
+	dataKey, err := inner_encoding.DecodeBase64(inner_encoding.DecodeString2Utf8Bytes(param.PlainDataKey))
 	if err != nil {
-		return "", err
+		return err
 	}
-	param.PlainDataKey = plainSecretKey
-	param.EncryptedDataKey = encryptedSecretKey
-	if len(param.PlainDataKey) == 0 {
-		return "", EmptyPlainDataKeyError
+	sealed, err := aesGcmSeal(dataKey, inner_encoding.DecodeString2Utf8Bytes(param.Content),
+		configAdditionalData(param.DataId, param.Group))
+	if err != nil {
+		return err
 	}
-	if len(param.EncryptedDataKey) == 0 {
-		return "", EmptyEncryptedDataKeyError
+	contentBase64Encoded, err := inner_encoding.EncodeBase64(sealed)
+	if err != nil {
+		return err
 	}
-	return plainSecretKey, nil
+	param.Content = inner_encoding.EncodeUtf8Bytes2String(contentBase64Encoded)
+	return nil
 }
 
-func (k *KmsAes256Plugin) EncryptSecretKey(param *HandlerParam) (string, error) {
-	return k.kmsPlugin.EncryptSecretKey(param)
+func (k *kmsGcmPlugin) Decrypt(param *HandlerParam) error {
+	if err := k.decryptionParamCheck(*param); err != nil {
+		return err
+	}
+	dataKey, err := inner_encoding.DecodeBase64(inner_encoding.DecodeString2Utf8Bytes(param.PlainDataKey))
+	if err != nil {
+		return err
+	}
+	sealed, err := inner_encoding.DecodeBase64(inner_encoding.DecodeString2Utf8Bytes(param.Content))
+	if err != nil {
+		return err
+	}
+	content, err := aesGcmOpen(dataKey, sealed, configAdditionalData(param.DataId, param.Group))
+	if err != nil {
+		return fmt.Errorf("decrypt content of dataId %s failed: %v", param.DataId, err)
+	}
+	param.Content = inner_encoding.EncodeUtf8Bytes2String(content)
+	return nil
 }
 
-func (k *KmsAes256Plugin) DecryptSecretKey(param *HandlerParam) (string, error) {
-	return k.kmsPlugin.DecryptSecretKey(param)
+func (k *kmsGcmPlugin) GenerateSecretKey(param *HandlerParam) (string, error) {
+	return k.kmsPlugin.generateSecretKey(param, k.keySpec)
+}
+
+// configAdditionalData is the dataId and group a config is bound to, the group defaults the way publishing does.
+func configAdditionalData(dataId, group string) []byte {
+	if len(group) == 0 {
+		group = constant.DEFAULT_GROUP
+	}
+	return []byte(dataId + "\x00" + group)
+}
+
+type KmsAes128GcmPlugin struct {
+	kmsGcmPlugin
+}
+
+func NewKmsAes128GcmPlugin(kmsClient KmsClient, dataKeyCache *DataKeyCache) *KmsAes128GcmPlugin {
+	return &KmsAes128GcmPlugin{kmsGcmPlugin{kmsPlugin{kmsClient: kmsClient, dataKeyCache: dataKeyCache}, kmsAes128KeySpec}}
+}
+
+func (k *KmsAes128GcmPlugin) AlgorithmName() string {
+	return KmsAes128GcmAlgorithmName
+}
+
+type KmsAes256GcmPlugin struct {
+	kmsGcmPlugin
+}
+
+func NewKmsAes256GcmPlugin(kmsClient KmsClient, dataKeyCache *DataKeyCache) *KmsAes256GcmPlugin {
+	return &KmsAes256GcmPlugin{kmsGcmPlugin{kmsPlugin{kmsClient: kmsClient, dataKeyCache: dataKeyCache}, kmsAes256KeySpec}}
+}
+
+func (k *KmsAes256GcmPlugin) AlgorithmName() string {
+	return KmsAes256GcmAlgorithmName
 }
 
 type KmsBasePlugin struct {
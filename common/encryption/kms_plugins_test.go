@@ -0,0 +1,150 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+// dataKeyKmsClient generates real data keys, wrapping them by prefixing "wrapped-"
+type dataKeyKmsClient struct {
+	fakeKmsClient
+	keySpecs []string
+}
+
+func (f *dataKeyKmsClient) Decrypt(cipherContent string) (string, error) {
+	return strings.TrimPrefix(cipherContent, "wrapped-"), nil
+}
+
+func (f *dataKeyKmsClient) GenerateDataKey(keyId, keySpec string) (string, string, error) {
+	f.keySpecs = append(f.keySpecs, keySpec)
+	key := make([]byte, 16)
+	if keySpec == kmsAes256KeySpec {
+		key = make([]byte, 32)
+	}
+	if _, err := rand.Read(key); err != nil {
+		return "", "", err
+	}
+	plainKey := base64.StdEncoding.EncodeToString(key)
+	return plainKey, "wrapped-" + plainKey, nil
+}
+
+func newTestKmsHandler(kmsClient KmsClient) Handler {
+	handler := NewKmsHandler()
+	_ = handler.RegisterPlugin(&KmsAes128Plugin{kmsPlugin{kmsClient: kmsClient}})
+	_ = handler.RegisterPlugin(&KmsAes256Plugin{kmsPlugin{kmsClient: kmsClient}})
+	_ = handler.RegisterPlugin(NewKmsAes128GcmPlugin(kmsClient, nil))
+	_ = handler.RegisterPlugin(NewKmsAes256GcmPlugin(kmsClient, nil))
+	return handler
+}
+
+func TestKmsGcmPlugin_RoundTrip(t *testing.T) {
+	for _, c := range []struct {
+		dataId  string
+		keySpec string
+	}{
+		{"cipher-kms-aes-128-gcm-app.yaml", kmsAes128KeySpec},
+		{"cipher-kms-aes-256-gcm-app.yaml", kmsAes256KeySpec},
+	} {
+		t.Run(c.dataId, func(t *testing.T) {
+			kmsClient := &dataKeyKmsClient{}
+			handler := newTestKmsHandler(kmsClient)
+			param := &HandlerParam{DataId: c.dataId, Group: "APP", Content: "password: secret", KeyId: "key"}
+			assert.Nil(t, handler.EncryptionHandler(param))
+			assert.Equal(t, []string{c.keySpec}, kmsClient.keySpecs)
+			assert.NotContains(t, param.Content, "secret")
+
+			// the nonce is random, the same content encrypts differently
+			again := &HandlerParam{DataId: c.dataId, Group: "APP", Content: "password: secret", KeyId: "key",
+				PlainDataKey: param.PlainDataKey}
+			plugin, err := handler.(*KmsHandler).getPluginByDataIdPrefix(c.dataId)
+			assert.Nil(t, err)
+			assert.Nil(t, plugin.Encrypt(again))
+			assert.NotEqual(t, param.Content, again.Content)
+
+			decrypted := &HandlerParam{DataId: c.dataId, Group: "APP", Content: param.Content,
+				EncryptedDataKey: param.EncryptedDataKey}
+			assert.Nil(t, handler.DecryptionHandler(decrypted))
+			assert.Equal(t, "password: secret", decrypted.Content)
+		})
+	}
+}
+
+func TestKmsGcmPlugin_BoundToConfig(t *testing.T) {
+	handler := newTestKmsHandler(&dataKeyKmsClient{})
+	dataId := "cipher-kms-aes-256-gcm-app.yaml"
+	param := &HandlerParam{DataId: dataId, Content: "password: secret", KeyId: "key"}
+	assert.Nil(t, handler.EncryptionHandler(param))
+
+	decrypt := func(dataId, group, content string) (string, error) {
+		decrypted := &HandlerParam{DataId: dataId, Group: group, Content: content, EncryptedDataKey: param.EncryptedDataKey}
+		err := handler.DecryptionHandler(decrypted)
+		return decrypted.Content, err
+	}
+	// an empty group is the default one
+	content, err := decrypt(dataId, constant.DEFAULT_GROUP, param.Content)
+	assert.Nil(t, err)
+	assert.Equal(t, "password: secret", content)
+
+	_, err = decrypt("cipher-kms-aes-256-gcm-other.yaml", constant.DEFAULT_GROUP, param.Content)
+	assert.NotNil(t, err)
+	_, err = decrypt(dataId, "APP", param.Content)
+	assert.NotNil(t, err)
+
+	sealed, _ := base64.StdEncoding.DecodeString(param.Content)
+	sealed[len(sealed)-1] ^= 1
+	_, err = decrypt(dataId, constant.DEFAULT_GROUP, base64.StdEncoding.EncodeToString(sealed))
+	assert.NotNil(t, err)
+}
+
+func TestKmsGcmPlugin_EcbStillDecrypts(t *testing.T) {
+	handler := newTestKmsHandler(&dataKeyKmsClient{})
+	for _, dataId := range []string{"cipher-kms-aes-128-app.yaml", "cipher-kms-aes-256-app.yaml"} {
+		param := &HandlerParam{DataId: dataId, Group: "APP", Content: "password: secret", KeyId: "key"}
+		assert.Nil(t, handler.EncryptionHandler(param))
+		plainKey, _ := base64.StdEncoding.DecodeString(param.PlainDataKey)
+		sealed, _ := base64.StdEncoding.DecodeString(param.Content)
+		content, err := AesEcbPkcs5PaddingDecrypt(sealed, plainKey)
+		assert.Nil(t, err)
+		assert.Equal(t, "password: secret", string(content))
+
+		// the group doesn't matter for ecb
+		decrypted := &HandlerParam{DataId: dataId, Group: "OTHER", Content: param.Content, EncryptedDataKey: param.EncryptedDataKey}
+		assert.Nil(t, handler.DecryptionHandler(decrypted))
+		assert.Equal(t, "password: secret", decrypted.Content)
+	}
+}
+
+func TestKmsHandler_GcmPluginSelected(t *testing.T) {
+	handler := newTestKmsHandler(&dataKeyKmsClient{}).(*KmsHandler)
+	for dataId, algorithmName := range map[string]string{
+		"cipher-kms-aes-128-app.yaml":     KmsAes128AlgorithmName,
+		"cipher-kms-aes-256-app.yaml":     KmsAes256AlgorithmName,
+		"cipher-kms-aes-128-gcm-app.yaml": KmsAes128GcmAlgorithmName,
+		"cipher-kms-aes-256-gcm-app.yaml": KmsAes256GcmAlgorithmName,
+	} {
+		plugin, err := handler.getPluginByDataIdPrefix(dataId)
+		assert.Nil(t, err)
+		assert.Equal(t, algorithmName, plugin.AlgorithmName())
+	}
+}
@@ -0,0 +1,298 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/pkg/errors"
+)
+
+var localKeyIdPattern = regexp.MustCompile(`^[A-Za-z0-9._/-]+$`)
+
+// LocalKeyring holds the AES-256 master keys of the local plugin by their id, the primary key encrypts the data keys
+// of the configs being published, all the keys decrypt the data keys of the configs being read.
+type LocalKeyring struct {
+	primaryKeyId string
+	keys         map[string][]byte
+}
+
+type localKeyringFile struct {
+	PrimaryKeyId string            `json:"primaryKeyId"`
+	Keys         map[string]string `json:"keys"`
+}
+
+// NewLocalKeyring creates a keyring of keys, which must be 32 bytes long, primaryKeyId must be one of them.
+func NewLocalKeyring(primaryKeyId string, keys map[string][]byte) (*LocalKeyring, error) {
+	if len(keys) == 0 {
+		return nil, EmptyLocalKeyringError
+	}
+	keyring := &LocalKeyring{primaryKeyId: primaryKeyId, keys: make(map[string][]byte, len(keys))}
+	for keyId, key := range keys {
+		if !localKeyIdPattern.MatchString(keyId) {
+			return nil, errors.Errorf("invalid local master key id [%s]", keyId)
+		}
+		if len(key) != localMasterKeySize {
+			return nil, errors.Errorf("local master key [%s] must be %d bytes, got %d", keyId, localMasterKeySize, len(key))
+		}
+		keyring.keys[keyId] = append([]byte(nil), key...)
+	}
+	if _, ok := keyring.keys[primaryKeyId]; !ok {
+		return nil, errors.Errorf("primary key [%s] is not in the local keyring", primaryKeyId)
+	}
+	return keyring, nil
+}
+
+// ParseLocalKeyring parses a keyring of the form
+//
+//	{"primaryKeyId": "key-2", "keys": {"key-1": "<base64 key>", "key-2": "<base64 key>"}}
+//
+// the primary key may be left out when the keyring holds a single key.
+func ParseLocalKeyring(data []byte) (*LocalKeyring, error) {
+	var file localKeyringFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, errors.Wrap(err, "parse local keyring")
+	}
+	keys := make(map[string][]byte, len(file.Keys))
+	for keyId, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, errors.Wrapf(err, "decode local master key [%s]", keyId)
+		}
+		keys[keyId] = key
+		if len(file.PrimaryKeyId) == 0 && len(file.Keys) == 1 {
+			file.PrimaryKeyId = keyId
+		}
+	}
+	return NewLocalKeyring(file.PrimaryKeyId, keys)
+}
+
+// LoadLocalKeyring loads the keyring from the file or the environment variable of config, the primary key of the
+// keyring is replaced by the one of config if set.
+func LoadLocalKeyring(config *constant.LocalKMSConfig) (*LocalKeyring, error) {
+	var data []byte
+	if len(config.KeyringFile) > 0 {
+		var err error
+		if data, err = os.ReadFile(config.KeyringFile); err != nil {
+			return nil, errors.Wrap(err, "read local keyring")
+		}
+	} else {
+		env := config.KeyringEnv
+		if len(env) == 0 {
+			env = DefaultLocalKeyringEnv
+		}
+		data = []byte(os.Getenv(env))
+		if len(data) == 0 {
+			return nil, errors.Wrapf(EmptyLocalKeyringError, "environment variable %s", env)
+		}
+	}
+	keyring, err := ParseLocalKeyring(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(config.PrimaryKeyId) > 0 {
+		if _, ok := keyring.keys[config.PrimaryKeyId]; !ok {
+			return nil, errors.Errorf("primary key [%s] is not in the local keyring", config.PrimaryKeyId)
+		}
+		keyring.primaryKeyId = config.PrimaryKeyId
+	}
+	return keyring, nil
+}
+
+// GenerateLocalMasterKey returns a new random master key, base64 encoded as in the keyring.
+func GenerateLocalMasterKey() (string, error) {
+	key := make([]byte, localMasterKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func (k *LocalKeyring) PrimaryKeyId() string {
+	return k.primaryKeyId
+}
+
+func (k *LocalKeyring) key(keyId string) ([]byte, error) {
+	key, ok := k.keys[keyId]
+	if !ok {
+		return nil, errors.Errorf("local master key [%s] not found", keyId)
+	}
+	return key, nil
+}
+
+// LocalAesGcmPlugin encrypts the content of the configs with AES-256-GCM under a random data key for each publish,
+// the data key is itself encrypted with a master key of the keyring. The id of that master key is kept in the
+// EncryptedDataKey, as <keyId>:<base64 encrypted data key>, so the configs can be read after the primary key changed.
+type LocalAesGcmPlugin struct {
+	keyring *LocalKeyring
+}
+
+func NewLocalAesGcmPlugin(keyring *LocalKeyring) *LocalAesGcmPlugin {
+	return &LocalAesGcmPlugin{keyring: keyring}
+}
+
+func (l *LocalAesGcmPlugin) Encrypt(param *HandlerParam) error {
+	if len(param.Content) == 0 {
+		return EmptyContentError
+	}
+	dataKey, err := decodeLocalDataKey(param.PlainDataKey)
+	if err != nil {
+		return err
+	}
+	sealed, err := aesGcmSeal(dataKey, []byte(param.Content), nil)
+	if err != nil {
+		return err
+	}
+	param.Content = base64.StdEncoding.EncodeToString(sealed)
+	return nil
+}
+
+func (l *LocalAesGcmPlugin) Decrypt(param *HandlerParam) error {
+	dataKey, err := decodeLocalDataKey(param.PlainDataKey)
+	if err != nil {
+		return err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(param.Content)
+	if err != nil {
+		return errors.Wrap(err, "decode encrypted content")
+	}
+	content, err := aesGcmOpen(dataKey, sealed, nil)
+	if err != nil {
+		return errors.Wrap(err, "decrypt content")
+	}
+	param.Content = string(content)
+	return nil
+}
+
+func (l *LocalAesGcmPlugin) AlgorithmName() string {
+	return LocalAes256GcmAlgorithmName
+}
+
+func (l *LocalAesGcmPlugin) GenerateSecretKey(param *HandlerParam) (string, error) {
+	dataKey := make([]byte, localMasterKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+	param.PlainDataKey = base64.StdEncoding.EncodeToString(dataKey)
+	if _, err := l.EncryptSecretKey(param); err != nil {
+		return "", err
+	}
+	return param.PlainDataKey, nil
+}
+
+func (l *LocalAesGcmPlugin) EncryptSecretKey(param *HandlerParam) (string, error) {
+	dataKey, err := decodeLocalDataKey(param.PlainDataKey)
+	if err != nil {
+		return "", err
+	}
+	keyId := strings.TrimSpace(param.KeyId)
+	if len(keyId) == 0 {
+		keyId = l.keyring.primaryKeyId
+	}
+	masterKey, err := l.keyring.key(keyId)
+	if err != nil {
+		return "", err
+	}
+	// the key id is authenticated with the data key, so the two can't be swapped
+	sealed, err := aesGcmSeal(masterKey, dataKey, []byte(keyId))
+	if err != nil {
+		return "", err
+	}
+	param.EncryptedDataKey = keyId + localKeyIdSeparator + base64.StdEncoding.EncodeToString(sealed)
+	return param.EncryptedDataKey, nil
+}
+
+func (l *LocalAesGcmPlugin) DecryptSecretKey(param *HandlerParam) (string, error) {
+	if len(param.EncryptedDataKey) == 0 {
+		return "", EmptyEncryptedDataKeyError
+	}
+	keyId, encoded, found := strings.Cut(param.EncryptedDataKey, localKeyIdSeparator)
+	if !found {
+		return "", errors.New("encrypted data key has no local master key id")
+	}
+	masterKey, err := l.keyring.key(keyId)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.Wrap(err, "decode encrypted data key")
+	}
+	dataKey, err := aesGcmOpen(masterKey, sealed, []byte(keyId))
+	if err != nil {
+		return "", errors.Wrapf(err, "decrypt data key with local master key [%s]", keyId)
+	}
+	param.PlainDataKey = base64.StdEncoding.EncodeToString(dataKey)
+	return param.PlainDataKey, nil
+}
+
+func decodeLocalDataKey(plainDataKey string) ([]byte, error) {
+	if len(plainDataKey) == 0 {
+		return nil, EmptyPlainDataKeyError
+	}
+	dataKey, err := base64.StdEncoding.DecodeString(plainDataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode data key")
+	}
+	if len(dataKey) != localMasterKeySize {
+		return nil, fmt.Errorf("data key must be %d bytes, got %d", localMasterKeySize, len(dataKey))
+	}
+	return dataKey, nil
+}
+
+// aesGcmSeal returns the random nonce followed by the ciphertext and its tag.
+func aesGcmSeal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newAesGcm(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func aesGcmOpen(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newAesGcm(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize()+aead.Overhead() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newAesGcm(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
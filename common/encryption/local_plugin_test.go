@@ -0,0 +1,159 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLocalKeyring(t *testing.T, primaryKeyId string, keyIds ...string) (*LocalKeyring, string) {
+	keys := make(map[string]string)
+	for _, keyId := range keyIds {
+		key, err := GenerateLocalMasterKey()
+		assert.Nil(t, err)
+		keys[keyId] = key
+	}
+	var entries []string
+	for keyId, key := range keys {
+		entries = append(entries, `"`+keyId+`": "`+key+`"`)
+	}
+	data := `{"primaryKeyId": "` + primaryKeyId + `", "keys": {` + strings.Join(entries, ", ") + `}}`
+	keyring, err := ParseLocalKeyring([]byte(data))
+	assert.Nil(t, err)
+	return keyring, data
+}
+
+func newTestLocalHandler(keyring *LocalKeyring) Handler {
+	handler := NewKmsHandler()
+	_ = handler.RegisterPlugin(NewLocalAesGcmPlugin(keyring))
+	return handler
+}
+
+func TestLocalAesGcmPlugin_RoundTrip(t *testing.T) {
+	keyring, _ := newTestLocalKeyring(t, "key-1", "key-1")
+	handler := newTestLocalHandler(keyring)
+	param := &HandlerParam{DataId: "cipher-local-aes-256-app.yaml", Content: "password: secret"}
+	assert.Nil(t, handler.EncryptionHandler(param))
+	assert.NotContains(t, param.Content, "secret")
+	assert.True(t, strings.HasPrefix(param.EncryptedDataKey, "key-1:"))
+
+	// every publish uses its own data key
+	other := &HandlerParam{DataId: "cipher-local-aes-256-app.yaml", Content: "password: secret"}
+	assert.Nil(t, handler.EncryptionHandler(other))
+	assert.NotEqual(t, param.EncryptedDataKey, other.EncryptedDataKey)
+	assert.NotEqual(t, param.Content, other.Content)
+
+	read := &HandlerParam{DataId: param.DataId, Content: param.Content, EncryptedDataKey: param.EncryptedDataKey}
+	assert.Nil(t, handler.DecryptionHandler(read))
+	assert.Equal(t, "password: secret", read.Content)
+
+	// the content doesn't decrypt with the data key of another publish
+	read = &HandlerParam{DataId: param.DataId, Content: param.Content, EncryptedDataKey: other.EncryptedDataKey}
+	assert.NotNil(t, handler.DecryptionHandler(read))
+}
+
+func TestLocalAesGcmPlugin_KeyIds(t *testing.T) {
+	keyring, _ := newTestLocalKeyring(t, "key-1", "key-1", "key-2")
+	handler := newTestLocalHandler(keyring)
+	param := &HandlerParam{DataId: "cipher-local-aes-256-app", Content: "a=b"}
+	assert.Nil(t, handler.EncryptionHandler(param))
+	assert.True(t, strings.HasPrefix(param.EncryptedDataKey, "key-1:"))
+
+	// the key id of the param selects the master key
+	selected := &HandlerParam{DataId: "cipher-local-aes-256-app", Content: "a=b", KeyId: "key-2"}
+	assert.Nil(t, handler.EncryptionHandler(selected))
+	assert.True(t, strings.HasPrefix(selected.EncryptedDataKey, "key-2:"))
+	assert.NotNil(t, handler.EncryptionHandler(&HandlerParam{DataId: "cipher-local-aes-256-app", Content: "a=b", KeyId: "key-3"}))
+
+	// configs encrypted with the former primary key are read after it changed
+	keyring.primaryKeyId = "key-2"
+	read := &HandlerParam{DataId: param.DataId, Content: param.Content, EncryptedDataKey: param.EncryptedDataKey}
+	assert.Nil(t, handler.DecryptionHandler(read))
+	assert.Equal(t, "a=b", read.Content)
+
+	// the key id is authenticated with the data key
+	_, sealed, _ := strings.Cut(param.EncryptedDataKey, ":")
+	read = &HandlerParam{DataId: param.DataId, Content: param.Content, EncryptedDataKey: "key-2:" + sealed}
+	assert.NotNil(t, handler.DecryptionHandler(read))
+	read = &HandlerParam{DataId: param.DataId, Content: param.Content, EncryptedDataKey: sealed}
+	assert.NotNil(t, handler.DecryptionHandler(read))
+}
+
+func TestParseLocalKeyring(t *testing.T) {
+	key, _ := GenerateLocalMasterKey()
+	keyring, err := ParseLocalKeyring([]byte(`{"keys": {"only": "` + key + `"}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "only", keyring.PrimaryKeyId())
+
+	shortKey := base64.StdEncoding.EncodeToString([]byte("short"))
+	for _, data := range []string{
+		`{`,
+		`{"keys": {}}`,
+		`{"keys": {"a": "` + key + `", "b": "` + key + `"}}`,
+		`{"primaryKeyId": "c", "keys": {"a": "` + key + `"}}`,
+		`{"primaryKeyId": "a", "keys": {"a": "` + shortKey + `"}}`,
+		`{"primaryKeyId": "a", "keys": {"a": "not base64"}}`,
+		`{"primaryKeyId": "a:b", "keys": {"a:b": "` + key + `"}}`,
+	} {
+		_, err = ParseLocalKeyring([]byte(data))
+		assert.NotNil(t, err, data)
+	}
+}
+
+func TestLoadLocalKeyring(t *testing.T) {
+	_, data := newTestLocalKeyring(t, "key-1", "key-1", "key-2")
+	file := filepath.Join(t.TempDir(), "keyring.json")
+	assert.Nil(t, os.WriteFile(file, []byte(data), 0600))
+	keyring, err := LoadLocalKeyring(&constant.LocalKMSConfig{KeyringFile: file})
+	assert.Nil(t, err)
+	assert.Equal(t, "key-1", keyring.PrimaryKeyId())
+	keyring, err = LoadLocalKeyring(&constant.LocalKMSConfig{KeyringFile: file, PrimaryKeyId: "key-2"})
+	assert.Nil(t, err)
+	assert.Equal(t, "key-2", keyring.PrimaryKeyId())
+	_, err = LoadLocalKeyring(&constant.LocalKMSConfig{KeyringFile: file, PrimaryKeyId: "key-3"})
+	assert.NotNil(t, err)
+	_, err = LoadLocalKeyring(&constant.LocalKMSConfig{KeyringFile: file + ".missing"})
+	assert.NotNil(t, err)
+
+	t.Setenv(DefaultLocalKeyringEnv, data)
+	keyring, err = LoadLocalKeyring(&constant.LocalKMSConfig{})
+	assert.Nil(t, err)
+	assert.Equal(t, "key-1", keyring.PrimaryKeyId())
+	_, err = LoadLocalKeyring(&constant.LocalKMSConfig{KeyringEnv: "NACOS_TEST_MISSING_KEYRING"})
+	assert.ErrorIs(t, err, EmptyLocalKeyringError)
+}
+
+func TestRegisterConfigEncryptionKmsPlugins_Local(t *testing.T) {
+	_, data := newTestLocalKeyring(t, "key-1", "key-1")
+	t.Setenv(DefaultLocalKeyringEnv, data)
+	handler := newKmsHandler()
+	RegisterConfigEncryptionKmsPlugins(handler, constant.ClientConfig{LocalKMSConfig: &constant.LocalKMSConfig{}})
+	// only the local plugin is registered without OpenKMS
+	assert.Len(t, handler.encryptionPlugins, 1)
+	plugin, err := handler.getPluginByDataIdPrefix("cipher-local-aes-256-app")
+	assert.Nil(t, err)
+	assert.Equal(t, LocalAes256GcmAlgorithmName, plugin.AlgorithmName())
+	_, err = handler.getPluginByDataIdPrefix("cipher-kms-aes-256-app")
+	assert.NotNil(t, err)
+}
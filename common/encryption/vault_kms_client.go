@@ -0,0 +1,283 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/pkg/errors"
+)
+
+// VaultKmsClient is a KmsClient backed by the transit secrets engine of hashicorp vault, the keyId is the name of
+// a transit key. The ciphertexts it returns are prefixed with the key name, vault needing it to decrypt.
+type VaultKmsClient struct {
+	httpClient *http.Client
+	address    string
+	namespace  string
+	transit    string
+	kmsVersion constant.KMSVersion
+	login      func() (string, time.Duration, error)
+	now        func() time.Time
+
+	mux         sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+type vaultResponse struct {
+	Data struct {
+		Plaintext  string `json:"plaintext"`
+		Ciphertext string `json:"ciphertext"`
+	} `json:"data"`
+	Auth *struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int64  `json:"lease_duration"`
+	} `json:"auth"`
+	Errors []string `json:"errors"`
+}
+
+type vaultStatusError struct {
+	path       string
+	statusCode int
+	errors     []string
+}
+
+func (e *vaultStatusError) Error() string {
+	return fmt.Sprintf("vault request %s failed with status %d: %s", e.path, e.statusCode, strings.Join(e.errors, "; "))
+}
+
+func NewVaultKmsClient(config *constant.VaultKMSConfig) (*VaultKmsClient, error) {
+	if config == nil || len(config.Address) == 0 {
+		return nil, EmptyAddressVaultClientInitError
+	}
+	timeoutMs := config.TimeoutMs
+	if timeoutMs == 0 {
+		timeoutMs = defaultVaultTimeoutMs
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(config.CaContent) != 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(config.CaContent)) {
+			return nil, errors.New("init vault client failed with invalid ca content")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	kmsClient := &VaultKmsClient{
+		httpClient: &http.Client{Transport: transport, Timeout: time.Duration(timeoutMs) * time.Millisecond},
+		address:    strings.TrimRight(config.Address, "/"),
+		namespace:  config.Namespace,
+		transit:    vaultMount(config.TransitMount, defaultVaultTransitMount),
+		now:        time.Now,
+	}
+	switch {
+	case len(config.Token) != 0:
+		kmsClient.token = config.Token
+	case len(config.AppRoleId) != 0:
+		mount := vaultMount(config.AppRoleMount, defaultVaultAppRoleMount)
+		kmsClient.login = func() (string, time.Duration, error) {
+			return kmsClient.loginWith(mount, map[string]string{"role_id": config.AppRoleId, "secret_id": config.AppRoleSecretId})
+		}
+	case len(config.KubernetesRole) != 0:
+		mount := vaultMount(config.KubernetesMount, defaultVaultKubernetesMount)
+		tokenFile := config.KubernetesTokenFile
+		if len(tokenFile) == 0 {
+			tokenFile = defaultVaultKubernetesTokenFile
+		}
+		kmsClient.login = func() (string, time.Duration, error) {
+			// the service account token is projected and rotated by kubernetes, so it's read for every login
+			jwt, err := os.ReadFile(tokenFile)
+			if err != nil {
+				return "", 0, errors.Wrap(err, "read kubernetes service account token failed")
+			}
+			return kmsClient.loginWith(mount, map[string]string{"role": config.KubernetesRole, "jwt": strings.TrimSpace(string(jwt))})
+		}
+	default:
+		return nil, EmptyAuthVaultClientInitError
+	}
+	kmsClient.setKmsVersion(constant.KMSVault)
+	logger.Debugf("init vault kms client with address:[%s], transit mount:[%s]", kmsClient.address, kmsClient.transit)
+	return kmsClient, nil
+}
+
+func vaultMount(mount, defaultMount string) string {
+	mount = strings.Trim(mount, "/")
+	if len(mount) == 0 {
+		return defaultMount
+	}
+	return mount
+}
+
+func (kmsClient *VaultKmsClient) GetKmsVersion() constant.KMSVersion {
+	return kmsClient.kmsVersion
+}
+
+func (kmsClient *VaultKmsClient) setKmsVersion(kmsVersion constant.KMSVersion) {
+	logger.Debug("successfully set kms client version to " + kmsVersion)
+	kmsClient.kmsVersion = kmsVersion
+}
+
+// GenerateDataKey returns the data key base64 encoded, like the other kms clients do, with its ciphertext
+func (kmsClient *VaultKmsClient) GenerateDataKey(keyId, keySpec string) (string, string, error) {
+	bits := 256
+	if keySpec == kmsAes128KeySpec {
+		bits = 128
+	}
+	response, err := kmsClient.transitRequest("datakey/plaintext/"+url.PathEscape(keyId), map[string]interface{}{"bits": bits})
+	if err != nil {
+		return "", "", err
+	}
+	return response.Data.Plaintext, keyId + vaultKeyNameSeparator + vaultDataKeyMarker + response.Data.Ciphertext, nil
+}
+
+func (kmsClient *VaultKmsClient) Encrypt(content, keyId string) (string, error) {
+	response, err := kmsClient.transitRequest("encrypt/"+url.PathEscape(keyId),
+		map[string]interface{}{"plaintext": base64.StdEncoding.EncodeToString([]byte(content))})
+	if err != nil {
+		return "", err
+	}
+	return keyId + vaultKeyNameSeparator + response.Data.Ciphertext, nil
+}
+
+func (kmsClient *VaultKmsClient) Decrypt(cipherContent string) (string, error) {
+	keyId, ciphertext, found := strings.Cut(cipherContent, vaultKeyNameSeparator)
+	if !found || len(keyId) == 0 {
+		return "", errors.New("vault ciphertext doesn't name its transit key")
+	}
+	dataKey := strings.HasPrefix(ciphertext, vaultDataKeyMarker)
+	ciphertext = strings.TrimPrefix(ciphertext, vaultDataKeyMarker)
+	response, err := kmsClient.transitRequest("decrypt/"+url.PathEscape(keyId), map[string]interface{}{"ciphertext": ciphertext})
+	if err != nil {
+		return "", err
+	}
+	if dataKey {
+		return response.Data.Plaintext, nil
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(response.Data.Plaintext)
+	if err != nil {
+		return "", errors.Wrap(err, "decode vault plaintext failed")
+	}
+	return string(plaintext), nil
+}
+
+// transitRequest calls the transit engine, logging in again once if vault rejects a token got by logging in
+func (kmsClient *VaultKmsClient) transitRequest(path string, body interface{}) (*vaultResponse, error) {
+	token, err := kmsClient.getToken()
+	if err != nil {
+		return nil, err
+	}
+	response, err := kmsClient.request(kmsClient.transit+"/"+path, token, body)
+	var statusErr *vaultStatusError
+	if kmsClient.login != nil && errors.As(err, &statusErr) && statusErr.statusCode == http.StatusForbidden {
+		kmsClient.resetToken(token)
+		if token, err = kmsClient.getToken(); err != nil {
+			return nil, err
+		}
+		response, err = kmsClient.request(kmsClient.transit+"/"+path, token, body)
+	}
+	return response, err
+}
+
+func (kmsClient *VaultKmsClient) getToken() (string, error) {
+	kmsClient.mux.Lock()
+	defer kmsClient.mux.Unlock()
+	if kmsClient.login == nil || (len(kmsClient.token) != 0 &&
+		(kmsClient.tokenExpiry.IsZero() || kmsClient.now().Before(kmsClient.tokenExpiry))) {
+		return kmsClient.token, nil
+	}
+	token, leaseDuration, err := kmsClient.login()
+	if err != nil {
+		return "", err
+	}
+	kmsClient.token = token
+	kmsClient.tokenExpiry = time.Time{}
+	if leaseDuration > 0 {
+		// renew well before the lease ends, so a request doesn't start with a token about to expire
+		kmsClient.tokenExpiry = kmsClient.now().Add(leaseDuration * 4 / 5)
+	}
+	return token, nil
+}
+
+func (kmsClient *VaultKmsClient) resetToken(token string) {
+	kmsClient.mux.Lock()
+	defer kmsClient.mux.Unlock()
+	if kmsClient.token == token {
+		kmsClient.token = ""
+	}
+}
+
+func (kmsClient *VaultKmsClient) loginWith(mount string, body map[string]string) (string, time.Duration, error) {
+	response, err := kmsClient.request("auth/"+mount+"/login", "", body)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "vault login failed")
+	}
+	if response.Auth == nil || len(response.Auth.ClientToken) == 0 {
+		return "", 0, errors.New("vault login returned no token")
+	}
+	logger.Debugf("successfully login vault with auth mount [%s]", mount)
+	return response.Auth.ClientToken, time.Duration(response.Auth.LeaseDuration) * time.Second, nil
+}
+
+func (kmsClient *VaultKmsClient) request(path, token string, body interface{}) (*vaultResponse, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest(http.MethodPost, kmsClient.address+"/v1/"+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if len(token) != 0 {
+		request.Header.Set("X-Vault-Token", token)
+	}
+	if len(kmsClient.namespace) != 0 {
+		request.Header.Set("X-Vault-Namespace", kmsClient.namespace)
+	}
+	response, err := kmsClient.httpClient.Do(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "vault request %s failed", path)
+	}
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read vault response of %s failed", path)
+	}
+	result := &vaultResponse{}
+	if len(content) != 0 {
+		if err := json.Unmarshal(content, result); err != nil && response.StatusCode == http.StatusOK {
+			return nil, errors.Wrapf(err, "parse vault response of %s failed", path)
+		}
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, &vaultStatusError{path: path, statusCode: response.StatusCode, errors: result.Errors}
+	}
+	return result, nil
+}
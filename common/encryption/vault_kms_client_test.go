@@ -0,0 +1,233 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeVaultTransit stands in for the transit engine and the approle and kubernetes auth of vault
+type fakeVaultTransit struct {
+	mux      sync.Mutex
+	tokens   map[string]bool
+	logins   []map[string]string
+	requests []*http.Request
+}
+
+func newFakeVaultTransit(t *testing.T) (*fakeVaultTransit, *httptest.Server) {
+	fake := &fakeVaultTransit{tokens: map[string]bool{"root": true}}
+	server := httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeVaultTransit) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.requests = append(f.requests, r)
+	body := map[string]interface{}{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	reply := func(status int, data map[string]interface{}) {
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(data)
+	}
+	if strings.HasPrefix(r.URL.Path, "/v1/auth/") {
+		login := map[string]string{"path": r.URL.Path}
+		for k, v := range body {
+			login[k] = v.(string)
+		}
+		f.logins = append(f.logins, login)
+		token := "token-" + string(rune('a'+len(f.logins)))
+		f.tokens[token] = true
+		reply(http.StatusOK, map[string]interface{}{"auth": map[string]interface{}{"client_token": token, "lease_duration": 60}})
+		return
+	}
+	if !f.tokens[r.Header.Get("X-Vault-Token")] {
+		reply(http.StatusForbidden, map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/transit/"), "/")
+	keyName := parts[len(parts)-1]
+	if keyName == "missing" {
+		reply(http.StatusBadRequest, map[string]interface{}{"errors": []string{"encryption key not found"}})
+		return
+	}
+	// the fake ciphertext is the plaintext sealed with the key name, just enough to check it's bound to the key
+	seal := func(plaintext string) string {
+		return "vault:v1:" + base64.StdEncoding.EncodeToString([]byte(keyName+"|"+plaintext))
+	}
+	switch parts[0] {
+	case "datakey":
+		key := make([]byte, int(body["bits"].(float64))/8)
+		_, _ = rand.Read(key)
+		plaintext := base64.StdEncoding.EncodeToString(key)
+		reply(http.StatusOK, map[string]interface{}{"data": map[string]string{"plaintext": plaintext, "ciphertext": seal(plaintext)}})
+	case "encrypt":
+		reply(http.StatusOK, map[string]interface{}{"data": map[string]string{"ciphertext": seal(body["plaintext"].(string))}})
+	case "decrypt":
+		sealed, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(body["ciphertext"].(string), "vault:v1:"))
+		name, plaintext, _ := strings.Cut(string(sealed), "|")
+		if name != keyName {
+			reply(http.StatusBadRequest, map[string]interface{}{"errors": []string{"cipher: message authentication failed"}})
+			return
+		}
+		reply(http.StatusOK, map[string]interface{}{"data": map[string]string{"plaintext": plaintext}})
+	default:
+		reply(http.StatusNotFound, map[string]interface{}{"errors": []string{}})
+	}
+}
+
+func (f *fakeVaultTransit) expireTokens() {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.tokens = map[string]bool{"root": true}
+}
+
+func TestVaultKmsClient_Token(t *testing.T) {
+	fake, server := newFakeVaultTransit(t)
+	kmsClient, err := NewVaultKmsClient(&constant.VaultKMSConfig{Address: server.URL, Token: "root", Namespace: "team-a"})
+	assert.Nil(t, err)
+	assert.Equal(t, constant.KMSVault, kmsClient.GetKmsVersion())
+
+	plainKey, encryptedKey, err := kmsClient.GenerateDataKey("nacos", kmsAes256KeySpec)
+	assert.Nil(t, err)
+	key, err := base64.StdEncoding.DecodeString(plainKey)
+	assert.Nil(t, err)
+	assert.Len(t, key, 32)
+	assert.True(t, strings.HasPrefix(encryptedKey, "nacos:"))
+	decryptedKey, err := kmsClient.Decrypt(encryptedKey)
+	assert.Nil(t, err)
+	assert.Equal(t, plainKey, decryptedKey)
+
+	plainKey, _, err = kmsClient.GenerateDataKey("nacos", kmsAes128KeySpec)
+	assert.Nil(t, err)
+	key, _ = base64.StdEncoding.DecodeString(plainKey)
+	assert.Len(t, key, 16)
+
+	ciphertext, err := kmsClient.Encrypt("password: secret", "nacos")
+	assert.Nil(t, err)
+	content, err := kmsClient.Decrypt(ciphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, "password: secret", content)
+
+	assert.Equal(t, "/v1/transit/decrypt/nacos", fake.requests[len(fake.requests)-1].URL.Path)
+	assert.Equal(t, "team-a", fake.requests[0].Header.Get("X-Vault-Namespace"))
+	assert.Empty(t, fake.logins)
+}
+
+func TestVaultKmsClient_Errors(t *testing.T) {
+	_, server := newFakeVaultTransit(t)
+	_, err := NewVaultKmsClient(&constant.VaultKMSConfig{Token: "root"})
+	assert.Equal(t, EmptyAddressVaultClientInitError, err)
+	_, err = NewVaultKmsClient(&constant.VaultKMSConfig{Address: server.URL})
+	assert.Equal(t, EmptyAuthVaultClientInitError, err)
+
+	kmsClient, err := NewVaultKmsClient(&constant.VaultKMSConfig{Address: server.URL, Token: "root"})
+	assert.Nil(t, err)
+	_, err = kmsClient.Encrypt("content", "missing")
+	assert.ErrorContains(t, err, "encryption key not found")
+	_, err = kmsClient.Decrypt("vault:v1:abc")
+	assert.NotNil(t, err)
+	ciphertext, _ := kmsClient.Encrypt("content", "nacos")
+	_, err = kmsClient.Decrypt("other" + strings.TrimPrefix(ciphertext, "nacos"))
+	assert.ErrorContains(t, err, "message authentication failed")
+
+	// a static token isn't renewed
+	denied, err := NewVaultKmsClient(&constant.VaultKMSConfig{Address: server.URL, Token: "revoked"})
+	assert.Nil(t, err)
+	_, err = denied.Encrypt("content", "nacos")
+	assert.ErrorContains(t, err, "403")
+}
+
+func TestVaultKmsClient_AppRole(t *testing.T) {
+	fake, server := newFakeVaultTransit(t)
+	kmsClient, err := NewVaultKmsClient(&constant.VaultKMSConfig{Address: server.URL, AppRoleId: "role", AppRoleSecretId: "secret"})
+	assert.Nil(t, err)
+	now := time.Unix(0, 0)
+	kmsClient.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		_, err = kmsClient.Encrypt("content", "nacos")
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, []map[string]string{{"path": "/v1/auth/approle/login", "role_id": "role", "secret_id": "secret"}}, fake.logins)
+
+	// the token is renewed before its lease ends
+	now = now.Add(50 * time.Second)
+	_, err = kmsClient.Encrypt("content", "nacos")
+	assert.Nil(t, err)
+	assert.Len(t, fake.logins, 2)
+
+	// and when vault no longer accepts it
+	fake.expireTokens()
+	_, err = kmsClient.Encrypt("content", "nacos")
+	assert.Nil(t, err)
+	assert.Len(t, fake.logins, 3)
+}
+
+func TestVaultKmsClient_Kubernetes(t *testing.T) {
+	fake, server := newFakeVaultTransit(t)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.Nil(t, os.WriteFile(tokenFile, []byte("service-account-jwt\n"), 0600))
+	kmsClient, err := NewVaultKmsClient(&constant.VaultKMSConfig{Address: server.URL, KubernetesRole: "nacos",
+		KubernetesTokenFile: tokenFile, KubernetesMount: "/k8s-prod/"})
+	assert.Nil(t, err)
+
+	_, _, err = kmsClient.GenerateDataKey("nacos", kmsAes256KeySpec)
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]string{{"path": "/v1/auth/k8s-prod/login", "role": "nacos", "jwt": "service-account-jwt"}}, fake.logins)
+
+	missing, err := NewVaultKmsClient(&constant.VaultKMSConfig{Address: server.URL, KubernetesRole: "nacos",
+		KubernetesTokenFile: filepath.Join(t.TempDir(), "missing")})
+	assert.Nil(t, err)
+	_, err = missing.Encrypt("content", "nacos")
+	assert.ErrorContains(t, err, "service account token")
+}
+
+func TestVaultKmsClient_Plugins(t *testing.T) {
+	_, server := newFakeVaultTransit(t)
+	kmsClient, err := innerNewKmsClient(constant.ClientConfig{KMSVersion: constant.KMSVault,
+		VaultKMSConfig: &constant.VaultKMSConfig{Address: server.URL, Token: "root"}})
+	assert.Nil(t, err)
+	assert.IsType(t, &VaultKmsClient{}, kmsClient)
+
+	handler := NewKmsHandler()
+	_ = handler.RegisterPlugin(&KmsAes256Plugin{kmsPlugin{kmsClient: kmsClient}})
+	_ = handler.RegisterPlugin(NewKmsAes256GcmPlugin(kmsClient, nil))
+	_ = handler.RegisterPlugin(&KmsBasePlugin{kmsPlugin{kmsClient: kmsClient}})
+	for _, dataId := range []string{"cipher-kms-aes-256-app.yaml", "cipher-kms-aes-256-gcm-app.yaml", "cipher-app.yaml"} {
+		param := &HandlerParam{DataId: dataId, Group: "APP", Content: "password: secret", KeyId: "nacos"}
+		assert.Nil(t, handler.EncryptionHandler(param))
+		assert.NotEqual(t, "password: secret", param.Content)
+		decrypted := &HandlerParam{DataId: dataId, Group: "APP", Content: param.Content, EncryptedDataKey: param.EncryptedDataKey}
+		assert.Nil(t, handler.DecryptionHandler(decrypted))
+		assert.Equal(t, "password: secret", decrypted.Content)
+	}
+}
@@ -0,0 +1,259 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"path"
+	"strings"
+
+	nacos_inner_encryption "github.com/nacos-group/nacos-sdk-go/v2/common/encryption"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+)
+
+const (
+	CompressionFilterName = "configCompressionFilter"
+	// CompressionPrefix is the default prefix of the dataIds whose content is compressed, it comes after the
+	// cipher prefix of the dataIds which are also encrypted, e.g. cipher-kms-aes-256-compress-routes.json
+	CompressionPrefix = "compress-"
+
+	GzipCodecName = "gzip"
+
+	// compressionFilterOrder places the filter after the decryption and before the placeholder resolution of the
+	// content being read, so the content is compressed before it's encrypted when published
+	compressionFilterOrder = 50
+
+	defaultMaxDecompressedSize = 64 * 1024 * 1024
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	// ZstdMagic is the magic number of zstd frames, for the codecs registered with WithCompressionCodec
+	ZstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// CompressionCodec compresses the content of the configs. The compressed data must start with Magic, which tells the
+// codec of the content being read.
+type CompressionCodec interface {
+	GetCodecName() string
+	Magic() []byte
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+type gzipCodec struct {
+	level int
+}
+
+// NewGzipCompressionCodec creates a gzip codec compressing with level, see compress/gzip for the levels.
+func NewGzipCompressionCodec(level int) (CompressionCodec, error) {
+	if _, err := gzip.NewWriterLevel(io.Discard, level); err != nil {
+		return nil, err
+	}
+	return &gzipCodec{level: level}, nil
+}
+
+func (c *gzipCodec) GetCodecName() string {
+	return GzipCodecName
+}
+
+func (c *gzipCodec) Magic() []byte {
+	return gzipMagic
+}
+
+func (c *gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, c.level)
+}
+
+func (c *gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+type CompressionOption func(*ConfigCompressionFilter)
+
+// WithCompressionPrefix replaces CompressionPrefix as the prefix of the dataIds to compress.
+func WithCompressionPrefix(prefix string) CompressionOption {
+	return func(f *ConfigCompressionFilter) {
+		f.prefix = prefix
+	}
+}
+
+// WithCompressionDataIdPatterns compresses the dataIds matching one of patterns as well, the patterns are matched
+// with path.Match, e.g. "routes-*.json".
+func WithCompressionDataIdPatterns(patterns ...string) CompressionOption {
+	return func(f *ConfigCompressionFilter) {
+		f.patterns = append(f.patterns, patterns...)
+	}
+}
+
+// WithCompressionCodec compresses the content published with codec instead of gzip, the content compressed by gzip
+// is still read.
+func WithCompressionCodec(codec CompressionCodec) CompressionOption {
+	return func(f *ConfigCompressionFilter) {
+		f.codec = codec
+		f.codecs = append(f.codecs, codec)
+	}
+}
+
+// WithCompressionMinSize leaves the content shorter than size bytes uncompressed.
+func WithCompressionMinSize(size int) CompressionOption {
+	return func(f *ConfigCompressionFilter) {
+		f.minSize = size
+	}
+}
+
+// WithMaxDecompressedSize fails the read of the content decompressing to more than size bytes, 64MB by default.
+func WithMaxDecompressedSize(size int64) CompressionOption {
+	return func(f *ConfigCompressionFilter) {
+		f.maxDecompressedSize = size
+	}
+}
+
+// ConfigCompressionFilter compresses and base64 encodes the content published for the dataIds carrying its prefix or
+// matching its patterns, and restores the content read for them. The content read is recognized by the magic number
+// of the codecs, so content published before compression was enabled, or shorter than the min size, is read as is.
+type ConfigCompressionFilter struct {
+	prefix              string
+	patterns            []string
+	codec               CompressionCodec
+	codecs              []CompressionCodec
+	minSize             int
+	maxDecompressedSize int64
+}
+
+func NewConfigCompressionFilter(opts ...CompressionOption) (*ConfigCompressionFilter, error) {
+	gzipDefault := &gzipCodec{level: gzip.DefaultCompression}
+	f := &ConfigCompressionFilter{
+		prefix:              CompressionPrefix,
+		codec:               gzipDefault,
+		codecs:              []CompressionCodec{gzipDefault},
+		maxDecompressedSize: defaultMaxDecompressedSize,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if f.codec == nil || len(f.codec.Magic()) == 0 {
+		return nil, errors.New("compression codec must have a magic number")
+	}
+	if len(f.prefix) == 0 && len(f.patterns) == 0 {
+		return nil, errors.New("either a dataId prefix or a dataId pattern is required")
+	}
+	for _, pattern := range f.patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid dataId pattern [%s]", pattern)
+		}
+	}
+	return f, nil
+}
+
+func (f *ConfigCompressionFilter) DoFilter(param *vo.ConfigParam) error {
+	if len(param.Content) == 0 || !f.isCompressed(param.DataId) {
+		return nil
+	}
+	if param.UsageType == vo.RequestType {
+		if len(param.Content) < f.minSize {
+			return nil
+		}
+		content, err := f.compress(param.Content)
+		if err != nil {
+			return errors.Wrapf(err, "compress config dataId=%s", param.DataId)
+		}
+		param.Content = content
+	} else if param.UsageType == vo.ResponseType {
+		content, err := f.decompress(param.Content)
+		if err != nil {
+			return errors.Wrapf(err, "decompress config dataId=%s", param.DataId)
+		}
+		param.Content = content
+	}
+	return nil
+}
+
+func (f *ConfigCompressionFilter) GetOrder() int {
+	return compressionFilterOrder
+}
+
+func (f *ConfigCompressionFilter) GetFilterName() string {
+	return CompressionFilterName
+}
+
+func (f *ConfigCompressionFilter) isCompressed(dataId string) bool {
+	if len(f.prefix) > 0 {
+		if strings.HasPrefix(dataId, f.prefix) ||
+			(strings.HasPrefix(dataId, nacos_inner_encryption.CipherPrefix) && strings.Contains(dataId, "-"+f.prefix)) {
+			return true
+		}
+	}
+	for _, pattern := range f.patterns {
+		if matched, _ := path.Match(pattern, dataId); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *ConfigCompressionFilter) compress(content string) (string, error) {
+	var buffer bytes.Buffer
+	writer, err := f.codec.NewWriter(&buffer)
+	if err != nil {
+		return "", err
+	}
+	if _, err = io.WriteString(writer, content); err != nil {
+		return "", err
+	}
+	if err = writer.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+func (f *ConfigCompressionFilter) decompress(content string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return content, nil
+	}
+	codec := f.codecOf(data)
+	if codec == nil {
+		return content, nil
+	}
+	reader, err := codec.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", errors.Wrap(err, codec.GetCodecName())
+	}
+	defer reader.Close()
+	decompressed, err := io.ReadAll(io.LimitReader(reader, f.maxDecompressedSize+1))
+	if err != nil {
+		return "", errors.Wrap(err, codec.GetCodecName())
+	}
+	if int64(len(decompressed)) > f.maxDecompressedSize {
+		return "", errors.Errorf("decompressed content exceeds %d bytes", f.maxDecompressedSize)
+	}
+	return string(decompressed), nil
+}
+
+func (f *ConfigCompressionFilter) codecOf(data []byte) CompressionCodec {
+	for i := len(f.codecs) - 1; i >= 0; i-- {
+		if bytes.HasPrefix(data, f.codecs[i].Magic()) {
+			return f.codecs[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	nacos_inner_encryption "github.com/nacos-group/nacos-sdk-go/v2/common/encryption"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+var largeConfig = strings.Repeat("route.service-a=10.0.0.1:8080\n", 1000)
+
+func filterConfig(t *testing.T, f IConfigFilter, dataId, content string, usageType vo.UsageType) string {
+	param := &vo.ConfigParam{DataId: dataId, Content: content, UsageType: usageType}
+	assert.Nil(t, f.DoFilter(param))
+	return param.Content
+}
+
+func TestConfigCompressionFilter_RoundTrip(t *testing.T) {
+	f, err := NewConfigCompressionFilter()
+	assert.Nil(t, err)
+	compressed := filterConfig(t, f, "compress-routes.properties", largeConfig, vo.RequestType)
+	assert.True(t, len(compressed) < len(largeConfig)/10)
+	data, err := base64.StdEncoding.DecodeString(compressed)
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(data, gzipMagic))
+	// the same content compresses the same, so publishing it again doesn't change its md5
+	assert.Equal(t, compressed, filterConfig(t, f, "compress-routes.properties", largeConfig, vo.RequestType))
+	assert.Equal(t, largeConfig, filterConfig(t, f, "compress-routes.properties", compressed, vo.ResponseType))
+
+	// other dataIds are left alone
+	assert.Equal(t, largeConfig, filterConfig(t, f, "routes.properties", largeConfig, vo.RequestType))
+	// content published before compression was enabled is read as is
+	assert.Equal(t, "a=b", filterConfig(t, f, "compress-routes.properties", "a=b", vo.ResponseType))
+}
+
+func TestConfigCompressionFilter_DataIds(t *testing.T) {
+	f, err := NewConfigCompressionFilter(WithCompressionPrefix("zip-"), WithCompressionDataIdPatterns("routes-*.json"))
+	assert.Nil(t, err)
+	assert.True(t, f.isCompressed("zip-app"))
+	assert.True(t, f.isCompressed("cipher-kms-aes-256-zip-app"))
+	assert.True(t, f.isCompressed("routes-east.json"))
+	assert.False(t, f.isCompressed("app-zip-app"))
+	assert.False(t, f.isCompressed("compress-app"))
+	assert.False(t, f.isCompressed("routes-east.yaml"))
+
+	_, err = NewConfigCompressionFilter(WithCompressionPrefix(""))
+	assert.NotNil(t, err)
+	_, err = NewConfigCompressionFilter(WithCompressionDataIdPatterns("["))
+	assert.NotNil(t, err)
+	_, err = NewConfigCompressionFilter(WithCompressionCodec(nil))
+	assert.NotNil(t, err)
+}
+
+func TestConfigCompressionFilter_Sizes(t *testing.T) {
+	f, err := NewConfigCompressionFilter(WithCompressionMinSize(1024), WithMaxDecompressedSize(int64(len(largeConfig)-1)))
+	assert.Nil(t, err)
+	assert.Equal(t, "a=b", filterConfig(t, f, "compress-app", "a=b", vo.RequestType))
+	compressed := filterConfig(t, f, "compress-app", largeConfig, vo.RequestType)
+	assert.NotEqual(t, largeConfig, compressed)
+	err = f.DoFilter(&vo.ConfigParam{DataId: "compress-app", Content: compressed, UsageType: vo.ResponseType})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "exceeds")
+
+	// corrupted content is an error rather than garbage
+	corrupted := base64.StdEncoding.EncodeToString(append(append([]byte{}, gzipMagic...), 0, 1, 2))
+	assert.NotNil(t, f.DoFilter(&vo.ConfigParam{DataId: "compress-app", Content: corrupted, UsageType: vo.ResponseType}))
+}
+
+// prefixCodec "compresses" by writing its magic before the content
+type prefixCodec struct{}
+
+func (c prefixCodec) GetCodecName() string { return "prefix" }
+
+func (c prefixCodec) Magic() []byte { return []byte("PFX") }
+
+func (c prefixCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	if _, err := w.Write(c.Magic()); err != nil {
+		return nil, err
+	}
+	return nopWriteCloser{w}, nil
+}
+
+func (c prefixCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	if _, err := io.ReadFull(r, make([]byte, len(c.Magic()))); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(r), nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestConfigCompressionFilter_Codec(t *testing.T) {
+	gzipFilter, err := NewConfigCompressionFilter()
+	assert.Nil(t, err)
+	gzipped := filterConfig(t, gzipFilter, "compress-app", "a=b", vo.RequestType)
+
+	f, err := NewConfigCompressionFilter(WithCompressionCodec(prefixCodec{}))
+	assert.Nil(t, err)
+	compressed := filterConfig(t, f, "compress-app", "a=b", vo.RequestType)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("PFXa=b")), compressed)
+	assert.Equal(t, "a=b", filterConfig(t, f, "compress-app", compressed, vo.ResponseType))
+	// the content compressed by gzip before switching codecs is still read
+	assert.Equal(t, "a=b", filterConfig(t, f, "compress-app", gzipped, vo.ResponseType))
+
+	_, err = NewGzipCompressionCodec(100)
+	assert.NotNil(t, err)
+}
+
+// wrappingHandler "encrypts" by wrapping the content
+type wrappingHandler struct {
+	encrypted []string
+}
+
+func (h *wrappingHandler) EncryptionHandler(param *nacos_inner_encryption.HandlerParam) error {
+	h.encrypted = append(h.encrypted, param.Content)
+	param.Content = "enc(" + param.Content + ")"
+	return nil
+}
+
+func (h *wrappingHandler) DecryptionHandler(param *nacos_inner_encryption.HandlerParam) error {
+	param.Content = strings.TrimSuffix(strings.TrimPrefix(param.Content, "enc("), ")")
+	return nil
+}
+
+func (h *wrappingHandler) RegisterPlugin(nacos_inner_encryption.Plugin) error { return nil }
+
+func (h *wrappingHandler) GetHandlerName() string { return "wrappingHandler" }
+
+func TestConfigCompressionFilter_WithEncryption(t *testing.T) {
+	handler := &wrappingHandler{}
+	chain := NewConfigFilterChainManager()
+	compressionFilter, err := NewConfigCompressionFilter()
+	assert.Nil(t, err)
+	assert.Nil(t, RegisterConfigFilterToChain(chain, compressionFilter))
+	assert.Nil(t, RegisterConfigFilterToChain(chain, NewDefaultConfigEncryptionFilter(handler)))
+
+	// the content is compressed before it's encrypted, and decrypted before it's decompressed
+	param := &vo.ConfigParam{DataId: "cipher-kms-aes-256-compress-routes", Content: largeConfig, UsageType: vo.RequestType}
+	assert.Nil(t, chain.DoFilters(param))
+	assert.Len(t, handler.encrypted, 1)
+	assert.NotEqual(t, largeConfig, handler.encrypted[0])
+	assert.True(t, strings.HasPrefix(param.Content, "enc("))
+
+	param.UsageType = vo.ResponseType
+	assert.Nil(t, chain.DoFilters(param))
+	assert.Equal(t, largeConfig, param.Content)
+}
@@ -24,7 +24,7 @@ import (
 )
 
 const (
-	defaultConfigEncryptionFilterName = "defaultConfigEncryptionFilter"
+	EncryptionFilterName = "defaultConfigEncryptionFilter"
 )
 
 var (
@@ -48,6 +48,7 @@ func (d *DefaultConfigEncryptionFilter) DoFilter(param *vo.ConfigParam) error {
 	if param.UsageType == vo.RequestType {
 		encryptionParam := &nacos_inner_encryption.HandlerParam{
 			DataId:  param.DataId,
+			Group:   param.Group,
 			Content: param.Content,
 			KeyId:   param.KmsKeyId,
 		}
@@ -60,6 +61,7 @@ func (d *DefaultConfigEncryptionFilter) DoFilter(param *vo.ConfigParam) error {
 	} else if param.UsageType == vo.ResponseType {
 		decryptionParam := &nacos_inner_encryption.HandlerParam{
 			DataId:           param.DataId,
+			Group:            param.Group,
 			Content:          param.Content,
 			EncryptedDataKey: param.EncryptedDataKey,
 		}
@@ -76,7 +78,7 @@ func (d *DefaultConfigEncryptionFilter) GetOrder() int {
 }
 
 func (d *DefaultConfigEncryptionFilter) GetFilterName() string {
-	return defaultConfigEncryptionFilterName
+	return EncryptionFilterName
 }
 func (d *DefaultConfigEncryptionFilter) paramCheck(param vo.ConfigParam) error {
 	if !strings.HasPrefix(param.DataId, nacos_inner_encryption.CipherPrefix) ||
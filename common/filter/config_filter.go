@@ -30,6 +30,8 @@ type IConfigFilterChain interface {
 
 type IConfigFilter interface {
 	DoFilter(*vo.ConfigParam) error
+	// GetOrder the filters with a lower order are closer to the server, they run last on the content being published
+	// and first on the content being read
 	GetOrder() int
 	GetFilterName() string
 }
@@ -50,6 +52,7 @@ func newConfigFilterChainManager() *DefaultConfigFilterChainManager {
 
 type DefaultConfigFilterChainManager struct {
 	configFilterPriorityQueue
+	validators []IConfigValidator
 }
 
 func (m *DefaultConfigFilterChainManager) AddFilter(filter IConfigFilter) error {
@@ -60,7 +63,40 @@ func (m *DefaultConfigFilterChainManager) GetFilters() []IConfigFilter {
 	return m.configFilterPriorityQueue
 }
 
+func (m *DefaultConfigFilterChainManager) AddValidator(validator IConfigValidator) error {
+	if validator == nil {
+		return fmt.Errorf("validator is nil")
+	}
+	for _, v := range m.validators {
+		if v.GetValidatorName() == validator.GetValidatorName() {
+			return nil
+		}
+	}
+	m.validators = append(m.validators, validator)
+	return nil
+}
+
+func (m *DefaultConfigFilterChainManager) GetValidators() []IConfigValidator {
+	return m.validators
+}
+
+// DoFilters runs the filters from the highest order to the lowest on the request path and the other way round on
+// the response path, so that the content read is restored the way it was published. The validators run on the
+// request path before any filter, so they see the content as published.
 func (m *DefaultConfigFilterChainManager) DoFilters(param *vo.ConfigParam) error {
+	if param.UsageType == vo.RequestType {
+		for _, validator := range m.validators {
+			if err := validator.Validate(param); err != nil {
+				return err
+			}
+		}
+		for index := len(m.GetFilters()) - 1; index >= 0; index-- {
+			if err := m.GetFilters()[index].DoFilter(param); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	for index := 0; index < len(m.GetFilters()); index++ {
 		if err := m.GetFilters()[index].DoFilter(param); err != nil {
 			return err
@@ -94,11 +130,8 @@ func (c *configFilterPriorityQueue) addFilter(filter IConfigFilter) error {
 			break
 		}
 	}
-	if pos == len(*c) {
-		*c = append((*c)[:], filter)
-	} else {
-		temp := append((*c)[:pos], filter)
-		*c = append(temp[:], (*c)[pos:]...)
-	}
+	*c = append(*c, nil)
+	copy((*c)[pos+1:], (*c)[pos:])
+	(*c)[pos] = filter
 	return nil
 }
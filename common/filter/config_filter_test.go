@@ -0,0 +1,64 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderedFilter struct {
+	name  string
+	order int
+	trace *[]string
+}
+
+func (f *orderedFilter) DoFilter(param *vo.ConfigParam) error {
+	*f.trace = append(*f.trace, f.name)
+	return nil
+}
+
+func (f *orderedFilter) GetOrder() int {
+	return f.order
+}
+
+func (f *orderedFilter) GetFilterName() string {
+	return f.name
+}
+
+func TestDefaultConfigFilterChainManager_Order(t *testing.T) {
+	var trace []string
+	chain := NewConfigFilterChainManager()
+	for _, f := range []*orderedFilter{{"c", 100, &trace}, {"a", 0, &trace}, {"b", 50, &trace}, {"d", 200, &trace}} {
+		assert.Nil(t, chain.AddFilter(f))
+	}
+	// a filter with a name already registered is ignored
+	assert.Nil(t, chain.AddFilter(&orderedFilter{"b", 10, &trace}))
+	var names []string
+	for _, f := range chain.GetFilters() {
+		names = append(names, f.GetFilterName())
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d"}, names)
+
+	assert.Nil(t, chain.DoFilters(&vo.ConfigParam{UsageType: vo.ResponseType}))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, trace)
+	trace = nil
+	assert.Nil(t, chain.DoFilters(&vo.ConfigParam{UsageType: vo.RequestType}))
+	assert.Equal(t, []string{"d", "c", "b", "a"}, trace)
+}
@@ -0,0 +1,393 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/encoding"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+)
+
+const (
+	PlaceholderFilterName  = "configPlaceholderFilter"
+	placeholderFilterOrder = 100
+	maxPlaceholderDepth    = 32
+
+	placeholderEnvPrefix   = "env:"
+	placeholderNacosPrefix = "nacos:"
+)
+
+// PlaceholderSource gives the placeholder filter access to the configs referenced by ${nacos:...}.
+type PlaceholderSource interface {
+	// GetConfig returns the content of a referenced config, after the other filters and with its placeholders
+	// unresolved
+	GetConfig(dataId, group string) (string, error)
+	// ListenConfig is called once for every referenced config, onChange must be called on each of its changes
+	ListenConfig(dataId, group string, onChange func()) error
+	// Refresh resolves a config again because a config it references has changed, and notifies its listeners
+	Refresh(dataId, group string)
+}
+
+type PlaceholderOption func(*ConfigPlaceholderFilter)
+
+// WithIgnoreUnresolvable keeps the placeholders that can't be resolved as they are, instead of failing.
+func WithIgnoreUnresolvable() PlaceholderOption {
+	return func(f *ConfigPlaceholderFilter) {
+		f.ignoreUnresolvable = true
+	}
+}
+
+// WithLookupEnv replaces os.LookupEnv for ${env:...}.
+func WithLookupEnv(lookupEnv func(name string) (string, bool)) PlaceholderOption {
+	return func(f *ConfigPlaceholderFilter) {
+		f.lookupEnv = lookupEnv
+	}
+}
+
+// ConfigPlaceholderFilter expands the placeholders of the configs read by the client:
+//
+//	${db.host}                                     a key of the same config
+//	${env:POD_IP}                                  an environment variable
+//	${nacos:DEFAULT_GROUP/common.yaml#redis.addr}  a key of another config, the group defaults to that of the config
+//	${nacos:DEFAULT_GROUP/banner.txt}              the whole content of another config
+//
+// A placeholder may carry a default after ":", e.g. ${db.port:3306}, and "$${" stands for a literal "${".
+// Keys are flattened like encoding.FlattenConfig does. Referenced configs are cached and listened to, a change of
+// one resolves the configs referencing it again. A reference cycle fails the resolution.
+type ConfigPlaceholderFilter struct {
+	source             PlaceholderSource
+	lookupEnv          func(string) (string, bool)
+	ignoreUnresolvable bool
+	mux                sync.Mutex
+	references         map[string]*placeholderReference
+	// dependencies are the references each resolved config used last time
+	dependencies map[string]map[string]struct{}
+}
+
+type placeholderReference struct {
+	dataId   string
+	group    string
+	listened bool
+	loaded   bool
+	content  string
+}
+
+// placeholderDoc is a config placeholders are resolved in, items are its flattened keys.
+type placeholderDoc struct {
+	dataId     string
+	group      string
+	configType string
+	content    string
+	items      map[string]string
+	parsed     bool
+}
+
+type placeholderResolution struct {
+	filter *ConfigPlaceholderFilter
+	stack  []string
+	refs   map[string]struct{}
+}
+
+func NewConfigPlaceholderFilter(source PlaceholderSource, opts ...PlaceholderOption) *ConfigPlaceholderFilter {
+	f := &ConfigPlaceholderFilter{
+		source:       source,
+		lookupEnv:    os.LookupEnv,
+		references:   make(map[string]*placeholderReference),
+		dependencies: make(map[string]map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+func (f *ConfigPlaceholderFilter) DoFilter(param *vo.ConfigParam) error {
+	if param.UsageType != vo.ResponseType {
+		return nil
+	}
+	key := placeholderConfigKey(param.DataId, param.Group)
+	resolution := &placeholderResolution{filter: f, refs: make(map[string]struct{})}
+	content, err := resolution.resolve(newPlaceholderDoc(param.DataId, param.Group, param.Type, param.Content),
+		param.Content, key)
+	f.setDependencies(key, resolution.refs)
+	if err != nil {
+		return errors.Wrapf(err, "resolve placeholders of dataId=%s, group=%s failed", param.DataId, param.Group)
+	}
+	param.Content = content
+	return nil
+}
+
+func (f *ConfigPlaceholderFilter) GetOrder() int {
+	return placeholderFilterOrder
+}
+
+func (f *ConfigPlaceholderFilter) GetFilterName() string {
+	return PlaceholderFilterName
+}
+
+func newPlaceholderDoc(dataId, group, configType, content string) *placeholderDoc {
+	configType = encoding.NormalizeConfigType(configType)
+	if len(configType) == 0 || configType == encoding.ConfigTypeText {
+		configType = encoding.ResolveConfigType("", dataId)
+	}
+	return &placeholderDoc{dataId: dataId, group: group, configType: configType, content: content}
+}
+
+func (doc *placeholderDoc) lookup(key string) (string, bool) {
+	if !doc.parsed {
+		doc.parsed = true
+		if _, err := encoding.GetConfigDecoder(doc.configType); err == nil {
+			items, err := encoding.FlattenConfig(doc.configType, doc.content)
+			if err != nil {
+				logger.Warnf("parse dataId=%s, group=%s for placeholders failed, err:%v", doc.dataId, doc.group, err)
+			}
+			doc.items = items
+		}
+	}
+	value, ok := doc.items[key]
+	return value, ok
+}
+
+func placeholderConfigKey(dataId, group string) string {
+	return group + "/" + dataId
+}
+
+// resolve expands the placeholders of content, which is part of doc. id identifies content in the reference stack.
+func (r *placeholderResolution) resolve(doc *placeholderDoc, content, id string) (string, error) {
+	if !strings.Contains(content, "${") {
+		return content, nil
+	}
+	for _, stacked := range r.stack {
+		if stacked == id {
+			return "", errors.Errorf("placeholder cycle %s -> %s", strings.Join(r.stack, " -> "), id)
+		}
+	}
+	if len(r.stack) >= maxPlaceholderDepth {
+		return "", errors.Errorf("placeholders nested deeper than %d at %s", maxPlaceholderDepth, id)
+	}
+	r.stack = append(r.stack, id)
+	defer func() {
+		r.stack = r.stack[:len(r.stack)-1]
+	}()
+
+	var result strings.Builder
+	for {
+		start := strings.Index(content, "${")
+		if start < 0 {
+			result.WriteString(content)
+			return result.String(), nil
+		}
+		if start > 0 && content[start-1] == '$' {
+			result.WriteString(content[:start-1])
+			result.WriteString("${")
+			content = content[start+2:]
+			continue
+		}
+		end := findPlaceholderEnd(content, start+2)
+		if end < 0 {
+			result.WriteString(content)
+			return result.String(), nil
+		}
+		result.WriteString(content[:start])
+		expression := content[start+2 : end]
+		value, err := r.expand(doc, expression)
+		if err != nil {
+			if !r.filter.ignoreUnresolvable || isPlaceholderCycle(err) {
+				return "", err
+			}
+			logger.Warnf("placeholder ${%s} of dataId=%s, group=%s is kept, err:%v", expression, doc.dataId, doc.group, err)
+			value = content[start : end+1]
+		}
+		result.WriteString(value)
+		content = content[end+1:]
+	}
+}
+
+// findPlaceholderEnd returns the index of the "}" closing the placeholder whose expression starts at from.
+func findPlaceholderEnd(content string, from int) int {
+	depth := 1
+	for i := from; i < len(content); i++ {
+		switch {
+		case content[i] == '$' && i+1 < len(content) && content[i+1] == '{':
+			depth++
+			i++
+		case content[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isPlaceholderCycle(err error) bool {
+	return strings.HasPrefix(errors.Cause(err).Error(), "placeholder cycle")
+}
+
+// expand resolves a single placeholder expression, its default is resolved only if it's used.
+func (r *placeholderResolution) expand(doc *placeholderDoc, expression string) (string, error) {
+	var name, defaultValue string
+	var hasDefault bool
+	var value string
+	var err error
+	switch {
+	case strings.HasPrefix(expression, placeholderEnvPrefix):
+		name, defaultValue, hasDefault = splitPlaceholderDefault(strings.TrimPrefix(expression, placeholderEnvPrefix))
+		var ok bool
+		if value, ok = r.filter.lookupEnv(name); !ok {
+			err = errors.Errorf("environment variable %s is not set", name)
+		}
+	case strings.HasPrefix(expression, placeholderNacosPrefix):
+		name, defaultValue, hasDefault = splitPlaceholderDefault(strings.TrimPrefix(expression, placeholderNacosPrefix))
+		value, err = r.expandReference(doc, name)
+	default:
+		name, defaultValue, hasDefault = splitPlaceholderDefault(expression)
+		var ok bool
+		if value, ok = doc.lookup(name); ok {
+			value, err = r.resolve(doc, value, placeholderConfigKey(doc.dataId, doc.group)+"#"+name)
+		} else {
+			err = errors.Errorf("key %s is not found in dataId=%s, group=%s", name, doc.dataId, doc.group)
+		}
+	}
+	if err != nil && hasDefault && !isPlaceholderCycle(err) {
+		return r.resolve(doc, defaultValue, "default of "+expression)
+	}
+	return value, err
+}
+
+// expandReference resolves "group/dataId#key", or "group/dataId" for the whole content.
+func (r *placeholderResolution) expandReference(doc *placeholderDoc, reference string) (string, error) {
+	configRef, key := reference, ""
+	if i := strings.IndexByte(reference, '#'); i >= 0 {
+		configRef, key = reference[:i], reference[i+1:]
+	}
+	group, dataId := doc.group, configRef
+	if i := strings.IndexByte(configRef, '/'); i >= 0 {
+		group, dataId = configRef[:i], configRef[i+1:]
+	}
+	if len(dataId) == 0 || len(group) == 0 {
+		return "", errors.Errorf("invalid config reference %s", reference)
+	}
+	refKey := placeholderConfigKey(dataId, group)
+	r.refs[refKey] = struct{}{}
+	content, err := r.filter.getReference(dataId, group)
+	if err != nil {
+		return "", err
+	}
+	refDoc := newPlaceholderDoc(dataId, group, "", content)
+	if len(key) == 0 {
+		return r.resolve(refDoc, content, refKey)
+	}
+	value, ok := refDoc.lookup(key)
+	if !ok {
+		return "", errors.Errorf("key %s is not found in dataId=%s, group=%s", key, dataId, group)
+	}
+	return r.resolve(refDoc, value, refKey+"#"+key)
+}
+
+// splitPlaceholderDefault splits "name:default" at the first ":" outside nested placeholders.
+func splitPlaceholderDefault(expression string) (string, string, bool) {
+	depth := 0
+	for i := 0; i < len(expression); i++ {
+		switch {
+		case expression[i] == '$' && i+1 < len(expression) && expression[i+1] == '{':
+			depth++
+			i++
+		case expression[i] == '}':
+			depth--
+		case expression[i] == ':' && depth == 0:
+			return expression[:i], expression[i+1:], true
+		}
+	}
+	return expression, "", false
+}
+
+// getReference returns the content of a referenced config, it's listened to on first use and cached until it
+// changes.
+func (f *ConfigPlaceholderFilter) getReference(dataId, group string) (string, error) {
+	key := placeholderConfigKey(dataId, group)
+	f.mux.Lock()
+	ref, ok := f.references[key]
+	if !ok {
+		ref = &placeholderReference{dataId: dataId, group: group}
+		f.references[key] = ref
+	}
+	listen := !ref.listened
+	ref.listened = true
+	if ref.loaded {
+		defer f.mux.Unlock()
+		return ref.content, nil
+	}
+	f.mux.Unlock()
+
+	// listen before reading, so that no change slips in between
+	if listen {
+		if err := f.source.ListenConfig(dataId, group, func() { f.onReferenceChanged(key) }); err != nil {
+			f.mux.Lock()
+			ref.listened = false
+			f.mux.Unlock()
+			return "", errors.Wrapf(err, "listen referenced dataId=%s, group=%s failed", dataId, group)
+		}
+	}
+	content, err := f.source.GetConfig(dataId, group)
+	if err == nil && len(content) == 0 {
+		err = errors.Errorf("referenced dataId=%s, group=%s doesn't exist", dataId, group)
+	}
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	// a failed read is retried next time
+	if err == nil {
+		ref.loaded, ref.content = true, content
+	}
+	return content, err
+}
+
+func (f *ConfigPlaceholderFilter) setDependencies(key string, refs map[string]struct{}) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if len(refs) == 0 {
+		delete(f.dependencies, key)
+		return
+	}
+	f.dependencies[key] = refs
+}
+
+func (f *ConfigPlaceholderFilter) onReferenceChanged(refKey string) {
+	var dependents []string
+	f.mux.Lock()
+	if ref, ok := f.references[refKey]; ok {
+		ref.loaded, ref.content = false, ""
+	}
+	for key, refs := range f.dependencies {
+		if _, ok := refs[refKey]; ok {
+			dependents = append(dependents, key)
+		}
+	}
+	f.mux.Unlock()
+
+	for _, key := range dependents {
+		i := strings.IndexByte(key, '/')
+		logger.Infof("referenced config %s changed, resolve %s again", refKey, key)
+		f.source.Refresh(key[i+1:], key[:i])
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPlaceholderSource struct {
+	mux       sync.Mutex
+	contents  map[string]string
+	reads     map[string]int
+	listeners map[string]func()
+	refreshed []string
+}
+
+func newMockPlaceholderSource(contents map[string]string) *mockPlaceholderSource {
+	return &mockPlaceholderSource{contents: contents, reads: map[string]int{}, listeners: map[string]func(){}}
+}
+
+func (m *mockPlaceholderSource) GetConfig(dataId, group string) (string, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	key := group + "/" + dataId
+	m.reads[key]++
+	if group == "BROKEN" {
+		return "", errors.New("read failed")
+	}
+	return m.contents[key], nil
+}
+
+func (m *mockPlaceholderSource) ListenConfig(dataId, group string, onChange func()) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.listeners[group+"/"+dataId] = onChange
+	return nil
+}
+
+func (m *mockPlaceholderSource) Refresh(dataId, group string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.refreshed = append(m.refreshed, group+"/"+dataId)
+}
+
+func doPlaceholderFilter(f *ConfigPlaceholderFilter, dataId, content string) (string, error) {
+	param := &vo.ConfigParam{DataId: dataId, Group: "APP", Content: content, UsageType: vo.ResponseType}
+	err := f.DoFilter(param)
+	return param.Content, err
+}
+
+func TestConfigPlaceholderFilter(t *testing.T) {
+	source := newMockPlaceholderSource(map[string]string{
+		"DEFAULT_GROUP/common.yaml": "redis:\n  addr: ${redis.host}:6379\n  host: redis.local\n",
+		"APP/banner.txt":            "hello ${env:USER_NAME}",
+	})
+	f := NewConfigPlaceholderFilter(source, WithLookupEnv(func(name string) (string, bool) {
+		if name == "POD_IP" {
+			return "10.0.0.1", true
+		}
+		if name == "USER_NAME" {
+			return "nacos", true
+		}
+		return "", false
+	}))
+
+	cases := []struct {
+		name     string
+		dataId   string
+		content  string
+		expected string
+	}{
+		{"SameConfig", "app.properties", "db.host=localhost\ndb.url=jdbc://${db.host}/app", "db.host=localhost\ndb.url=jdbc://localhost/app"},
+		{"Env", "app.properties", "ip=${env:POD_IP}", "ip=10.0.0.1"},
+		{"Reference", "app.properties", "redis=${nacos:DEFAULT_GROUP/common.yaml#redis.addr}", "redis=redis.local:6379"},
+		{"WholeContent", "app.properties", "banner=${nacos:banner.txt}", "banner=hello nacos"},
+		{"Default", "app.properties", "port=${db.port:3306}\nhost=${env:MISSING:${env:POD_IP}}", "port=3306\nhost=10.0.0.1"},
+		{"Escape", "app.properties", "literal=$${db.host}", "literal=${db.host}"},
+		{"Text", "app.txt", "${env:POD_IP}", "10.0.0.1"},
+		{"NoPlaceholder", "app.properties", "a=b", "a=b"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			content, err := doPlaceholderFilter(f, c.dataId, c.content)
+			assert.Nil(t, err)
+			assert.Equal(t, c.expected, content)
+		})
+	}
+
+	t.Run("RequestType", func(t *testing.T) {
+		param := &vo.ConfigParam{DataId: "app.properties", Group: "APP", Content: "${env:POD_IP}", UsageType: vo.RequestType}
+		assert.Nil(t, f.DoFilter(param))
+		assert.Equal(t, "${env:POD_IP}", param.Content)
+	})
+}
+
+func TestConfigPlaceholderFilter_Errors(t *testing.T) {
+	source := newMockPlaceholderSource(map[string]string{
+		"APP/a.properties": "a=${nacos:b.properties#b}",
+		"APP/b.properties": "b=${nacos:a.properties#a}",
+	})
+	f := NewConfigPlaceholderFilter(source, WithLookupEnv(func(string) (string, bool) { return "", false }))
+
+	_, err := doPlaceholderFilter(f, "app.properties", "a=${b}\nb=${a}")
+	assert.ErrorContains(t, err, "placeholder cycle")
+	_, err = doPlaceholderFilter(f, "app.properties", "a=${nacos:a.properties#a}")
+	assert.ErrorContains(t, err, "placeholder cycle")
+	_, err = doPlaceholderFilter(f, "app.properties", "a=${missing}")
+	assert.NotNil(t, err)
+	_, err = doPlaceholderFilter(f, "app.properties", "a=${env:MISSING}")
+	assert.NotNil(t, err)
+	_, err = doPlaceholderFilter(f, "app.properties", "a=${nacos:missing.yaml#a}")
+	assert.NotNil(t, err)
+	_, err = doPlaceholderFilter(f, "app.properties", "a=${nacos:BROKEN/broken.yaml#a}")
+	assert.NotNil(t, err)
+
+	ignoring := NewConfigPlaceholderFilter(source, WithIgnoreUnresolvable(),
+		WithLookupEnv(func(string) (string, bool) { return "", false }))
+	content, err := doPlaceholderFilter(ignoring, "app.properties", "a=${missing}\nb=${env:MISSING}")
+	assert.Nil(t, err)
+	assert.Equal(t, "a=${missing}\nb=${env:MISSING}", content)
+	// a cycle fails even then
+	_, err = doPlaceholderFilter(ignoring, "app.properties", "a=${b}\nb=${a}")
+	assert.NotNil(t, err)
+}
+
+func TestConfigPlaceholderFilter_ReferenceChanged(t *testing.T) {
+	source := newMockPlaceholderSource(map[string]string{"DEFAULT_GROUP/common.yaml": "redis: old"})
+	f := NewConfigPlaceholderFilter(source)
+
+	content, err := doPlaceholderFilter(f, "app.properties", "redis=${nacos:DEFAULT_GROUP/common.yaml#redis}")
+	assert.Nil(t, err)
+	assert.Equal(t, "redis=old", content)
+	_, err = doPlaceholderFilter(f, "other.properties", "redis=${nacos:DEFAULT_GROUP/common.yaml#redis}")
+	assert.Nil(t, err)
+	// the referenced config is cached
+	assert.Equal(t, 1, source.reads["DEFAULT_GROUP/common.yaml"])
+	onChange, ok := source.listeners["DEFAULT_GROUP/common.yaml"]
+	assert.True(t, ok)
+
+	source.mux.Lock()
+	source.contents["DEFAULT_GROUP/common.yaml"] = "redis: new"
+	source.mux.Unlock()
+	onChange()
+	assert.ElementsMatch(t, []string{"APP/app.properties", "APP/other.properties"}, source.refreshed)
+
+	content, err = doPlaceholderFilter(f, "app.properties", "redis=${nacos:DEFAULT_GROUP/common.yaml#redis}")
+	assert.Nil(t, err)
+	assert.Equal(t, "redis=new", content)
+
+	// a config no longer referencing it isn't refreshed
+	source.refreshed = nil
+	_, err = doPlaceholderFilter(f, "other.properties", "redis=local")
+	assert.Nil(t, err)
+	onChange()
+	assert.Equal(t, []string{"APP/app.properties"}, source.refreshed)
+}
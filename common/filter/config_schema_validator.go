@@ -0,0 +1,905 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/encoding"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+)
+
+const (
+	JsonSchemaValidatorName = "jsonSchemaValidator"
+)
+
+// JsonSchemaValidator validates configs against the JSON Schemas registered for their dataId. Content of any type
+// decoding into a tree, json, yaml, properties and toml, is validated as the equivalent json document.
+//
+// The validation keywords of draft-07 are supported together with the local references ("#/definitions/..." or
+// "#/$defs/..."). A schema using a keyword that isn't supported, format or a remote reference included, is rejected
+// when it's registered rather than having the keyword ignored.
+type JsonSchemaValidator struct {
+	mux     sync.RWMutex
+	schemas []registeredSchema
+}
+
+type registeredSchema struct {
+	dataIdPattern string
+	schema        *jsonSchema
+}
+
+func NewJsonSchemaValidator() *JsonSchemaValidator {
+	return &JsonSchemaValidator{}
+}
+
+func (v *JsonSchemaValidator) GetValidatorName() string {
+	return JsonSchemaValidatorName
+}
+
+// RegisterSchema registers schema for the configs whose dataId matches dataIdPattern, the pattern is matched with
+// path.Match, e.g. "*.json" or "app-?.yaml". A config matching several patterns is validated against all of them.
+func (v *JsonSchemaValidator) RegisterSchema(dataIdPattern string, schema string) error {
+	if _, err := path.Match(dataIdPattern, ""); err != nil {
+		return errors.Wrapf(err, "invalid dataId pattern [%s]", dataIdPattern)
+	}
+	compiled, err := compileJsonSchema(schema)
+	if err != nil {
+		return errors.Wrapf(err, "invalid schema for dataId pattern [%s]", dataIdPattern)
+	}
+	v.mux.Lock()
+	defer v.mux.Unlock()
+	v.schemas = append(v.schemas, registeredSchema{dataIdPattern: dataIdPattern, schema: compiled})
+	return nil
+}
+
+func (v *JsonSchemaValidator) Validate(param *vo.ConfigParam) error {
+	schemas := v.matchSchemas(param.DataId)
+	if len(schemas) == 0 {
+		return nil
+	}
+	validationErr := &ConfigValidationError{
+		DataId:    param.DataId,
+		Group:     param.Group,
+		Validator: JsonSchemaValidatorName,
+	}
+	configType := encoding.ResolveConfigType(param.Type, param.DataId)
+	document, err := decodeJsonDocument(configType, param.Content)
+	if err != nil {
+		validationErr.Violations = []ConfigViolation{{Message: err.Error()}}
+		return validationErr
+	}
+	for _, schema := range schemas {
+		schema.validate(document, "", &validationErr.Violations)
+	}
+	if len(validationErr.Violations) == 0 {
+		return nil
+	}
+	return validationErr
+}
+
+func (v *JsonSchemaValidator) matchSchemas(dataId string) []*jsonSchema {
+	v.mux.RLock()
+	defer v.mux.RUnlock()
+	var schemas []*jsonSchema
+	for _, registered := range v.schemas {
+		if matched, _ := path.Match(registered.dataIdPattern, dataId); matched {
+			schemas = append(schemas, registered.schema)
+		}
+	}
+	return schemas
+}
+
+// decodeJsonDocument decodes content into the values json decodes into, with numbers kept as json.Number.
+func decodeJsonDocument(configType, content string) (interface{}, error) {
+	data := []byte(content)
+	switch configType {
+	case encoding.ConfigTypeJson:
+	case encoding.ConfigTypeYaml, encoding.ConfigTypeProperties, encoding.ConfigTypeToml:
+		tree, err := encoding.DecodeConfigToMap(configType, content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", configType)
+		}
+		if data, err = json.Marshal(normalizeTree(tree)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("content of type %s can not be validated against a schema", configType)
+	}
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", configType)
+	}
+	return document, nil
+}
+
+// normalizeTree converts the map[interface{}]interface{} some decoders produce so that the tree marshals to json.
+func normalizeTree(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = normalizeTree(child)
+		}
+		return v
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			result[fmt.Sprint(key)] = normalizeTree(child)
+		}
+		return result
+	case []interface{}:
+		for i, child := range v {
+			v[i] = normalizeTree(child)
+		}
+		return v
+	case []map[string]interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, child := range v {
+			result = append(result, normalizeTree(child))
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+type jsonSchema struct {
+	// alwaysValid and neverValid are the true and false schemas
+	alwaysValid bool
+	neverValid  bool
+
+	ref   *jsonSchema
+	types []string
+	enum  []interface{}
+	// constValue is only checked when hasConst is set, null being a valid const
+	constValue interface{}
+	hasConst   bool
+
+	properties           map[string]*jsonSchema
+	required             []string
+	additionalProperties *jsonSchema
+	patternProperties    []patternSchema
+	minProperties        *int
+	maxProperties        *int
+
+	items       *jsonSchema
+	tupleItems  []*jsonSchema
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	allOf []*jsonSchema
+	anyOf []*jsonSchema
+	oneOf []*jsonSchema
+	not   *jsonSchema
+
+	ifSchema   *jsonSchema
+	thenSchema *jsonSchema
+	elseSchema *jsonSchema
+
+	contains          *jsonSchema
+	additionalItems   *jsonSchema
+	propertyNames     *jsonSchema
+	dependencies      map[string]*jsonSchema
+	dependentRequired map[string][]string
+}
+
+// supportedSchemaKeywords are the keywords compileInto understands, the annotations being accepted and ignored
+var supportedSchemaKeywords = map[string]bool{
+	"$ref": true, "type": true, "enum": true, "const": true,
+	"properties": true, "required": true, "additionalProperties": true, "patternProperties": true,
+	"minProperties": true, "maxProperties": true, "propertyNames": true, "dependencies": true,
+	"items": true, "additionalItems": true, "contains": true, "minItems": true, "maxItems": true, "uniqueItems": true,
+	"minLength": true, "maxLength": true, "pattern": true,
+	"minimum": true, "maximum": true, "exclusiveMinimum": true, "exclusiveMaximum": true, "multipleOf": true,
+	"allOf": true, "anyOf": true, "oneOf": true, "not": true, "if": true, "then": true, "else": true,
+	"definitions": true, "$defs": true,
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true, "default": true,
+	"examples": true, "readOnly": true, "writeOnly": true,
+}
+
+type patternSchema struct {
+	pattern *regexp.Regexp
+	schema  *jsonSchema
+}
+
+type jsonSchemaCompiler struct {
+	root interface{}
+	refs map[string]*jsonSchema
+}
+
+func compileJsonSchema(schema string) (*jsonSchema, error) {
+	var root interface{}
+	decoder := json.NewDecoder(strings.NewReader(schema))
+	decoder.UseNumber()
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+	compiler := &jsonSchemaCompiler{root: root, refs: make(map[string]*jsonSchema)}
+	return compiler.compile(root)
+}
+
+func (c *jsonSchemaCompiler) compile(raw interface{}) (*jsonSchema, error) {
+	schema := &jsonSchema{}
+	if err := c.compileInto(schema, raw); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func (c *jsonSchemaCompiler) compileAll(raw interface{}, keyword string) ([]*jsonSchema, error) {
+	list, ok := raw.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, errors.Errorf("%s must be a non-empty array of schemas", keyword)
+	}
+	schemas := make([]*jsonSchema, 0, len(list))
+	for _, item := range list {
+		schema, err := c.compile(item)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, nil
+}
+
+// resolveRef compiles the schema a local reference points to, the compiled schemas are shared by reference so that
+// recursive definitions end up pointing to themselves.
+func (c *jsonSchemaCompiler) resolveRef(ref string) (*jsonSchema, error) {
+	if schema, ok := c.refs[ref]; ok {
+		return schema, nil
+	}
+	if !strings.HasPrefix(ref, "#") {
+		return nil, errors.Errorf("only local $ref is supported, got [%s]", ref)
+	}
+	target := c.root
+	pointer := strings.TrimPrefix(ref, "#")
+	if len(pointer) > 0 {
+		if !strings.HasPrefix(pointer, "/") {
+			return nil, errors.Errorf("invalid $ref [%s]", ref)
+		}
+		for _, token := range strings.Split(pointer[1:], "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			switch node := target.(type) {
+			case map[string]interface{}:
+				child, ok := node[token]
+				if !ok {
+					return nil, errors.Errorf("$ref [%s] can not be resolved", ref)
+				}
+				target = child
+			case []interface{}:
+				index, err := strconv.Atoi(token)
+				if err != nil || index < 0 || index >= len(node) {
+					return nil, errors.Errorf("$ref [%s] can not be resolved", ref)
+				}
+				target = node[index]
+			default:
+				return nil, errors.Errorf("$ref [%s] can not be resolved", ref)
+			}
+		}
+	}
+	schema := &jsonSchema{}
+	c.refs[ref] = schema
+	if err := c.compileInto(schema, target); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func (c *jsonSchemaCompiler) compileInto(schema *jsonSchema, raw interface{}) error {
+	if value, ok := raw.(bool); ok {
+		schema.alwaysValid = value
+		schema.neverValid = !value
+		return nil
+	}
+	object, ok := raw.(map[string]interface{})
+	if !ok {
+		return errors.New("schema must be an object or a boolean")
+	}
+	keywords := make([]string, 0, len(object))
+	for keyword := range object {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	for _, keyword := range keywords {
+		if !supportedSchemaKeywords[keyword] {
+			return errors.Errorf("keyword [%s] is not supported", keyword)
+		}
+	}
+	var err error
+	for _, keyword := range []string{"definitions", "$defs"} {
+		definitions, ok := object[keyword]
+		if !ok {
+			continue
+		}
+		definitionMap, ok := definitions.(map[string]interface{})
+		if !ok {
+			return errors.Errorf("%s must be an object", keyword)
+		}
+		// the definitions are compiled even when they aren't referenced, so an unsupported keyword is reported
+		for name, definition := range definitionMap {
+			if _, err = c.compile(definition); err != nil {
+				return errors.Wrapf(err, "%s [%s]", keyword, name)
+			}
+		}
+	}
+	if ref, ok := object["$ref"]; ok {
+		refString, ok := ref.(string)
+		if !ok {
+			return errors.New("$ref must be a string")
+		}
+		if schema.ref, err = c.resolveRef(refString); err != nil {
+			return err
+		}
+	}
+	if types, ok := object["type"]; ok {
+		if schema.types, err = schemaStrings(types, "type"); err != nil {
+			return err
+		}
+	}
+	if enum, ok := object["enum"]; ok {
+		if schema.enum, ok = enum.([]interface{}); !ok {
+			return errors.New("enum must be an array")
+		}
+	}
+	if constValue, ok := object["const"]; ok {
+		schema.constValue, schema.hasConst = constValue, true
+	}
+	if properties, ok := object["properties"]; ok {
+		propertyMap, ok := properties.(map[string]interface{})
+		if !ok {
+			return errors.New("properties must be an object")
+		}
+		schema.properties = make(map[string]*jsonSchema, len(propertyMap))
+		for name, property := range propertyMap {
+			if schema.properties[name], err = c.compile(property); err != nil {
+				return errors.Wrapf(err, "property [%s]", name)
+			}
+		}
+	}
+	if required, ok := object["required"]; ok {
+		if schema.required, err = schemaStrings(required, "required"); err != nil {
+			return err
+		}
+	}
+	if additional, ok := object["additionalProperties"]; ok {
+		if schema.additionalProperties, err = c.compile(additional); err != nil {
+			return errors.Wrap(err, "additionalProperties")
+		}
+	}
+	if patternProperties, ok := object["patternProperties"]; ok {
+		patternMap, ok := patternProperties.(map[string]interface{})
+		if !ok {
+			return errors.New("patternProperties must be an object")
+		}
+		patterns := make([]string, 0, len(patternMap))
+		for pattern := range patternMap {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		for _, pattern := range patterns {
+			compiledPattern, err := regexp.Compile(pattern)
+			if err != nil {
+				return errors.Wrapf(err, "patternProperties [%s]", pattern)
+			}
+			propertySchema, err := c.compile(patternMap[pattern])
+			if err != nil {
+				return errors.Wrapf(err, "patternProperties [%s]", pattern)
+			}
+			schema.patternProperties = append(schema.patternProperties, patternSchema{compiledPattern, propertySchema})
+		}
+	}
+	if items, ok := object["items"]; ok {
+		if list, isList := items.([]interface{}); isList {
+			schema.tupleItems, err = c.compileAll(list, "items")
+		} else {
+			schema.items, err = c.compile(items)
+		}
+		if err != nil {
+			return errors.Wrap(err, "items")
+		}
+	}
+	if uniqueItems, ok := object["uniqueItems"]; ok {
+		if schema.uniqueItems, ok = uniqueItems.(bool); !ok {
+			return errors.New("uniqueItems must be a boolean")
+		}
+	}
+	for keyword, target := range map[string]**int{
+		"minProperties": &schema.minProperties,
+		"maxProperties": &schema.maxProperties,
+		"minItems":      &schema.minItems,
+		"maxItems":      &schema.maxItems,
+		"minLength":     &schema.minLength,
+		"maxLength":     &schema.maxLength,
+	} {
+		if value, ok := object[keyword]; ok {
+			if *target, err = schemaInt(value, keyword); err != nil {
+				return err
+			}
+		}
+	}
+	if pattern, ok := object["pattern"]; ok {
+		patternString, ok := pattern.(string)
+		if !ok {
+			return errors.New("pattern must be a string")
+		}
+		if schema.pattern, err = regexp.Compile(patternString); err != nil {
+			return errors.Wrap(err, "pattern")
+		}
+	}
+	for keyword, target := range map[string]**float64{
+		"minimum":    &schema.minimum,
+		"maximum":    &schema.maximum,
+		"multipleOf": &schema.multipleOf,
+	} {
+		if value, ok := object[keyword]; ok {
+			if *target, err = schemaNumber(value, keyword); err != nil {
+				return err
+			}
+		}
+	}
+	if schema.multipleOf != nil && *schema.multipleOf <= 0 {
+		return errors.New("multipleOf must be greater than 0")
+	}
+	// the draft-04 boolean form makes minimum and maximum exclusive
+	for keyword, bounds := range map[string][2]**float64{
+		"exclusiveMinimum": {&schema.exclusiveMinimum, &schema.minimum},
+		"exclusiveMaximum": {&schema.exclusiveMaximum, &schema.maximum},
+	} {
+		value, ok := object[keyword]
+		if !ok {
+			continue
+		}
+		if exclusive, isBool := value.(bool); isBool {
+			if exclusive {
+				*bounds[0], *bounds[1] = *bounds[1], nil
+			}
+			continue
+		}
+		if *bounds[0], err = schemaNumber(value, keyword); err != nil {
+			return err
+		}
+	}
+	for keyword, target := range map[string]*[]*jsonSchema{
+		"allOf": &schema.allOf,
+		"anyOf": &schema.anyOf,
+		"oneOf": &schema.oneOf,
+	} {
+		if value, ok := object[keyword]; ok {
+			if *target, err = c.compileAll(value, keyword); err != nil {
+				return err
+			}
+		}
+	}
+	for keyword, target := range map[string]**jsonSchema{
+		"not":             &schema.not,
+		"if":              &schema.ifSchema,
+		"then":            &schema.thenSchema,
+		"else":            &schema.elseSchema,
+		"contains":        &schema.contains,
+		"additionalItems": &schema.additionalItems,
+		"propertyNames":   &schema.propertyNames,
+	} {
+		if value, ok := object[keyword]; ok {
+			if *target, err = c.compile(value); err != nil {
+				return errors.Wrap(err, keyword)
+			}
+		}
+	}
+	if dependencies, ok := object["dependencies"]; ok {
+		dependencyMap, ok := dependencies.(map[string]interface{})
+		if !ok {
+			return errors.New("dependencies must be an object")
+		}
+		for name, dependency := range dependencyMap {
+			if _, isList := dependency.([]interface{}); isList {
+				if schema.dependentRequired == nil {
+					schema.dependentRequired = make(map[string][]string)
+				}
+				if schema.dependentRequired[name], err = schemaStrings(dependency, "dependencies"); err != nil {
+					return err
+				}
+				continue
+			}
+			if schema.dependencies == nil {
+				schema.dependencies = make(map[string]*jsonSchema)
+			}
+			if schema.dependencies[name], err = c.compile(dependency); err != nil {
+				return errors.Wrapf(err, "dependencies [%s]", name)
+			}
+		}
+	}
+	return nil
+}
+
+func schemaStrings(raw interface{}, keyword string) ([]string, error) {
+	if value, ok := raw.(string); ok {
+		return []string{value}, nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("%s must be a string or an array of strings", keyword)
+	}
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		value, ok := item.(string)
+		if !ok {
+			return nil, errors.Errorf("%s must be a string or an array of strings", keyword)
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+func schemaInt(raw interface{}, keyword string) (*int, error) {
+	number, ok := raw.(json.Number)
+	if !ok {
+		return nil, errors.Errorf("%s must be a non-negative integer", keyword)
+	}
+	value, err := strconv.Atoi(number.String())
+	if err != nil || value < 0 {
+		return nil, errors.Errorf("%s must be a non-negative integer", keyword)
+	}
+	return &value, nil
+}
+
+func schemaNumber(raw interface{}, keyword string) (*float64, error) {
+	number, ok := raw.(json.Number)
+	if !ok {
+		return nil, errors.Errorf("%s must be a number", keyword)
+	}
+	value, err := number.Float64()
+	if err != nil {
+		return nil, errors.Errorf("%s must be a number", keyword)
+	}
+	return &value, nil
+}
+
+func (s *jsonSchema) isValid(value interface{}) bool {
+	var violations []ConfigViolation
+	s.validate(value, "", &violations)
+	return len(violations) == 0
+}
+
+func (s *jsonSchema) validate(value interface{}, valuePath string, violations *[]ConfigViolation) {
+	report := func(format string, args ...interface{}) {
+		*violations = append(*violations, ConfigViolation{Path: valuePath, Message: fmt.Sprintf(format, args...)})
+	}
+	if s.alwaysValid {
+		return
+	}
+	if s.neverValid {
+		report("is not allowed")
+		return
+	}
+	if s.ref != nil {
+		s.ref.validate(value, valuePath, violations)
+	}
+	valueType := jsonTypeOf(value)
+	if len(s.types) > 0 && !matchesJsonType(valueType, s.types) {
+		report("expected %s, got %s", strings.Join(s.types, " or "), valueType)
+		return
+	}
+	if len(s.enum) > 0 {
+		found := false
+		for _, item := range s.enum {
+			if jsonEqual(value, item) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report("must be one of %s", marshalJsonValue(s.enum))
+		}
+	}
+	if s.hasConst && !jsonEqual(value, s.constValue) {
+		report("must be %s", marshalJsonValue(s.constValue))
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s.validateObject(v, valuePath, violations, report)
+	case []interface{}:
+		s.validateArray(v, valuePath, violations, report)
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.minLength != nil && length < *s.minLength {
+			report("length must be at least %d", *s.minLength)
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			report("length must be at most %d", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			report("must match pattern %s", s.pattern.String())
+		}
+	case json.Number:
+		s.validateNumber(v, report)
+	}
+	for _, schema := range s.allOf {
+		schema.validate(value, valuePath, violations)
+	}
+	if len(s.anyOf) > 0 {
+		valid := false
+		for _, schema := range s.anyOf {
+			if schema.isValid(value) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			report("must match at least one schema of anyOf")
+		}
+	}
+	if len(s.oneOf) > 0 {
+		matched := 0
+		for _, schema := range s.oneOf {
+			if schema.isValid(value) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			report("must match exactly one schema of oneOf, matched %d", matched)
+		}
+	}
+	if s.not != nil && s.not.isValid(value) {
+		report("must not match the schema of not")
+	}
+	if s.ifSchema != nil {
+		if s.ifSchema.isValid(value) {
+			if s.thenSchema != nil {
+				s.thenSchema.validate(value, valuePath, violations)
+			}
+		} else if s.elseSchema != nil {
+			s.elseSchema.validate(value, valuePath, violations)
+		}
+	}
+}
+
+func (s *jsonSchema) validateObject(object map[string]interface{}, valuePath string, violations *[]ConfigViolation,
+	report func(format string, args ...interface{})) {
+	for _, name := range s.required {
+		if _, ok := object[name]; !ok {
+			*violations = append(*violations, ConfigViolation{Path: joinJsonPath(valuePath, name), Message: "is required"})
+		}
+	}
+	if s.minProperties != nil && len(object) < *s.minProperties {
+		report("must have at least %d properties", *s.minProperties)
+	}
+	if s.maxProperties != nil && len(object) > *s.maxProperties {
+		report("must have at most %d properties", *s.maxProperties)
+	}
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		childPath := joinJsonPath(valuePath, name)
+		if s.propertyNames != nil && !s.propertyNames.isValid(name) {
+			*violations = append(*violations, ConfigViolation{Path: childPath, Message: "is not a valid property name"})
+		}
+		for _, required := range s.dependentRequired[name] {
+			if _, ok := object[required]; !ok {
+				*violations = append(*violations, ConfigViolation{Path: joinJsonPath(valuePath, required),
+					Message: fmt.Sprintf("is required by %s", name)})
+			}
+		}
+		if dependency, ok := s.dependencies[name]; ok {
+			dependency.validate(object, valuePath, violations)
+		}
+		matched := false
+		if property, ok := s.properties[name]; ok {
+			property.validate(object[name], childPath, violations)
+			matched = true
+		}
+		for _, pattern := range s.patternProperties {
+			if pattern.pattern.MatchString(name) {
+				pattern.schema.validate(object[name], childPath, violations)
+				matched = true
+			}
+		}
+		if !matched && s.additionalProperties != nil {
+			if s.additionalProperties.neverValid {
+				*violations = append(*violations, ConfigViolation{Path: childPath, Message: "is not a known property"})
+			} else {
+				s.additionalProperties.validate(object[name], childPath, violations)
+			}
+		}
+	}
+}
+
+func (s *jsonSchema) validateArray(array []interface{}, valuePath string, violations *[]ConfigViolation,
+	report func(format string, args ...interface{})) {
+	if s.minItems != nil && len(array) < *s.minItems {
+		report("must have at least %d items", *s.minItems)
+	}
+	if s.maxItems != nil && len(array) > *s.maxItems {
+		report("must have at most %d items", *s.maxItems)
+	}
+	for i, item := range array {
+		itemPath := fmt.Sprintf("%s[%d]", valuePath, i)
+		if s.items != nil {
+			s.items.validate(item, itemPath, violations)
+		} else if i < len(s.tupleItems) {
+			s.tupleItems[i].validate(item, itemPath, violations)
+		} else if s.tupleItems != nil && s.additionalItems != nil {
+			if s.additionalItems.neverValid {
+				*violations = append(*violations, ConfigViolation{Path: itemPath, Message: "is not allowed"})
+			} else {
+				s.additionalItems.validate(item, itemPath, violations)
+			}
+		}
+	}
+	if s.contains != nil {
+		found := false
+		for _, item := range array {
+			if s.contains.isValid(item) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report("must contain an item matching the schema of contains")
+		}
+	}
+	if s.uniqueItems {
+		for i := 1; i < len(array); i++ {
+			for j := 0; j < i; j++ {
+				if jsonEqual(array[i], array[j]) {
+					report("items [%d] and [%d] must be unique", j, i)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *jsonSchema) validateNumber(number json.Number, report func(format string, args ...interface{})) {
+	value, err := number.Float64()
+	if err != nil {
+		report("invalid number %s", number)
+		return
+	}
+	if s.minimum != nil && value < *s.minimum {
+		report("must be at least %v", *s.minimum)
+	}
+	if s.maximum != nil && value > *s.maximum {
+		report("must be at most %v", *s.maximum)
+	}
+	if s.exclusiveMinimum != nil && value <= *s.exclusiveMinimum {
+		report("must be greater than %v", *s.exclusiveMinimum)
+	}
+	if s.exclusiveMaximum != nil && value >= *s.exclusiveMaximum {
+		report("must be less than %v", *s.exclusiveMaximum)
+	}
+	if s.multipleOf != nil {
+		quotient := value / *s.multipleOf
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			report("must be a multiple of %v", *s.multipleOf)
+		}
+	}
+}
+
+func joinJsonPath(parent, name string) string {
+	if len(parent) == 0 {
+		return name
+	}
+	return parent + "." + name
+}
+
+func jsonTypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case json.Number:
+		if number, err := v.Float64(); err == nil && number == math.Trunc(number) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func matchesJsonType(valueType string, types []string) bool {
+	for _, t := range types {
+		if t == valueType || (t == "number" && valueType == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonEqual compares two json values, numbers being equal by value, e.g. 1 and 1.0.
+func jsonEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		af, aErr := av.Float64()
+		bf, bErr := bv.Float64()
+		if aErr != nil || bErr != nil {
+			return av == bv
+		}
+		return af == bf
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for key, value := range av {
+			other, ok := bv[key]
+			if !ok || !jsonEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+func marshalJsonValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
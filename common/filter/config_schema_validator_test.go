@@ -0,0 +1,184 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAppSchema = `{
+  "type": "object",
+  "required": ["name", "port"],
+  "additionalProperties": false,
+  "properties": {
+    "name": {"type": "string", "minLength": 1, "pattern": "^[a-z-]+$"},
+    "port": {"type": "integer", "minimum": 1, "maximum": 65535},
+    "ratio": {"type": "number", "exclusiveMaximum": 1, "multipleOf": 0.25},
+    "mode": {"enum": ["active", "standby"]},
+    "hosts": {"type": "array", "items": {"$ref": "#/definitions/host"}, "minItems": 1, "uniqueItems": true},
+    "node": {"$ref": "#/definitions/node"}
+  },
+  "definitions": {
+    "host": {"type": "string", "not": {"const": "localhost"}},
+    "node": {
+      "type": "object",
+      "properties": {"child": {"$ref": "#/definitions/node"}, "id": {"oneOf": [{"type": "string"}, {"type": "integer"}]}}
+    }
+  }
+}`
+
+func newTestSchemaValidator(t *testing.T) *JsonSchemaValidator {
+	validator := NewJsonSchemaValidator()
+	assert.Nil(t, validator.RegisterSchema("app-*", testAppSchema))
+	return validator
+}
+
+func validateSchema(validator *JsonSchemaValidator, dataId, content string) *ConfigValidationError {
+	return AsConfigValidationError(validator.Validate(&vo.ConfigParam{DataId: dataId, Group: "DEFAULT_GROUP",
+		Content: content}))
+}
+
+func violationPaths(err *ConfigValidationError) map[string]string {
+	paths := make(map[string]string)
+	for _, violation := range err.Violations {
+		paths[violation.Path] = violation.Message
+	}
+	return paths
+}
+
+func TestJsonSchemaValidator_Valid(t *testing.T) {
+	validator := newTestSchemaValidator(t)
+	assert.Nil(t, validateSchema(validator, "app-a.json",
+		`{"name": "app", "port": 8080, "ratio": 0.5, "mode": "active", "hosts": ["a", "b"],
+		"node": {"id": 1, "child": {"id": "x", "child": {}}}}`))
+	// yaml, properties and toml are validated as the equivalent json
+	assert.Nil(t, validateSchema(validator, "app-b.yaml", "name: app\nport: 8080\nhosts:\n  - a\n"))
+	assert.Nil(t, validateSchema(validator, "app-c.properties", "name=app\nport=8080\n"))
+	assert.Nil(t, validateSchema(validator, "app-d.toml", "name = \"app\"\nport = 8080\n"))
+	// configs matching no pattern are not validated
+	assert.Nil(t, validateSchema(validator, "other.json", "{}"))
+}
+
+func TestJsonSchemaValidator_Violations(t *testing.T) {
+	validator := newTestSchemaValidator(t)
+	err := validateSchema(validator, "app-a.json",
+		`{"name": "App", "ratio": 1, "mode": "idle", "hosts": ["a", "localhost", "a"], "extra": true,
+		"node": {"child": {"id": 1.5}}}`)
+	assert.NotNil(t, err)
+	assert.Equal(t, JsonSchemaValidatorName, err.Validator)
+	paths := violationPaths(err)
+	assert.Equal(t, "is required", paths["port"])
+	assert.Contains(t, paths["name"], "must match pattern")
+	assert.Contains(t, paths["ratio"], "must be less than 1")
+	assert.Contains(t, paths["mode"], "must be one of")
+	assert.Contains(t, paths["hosts[1]"], "must not match")
+	assert.Contains(t, paths["hosts"], "must be unique")
+	assert.Equal(t, "is not a known property", paths["extra"])
+	assert.Contains(t, paths["node.child.id"], "matched 0")
+
+	err = validateSchema(validator, "app-b.yaml", "name: app\nport: http\n")
+	assert.Equal(t, "expected integer, got string", violationPaths(err)["port"])
+	err = validateSchema(validator, "app-b.yaml", "name: app\nport: 0\n")
+	assert.Equal(t, "must be at least 1", violationPaths(err)["port"])
+	err = validateSchema(validator, "app-b.yaml", "name: app\nport: 70000\n")
+	assert.Equal(t, "must be at most 65535", violationPaths(err)["port"])
+
+	// content that doesn't decode is rejected as a whole
+	err = validateSchema(validator, "app-a.json", "{")
+	assert.NotNil(t, err)
+	assert.Equal(t, "", err.Violations[0].Path)
+	err = validateSchema(validator, "app-a.txt", "name")
+	assert.Contains(t, err.Error(), "can not be validated against a schema")
+}
+
+func TestJsonSchemaValidator_SeveralSchemas(t *testing.T) {
+	validator := newTestSchemaValidator(t)
+	assert.Nil(t, validator.RegisterSchema("*.json", `{"anyOf": [{"required": ["name"]}, {"required": ["id"]}]}`))
+	assert.Nil(t, validateSchema(validator, "other.json", `{"id": 1}`))
+	err := validateSchema(validator, "other.json", `{}`)
+	assert.Contains(t, err.Error(), "anyOf")
+	err = validateSchema(validator, "app-a.json", `{"port": 1}`)
+	assert.Len(t, err.Violations, 2)
+}
+
+func TestJsonSchemaValidator_RegisterInvalid(t *testing.T) {
+	validator := NewJsonSchemaValidator()
+	assert.NotNil(t, validator.RegisterSchema("[", `{}`))
+	assert.NotNil(t, validator.RegisterSchema("*", `{`))
+	assert.NotNil(t, validator.RegisterSchema("*", `"string"`))
+	assert.NotNil(t, validator.RegisterSchema("*", `{"type": 1}`))
+	assert.NotNil(t, validator.RegisterSchema("*", `{"minLength": -1}`))
+	assert.NotNil(t, validator.RegisterSchema("*", `{"pattern": "("}`))
+	assert.NotNil(t, validator.RegisterSchema("*", `{"$ref": "#/definitions/missing"}`))
+	assert.NotNil(t, validator.RegisterSchema("*", `{"$ref": "http://example.com/schema.json"}`))
+	assert.Nil(t, validator.RegisterSchema("*", `true`))
+}
+
+func TestJsonSchemaValidator_ApplicatorKeywords(t *testing.T) {
+	cases := []struct {
+		name    string
+		schema  string
+		valid   []string
+		invalid []string
+	}{
+		{"IfThenElse", `{"if": {"properties": {"a": {"const": 1}}}, "then": {"required": ["b"]}, "else": {"required": ["c"]}}`,
+			[]string{`{"a": 1, "b": 2}`, `{"a": 2, "c": 3}`}, []string{`{"a": 1}`, `{"a": 2}`}},
+		{"Contains", `{"contains": {"const": 5}}`, []string{`[1, 5]`, `{}`}, []string{`[1, 2]`, `[]`}},
+		{"PropertyNames", `{"propertyNames": {"pattern": "^[a-z]+$"}}`, []string{`{"abc": 1}`}, []string{`{"Abc": 1}`}},
+		{"DependenciesRequired", `{"dependencies": {"tls": ["cert", "key"]}}`,
+			[]string{`{"tls": true, "cert": "c", "key": "k"}`, `{"cert": "c"}`}, []string{`{"tls": true, "cert": "c"}`}},
+		{"DependenciesSchema", `{"dependencies": {"tls": {"properties": {"port": {"const": 443}}}}}`,
+			[]string{`{"tls": true, "port": 443}`, `{"port": 80}`}, []string{`{"tls": true, "port": 80}`}},
+		{"AdditionalItems", `{"items": [{"type": "string"}], "additionalItems": false}`,
+			[]string{`["x"]`, `[]`}, []string{`["x", 1]`}},
+		{"AdditionalItemsSchema", `{"items": [{"type": "string"}], "additionalItems": {"type": "integer"}}`,
+			[]string{`["x", 1, 2]`}, []string{`["x", "y"]`}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			validator := NewJsonSchemaValidator()
+			assert.Nil(t, validator.RegisterSchema("*.json", c.schema))
+			for _, content := range c.valid {
+				assert.Nil(t, validateSchema(validator, "app.json", content), content)
+			}
+			for _, content := range c.invalid {
+				assert.NotNil(t, validateSchema(validator, "app.json", content), content)
+			}
+		})
+	}
+}
+
+func TestJsonSchemaValidator_UnsupportedKeywords(t *testing.T) {
+	validator := NewJsonSchemaValidator()
+	for _, schema := range []string{
+		`{"format": "email"}`,
+		`{"unevaluatedProperties": false}`,
+		`{"properties": {"a": {"dependentRequired": {"b": ["c"]}}}}`,
+		`{"definitions": {"unused": {"typo": 1}}}`,
+		`{"if": {"contentSchema": {}}}`,
+	} {
+		err := validator.RegisterSchema("*.json", schema)
+		assert.NotNil(t, err, schema)
+		assert.Contains(t, err.Error(), "is not supported", schema)
+	}
+	// annotations are accepted
+	assert.Nil(t, validator.RegisterSchema("*.json",
+		`{"$schema": "http://json-schema.org/draft-07/schema#", "title": "app", "description": "d", "default": {}}`))
+}
@@ -0,0 +1,272 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	nacos_inner_encryption "github.com/nacos-group/nacos-sdk-go/v2/common/encryption"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+)
+
+const (
+	SecretFilterName = "configSecretFilter"
+	// secretFilterOrder places the filter after the decryption and decompression and before the placeholder
+	// resolution of the content being read
+	secretFilterOrder = 75
+
+	secretReferencePrefix = "${secret:"
+
+	KmsSecretScheme  = "kms"
+	FileSecretScheme = "file"
+
+	defaultSecretCacheTtl        = 5 * time.Minute
+	defaultSecretCacheMaxEntries = 1000
+)
+
+// SecretResolver resolves the secret references of its scheme, e.g. ${secret:kms:<ciphertext>} is resolved by the
+// resolver of the scheme "kms" with "<ciphertext>" as reference.
+type SecretResolver interface {
+	Scheme() string
+	Resolve(reference string) (string, error)
+}
+
+type kmsSecretResolver struct {
+	kmsClient nacos_inner_encryption.KmsClient
+}
+
+// NewKmsSecretResolver creates a resolver of ${secret:kms:<ciphertext>}, the ciphertext being decrypted by kmsClient,
+// e.g. the one created by encryption.NewKmsClient.
+func NewKmsSecretResolver(kmsClient nacos_inner_encryption.KmsClient) SecretResolver {
+	return &kmsSecretResolver{kmsClient: kmsClient}
+}
+
+func (r *kmsSecretResolver) Scheme() string {
+	return KmsSecretScheme
+}
+
+func (r *kmsSecretResolver) Resolve(reference string) (string, error) {
+	return r.kmsClient.Decrypt(reference)
+}
+
+type fileSecretResolver struct {
+	allowedDirs []string
+}
+
+// NewFileSecretResolver creates a resolver of ${secret:file:/run/secrets/db}, the secret being the content of the
+// file without its trailing line break. The path must be absolute, and within one of allowedDirs if any is given.
+func NewFileSecretResolver(allowedDirs ...string) SecretResolver {
+	r := &fileSecretResolver{}
+	for _, dir := range allowedDirs {
+		r.allowedDirs = append(r.allowedDirs, filepath.Clean(dir))
+	}
+	return r
+}
+
+func (r *fileSecretResolver) Scheme() string {
+	return FileSecretScheme
+}
+
+func (r *fileSecretResolver) Resolve(reference string) (string, error) {
+	if !filepath.IsAbs(reference) {
+		return "", errors.Errorf("secret file %s is not an absolute path", reference)
+	}
+	file := filepath.Clean(reference)
+	if len(r.allowedDirs) > 0 {
+		allowed := false
+		for _, dir := range r.allowedDirs {
+			if strings.HasPrefix(file, dir+string(filepath.Separator)) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return "", errors.Errorf("secret file %s is not in the allowed directories", reference)
+		}
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+type SecretOption func(*ConfigSecretFilter)
+
+// WithSecretResolver resolves the references of the scheme of resolver with it, replacing the resolver registered
+// for the same scheme.
+func WithSecretResolver(resolver SecretResolver) SecretOption {
+	return func(f *ConfigSecretFilter) {
+		f.resolvers[resolver.Scheme()] = resolver
+	}
+}
+
+// WithSecretCacheTtl sets how long a resolved secret is kept, 5 minutes by default, 0 resolves every read.
+func WithSecretCacheTtl(ttl time.Duration) SecretOption {
+	return func(f *ConfigSecretFilter) {
+		f.cacheTtl = ttl
+	}
+}
+
+// WithSecretCacheMaxEntries sets the number of resolved secrets kept, 1000 by default.
+func WithSecretCacheMaxEntries(maxEntries int) SecretOption {
+	return func(f *ConfigSecretFilter) {
+		f.cacheMaxEntries = maxEntries
+	}
+}
+
+// ConfigSecretFilter replaces the secret references of the content read, e.g. ${secret:kms:<ciphertext>} or
+// ${secret:file:/run/secrets/db}, with the secrets resolved by the resolver of their scheme. The content published
+// and the snapshot keep the references, the secrets are only in the content returned to the application.
+// "$${secret:" isn't resolved, it's a literal "${secret:" for the placeholder filter. A reference that can't be
+// resolved fails the read.
+type ConfigSecretFilter struct {
+	resolvers       map[string]SecretResolver
+	cacheTtl        time.Duration
+	cacheMaxEntries int
+	now             func() time.Time
+	mux             sync.Mutex
+	cache           map[string]secretCacheEntry
+}
+
+type secretCacheEntry struct {
+	value   string
+	expires time.Time
+}
+
+func NewConfigSecretFilter(opts ...SecretOption) (*ConfigSecretFilter, error) {
+	f := &ConfigSecretFilter{
+		resolvers:       make(map[string]SecretResolver),
+		cacheTtl:        defaultSecretCacheTtl,
+		cacheMaxEntries: defaultSecretCacheMaxEntries,
+		now:             time.Now,
+		cache:           make(map[string]secretCacheEntry),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if len(f.resolvers) == 0 {
+		return nil, errors.New("at least one secret resolver is required")
+	}
+	return f, nil
+}
+
+func (f *ConfigSecretFilter) DoFilter(param *vo.ConfigParam) error {
+	if param.UsageType != vo.ResponseType || !strings.Contains(param.Content, secretReferencePrefix) {
+		return nil
+	}
+	content, err := f.resolve(param.Content)
+	if err != nil {
+		return errors.Wrapf(err, "resolve secrets of dataId=%s, group=%s failed", param.DataId, param.Group)
+	}
+	param.Content = content
+	return nil
+}
+
+func (f *ConfigSecretFilter) GetOrder() int {
+	return secretFilterOrder
+}
+
+func (f *ConfigSecretFilter) GetFilterName() string {
+	return SecretFilterName
+}
+
+// ClearCache drops the resolved secrets, so they are resolved again on the next read.
+func (f *ConfigSecretFilter) ClearCache() {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.cache = make(map[string]secretCacheEntry)
+}
+
+func (f *ConfigSecretFilter) resolve(content string) (string, error) {
+	var result strings.Builder
+	for {
+		start := strings.Index(content, secretReferencePrefix)
+		if start < 0 {
+			result.WriteString(content)
+			return result.String(), nil
+		}
+		end := strings.Index(content[start:], "}")
+		if end < 0 {
+			return "", errors.Errorf("secret reference %s is not closed", content[start:])
+		}
+		end += start
+		if start > 0 && content[start-1] == '$' {
+			result.WriteString(content[:end+1])
+			content = content[end+1:]
+			continue
+		}
+		result.WriteString(content[:start])
+		scheme, reference, found := strings.Cut(content[start+len(secretReferencePrefix):end], ":")
+		if !found || len(reference) == 0 {
+			return "", errors.Errorf("secret reference %s has no scheme", content[start:end+1])
+		}
+		secret, err := f.resolveSecret(scheme, reference)
+		if err != nil {
+			return "", errors.Wrapf(err, "resolve secret of scheme %s", scheme)
+		}
+		result.WriteString(secret)
+		content = content[end+1:]
+	}
+}
+
+func (f *ConfigSecretFilter) resolveSecret(scheme, reference string) (string, error) {
+	resolver, ok := f.resolvers[scheme]
+	if !ok {
+		return "", errors.Errorf("no secret resolver of scheme %s", scheme)
+	}
+	key := scheme + ":" + reference
+	if f.cacheTtl > 0 {
+		f.mux.Lock()
+		entry, cached := f.cache[key]
+		f.mux.Unlock()
+		if cached && f.now().Before(entry.expires) {
+			return entry.value, nil
+		}
+	}
+	secret, err := resolver.Resolve(reference)
+	if err != nil {
+		return "", err
+	}
+	if f.cacheTtl > 0 {
+		f.mux.Lock()
+		f.putCache(key, secretCacheEntry{value: secret, expires: f.now().Add(f.cacheTtl)})
+		f.mux.Unlock()
+	}
+	return secret, nil
+}
+
+// putCache adds entry, the expired entries are dropped once the cache is full, all of them if none is expired
+func (f *ConfigSecretFilter) putCache(key string, entry secretCacheEntry) {
+	if _, ok := f.cache[key]; !ok && len(f.cache) >= f.cacheMaxEntries {
+		now := f.now()
+		for k, v := range f.cache {
+			if !now.Before(v.expires) {
+				delete(f.cache, k)
+			}
+		}
+		if len(f.cache) >= f.cacheMaxEntries {
+			f.cache = make(map[string]secretCacheEntry)
+		}
+	}
+	f.cache[key] = entry
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	nacos_inner_encryption "github.com/nacos-group/nacos-sdk-go/v2/common/encryption"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingSecretResolver resolves "name" to "secret-name"
+type countingSecretResolver struct {
+	scheme   string
+	resolved map[string]int
+}
+
+func (r *countingSecretResolver) Scheme() string {
+	return r.scheme
+}
+
+func (r *countingSecretResolver) Resolve(reference string) (string, error) {
+	if reference == "missing" {
+		return "", errors.New("secret not found")
+	}
+	r.resolved[reference]++
+	return "secret-" + reference, nil
+}
+
+// fakeSecretKmsClient decrypts by removing the "enc-" prefix
+type fakeSecretKmsClient struct {
+	nacos_inner_encryption.KmsClient
+}
+
+func (c *fakeSecretKmsClient) Decrypt(cipherContent string) (string, error) {
+	if !strings.HasPrefix(cipherContent, "enc-") {
+		return "", errors.New("invalid ciphertext")
+	}
+	return strings.TrimPrefix(cipherContent, "enc-"), nil
+}
+
+func doSecretFilter(f *ConfigSecretFilter, content string) (string, error) {
+	param := &vo.ConfigParam{DataId: "app.properties", Group: "APP", Content: content, UsageType: vo.ResponseType}
+	err := f.DoFilter(param)
+	return param.Content, err
+}
+
+func TestConfigSecretFilter(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "db")
+	assert.Nil(t, os.WriteFile(secretFile, []byte("file-secret\r\n"), 0600))
+	f, err := NewConfigSecretFilter(
+		WithSecretResolver(NewKmsSecretResolver(&fakeSecretKmsClient{})),
+		WithSecretResolver(NewFileSecretResolver(dir)))
+	assert.Nil(t, err)
+
+	cases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"Kms", "password=${secret:kms:enc-kms-secret}", "password=kms-secret"},
+		{"File", "password=${secret:file:" + secretFile + "}", "password=file-secret"},
+		{"Many", "a=${secret:kms:enc-a}\nb=${secret:kms:enc-b}", "a=a\nb=b"},
+		{"Escape", "literal=$${secret:kms:enc-a}", "literal=$${secret:kms:enc-a}"},
+		{"Placeholder", "host=${db.host}", "host=${db.host}"},
+		{"NoReference", "a=b", "a=b"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			content, err := doSecretFilter(f, c.content)
+			assert.Nil(t, err)
+			assert.Equal(t, c.expected, content)
+		})
+	}
+
+	for _, content := range []string{
+		"password=${secret:kms:plain}",
+		"password=${secret:vault:enc-a}",
+		"password=${secret:kms}",
+		"password=${secret:kms:enc-a",
+		"password=${secret:file:db}",
+		"password=${secret:file:" + filepath.Join(dir, "..", "etc", "passwd") + "}",
+		"password=${secret:file:" + filepath.Join(dir, "missing") + "}",
+	} {
+		_, err = doSecretFilter(f, content)
+		assert.NotNil(t, err, content)
+	}
+
+	param := &vo.ConfigParam{DataId: "app.properties", Content: "password=${secret:kms:enc-a}", UsageType: vo.RequestType}
+	assert.Nil(t, f.DoFilter(param))
+	assert.Equal(t, "password=${secret:kms:enc-a}", param.Content)
+
+	_, err = NewConfigSecretFilter()
+	assert.NotNil(t, err)
+}
+
+func TestConfigSecretFilter_Cache(t *testing.T) {
+	resolver := &countingSecretResolver{scheme: "vault", resolved: map[string]int{}}
+	f, err := NewConfigSecretFilter(WithSecretResolver(resolver), WithSecretCacheTtl(time.Minute), WithSecretCacheMaxEntries(2))
+	assert.Nil(t, err)
+	now := time.Unix(0, 0)
+	f.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		content, err := doSecretFilter(f, "a=${secret:vault:a}\nb=${secret:vault:a}")
+		assert.Nil(t, err)
+		assert.Equal(t, "a=secret-a\nb=secret-a", content)
+	}
+	assert.Equal(t, 1, resolver.resolved["a"])
+	_, err = doSecretFilter(f, "a=${secret:vault:missing}")
+	assert.NotNil(t, err)
+
+	now = now.Add(time.Minute)
+	_, _ = doSecretFilter(f, "a=${secret:vault:a}")
+	assert.Equal(t, 2, resolver.resolved["a"])
+	f.ClearCache()
+	_, _ = doSecretFilter(f, "a=${secret:vault:a}")
+	assert.Equal(t, 3, resolver.resolved["a"])
+
+	// the cache is bounded
+	_, _ = doSecretFilter(f, "b=${secret:vault:b}\nc=${secret:vault:c}")
+	assert.LessOrEqual(t, len(f.cache), 2)
+
+	uncached, _ := NewConfigSecretFilter(WithSecretResolver(resolver), WithSecretCacheTtl(0))
+	_, _ = doSecretFilter(uncached, "a=${secret:vault:b}")
+	_, _ = doSecretFilter(uncached, "a=${secret:vault:b}")
+	assert.Equal(t, 3, resolver.resolved["b"])
+}
+
+func TestConfigSecretFilter_WithPlaceholders(t *testing.T) {
+	secretFilter, err := NewConfigSecretFilter(WithSecretResolver(NewKmsSecretResolver(&fakeSecretKmsClient{})))
+	assert.Nil(t, err)
+	chain := NewConfigFilterChainManager()
+	assert.Nil(t, RegisterConfigFilterToChain(chain, NewConfigPlaceholderFilter(newMockPlaceholderSource(nil))))
+	assert.Nil(t, RegisterConfigFilterToChain(chain, secretFilter))
+
+	// the secrets are resolved before the placeholders, which unescape the literal ones
+	param := &vo.ConfigParam{DataId: "app.properties", Group: "APP", UsageType: vo.ResponseType,
+		Content: "user=nacos\npassword=${secret:kms:enc-pwd}\nurl=${user}@db\nliteral=$${secret:kms:enc-pwd}"}
+	assert.Nil(t, chain.DoFilters(param))
+	assert.Equal(t, "user=nacos\npassword=pwd\nurl=nacos@db\nliteral=${secret:kms:enc-pwd}", param.Content)
+}
@@ -0,0 +1,415 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+)
+
+const (
+	SignatureFilterName = "configSignatureFilter"
+	// SignatureTrailerPrefix starts the last line of the content published, which holds the signature
+	SignatureTrailerPrefix = "#nacos-signature:"
+
+	SignatureAlgorithmEd25519         = "ed25519"
+	SignatureAlgorithmEcdsaP256Sha256 = "ecdsa-p256-sha256"
+	SignatureAlgorithmEcdsaP384Sha384 = "ecdsa-p384-sha384"
+	SignatureAlgorithmEcdsaP521Sha512 = "ecdsa-p521-sha512"
+
+	// signatureFilterOrder places the filter closest to the server, the content is signed once it's compressed and
+	// encrypted, and verified before it's decrypted
+	signatureFilterOrder = -50
+
+	signatureVersion = "nacos-config-signature/v1"
+)
+
+var (
+	UnsignedConfigError         = errors.New("config is not signed")
+	UntrustedSignatureKeyError  = errors.New("config is signed by an untrusted key")
+	InvalidConfigSignatureError = errors.New("config signature is invalid")
+	ExpiredConfigSignatureError = errors.New("config signature is expired")
+)
+
+// SignatureVerifyMode tells what happens to the configs failing the verification.
+type SignatureVerifyMode int
+
+const (
+	// SignatureReject fails the read of the config, its listeners aren't called
+	SignatureReject SignatureVerifyMode = iota
+	// SignatureFlag reports the config to the violation listener and reads it anyway
+	SignatureFlag
+)
+
+// ConfigSignatureError is returned when a config read fails the verification of its signature.
+type ConfigSignatureError struct {
+	DataId string
+	Group  string
+	KeyId  string
+	Reason error
+}
+
+func (e *ConfigSignatureError) Error() string {
+	if len(e.KeyId) == 0 {
+		return fmt.Sprintf("config dataId=%s, group=%s: %v", e.DataId, e.Group, e.Reason)
+	}
+	return fmt.Sprintf("config dataId=%s, group=%s, keyId=%s: %v", e.DataId, e.Group, e.KeyId, e.Reason)
+}
+
+func (e *ConfigSignatureError) Unwrap() error {
+	return e.Reason
+}
+
+// AsConfigSignatureError returns the *ConfigSignatureError err wraps, nil if it doesn't.
+func AsConfigSignatureError(err error) *ConfigSignatureError {
+	var signatureErr *ConfigSignatureError
+	if errors.As(err, &signatureErr) {
+		return signatureErr
+	}
+	return nil
+}
+
+type configSignature struct {
+	KeyId     string `json:"keyId"`
+	Algorithm string `json:"alg"`
+	Timestamp int64  `json:"ts"`
+	Signature string `json:"sig"`
+}
+
+type SignatureOption func(*ConfigSignatureFilter) error
+
+// WithSigningKey signs the content published with key, an ed25519.PrivateKey or an *ecdsa.PrivateKey of P-256,
+// P-384 or P-521. keyId tells the readers which of their trusted keys verifies it.
+func WithSigningKey(keyId string, key crypto.Signer) SignatureOption {
+	return func(f *ConfigSignatureFilter) error {
+		if err := checkSignatureKeyId(keyId); err != nil {
+			return err
+		}
+		algorithm, err := signatureAlgorithmOf(key.Public())
+		if err != nil {
+			return err
+		}
+		f.signingKeyId, f.signingKey, f.signingAlgorithm = keyId, key, algorithm
+		return nil
+	}
+}
+
+// WithTrustedKey verifies the configs signed by keyId with key, an ed25519.PublicKey or an *ecdsa.PublicKey.
+func WithTrustedKey(keyId string, key crypto.PublicKey) SignatureOption {
+	return func(f *ConfigSignatureFilter) error {
+		if err := checkSignatureKeyId(keyId); err != nil {
+			return err
+		}
+		if _, err := signatureAlgorithmOf(key); err != nil {
+			return err
+		}
+		f.trustedKeys[keyId] = key
+		return nil
+	}
+}
+
+// WithSignatureVerifyMode sets what happens to the configs failing the verification, SignatureReject by default.
+func WithSignatureVerifyMode(mode SignatureVerifyMode) SignatureOption {
+	return func(f *ConfigSignatureFilter) error {
+		f.mode = mode
+		return nil
+	}
+}
+
+// WithSignatureViolationListener calls onViolation with the configs failing the verification, whatever the mode.
+func WithSignatureViolationListener(onViolation func(param *vo.ConfigParam, err *ConfigSignatureError)) SignatureOption {
+	return func(f *ConfigSignatureFilter) error {
+		f.onViolation = onViolation
+		return nil
+	}
+}
+
+// WithSignatureDataIdPatterns signs and verifies only the dataIds matching one of patterns, the patterns are matched
+// with path.Match. All the configs are by default.
+func WithSignatureDataIdPatterns(patterns ...string) SignatureOption {
+	return func(f *ConfigSignatureFilter) error {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return errors.Wrapf(err, "invalid dataId pattern [%s]", pattern)
+			}
+		}
+		f.patterns = append(f.patterns, patterns...)
+		return nil
+	}
+}
+
+// WithSignatureMaxAge fails the verification of the signatures made more than maxAge ago, so an old signed
+// content can't be published again. Signatures don't expire by default.
+func WithSignatureMaxAge(maxAge time.Duration) SignatureOption {
+	return func(f *ConfigSignatureFilter) error {
+		f.maxAge = maxAge
+		return nil
+	}
+}
+
+// ConfigSignatureFilter signs the content published with its signing key, over the dataId, group, content and the
+// time of signing, and appends the signature to the content as its last line. The content read is verified with the
+// trusted keys and the signature removed. Configs that are unsigned, signed by an untrusted key or modified since
+// they were signed are rejected or flagged depending on the verify mode.
+type ConfigSignatureFilter struct {
+	signingKeyId     string
+	signingKey       crypto.Signer
+	signingAlgorithm string
+	trustedKeys      map[string]crypto.PublicKey
+	mode             SignatureVerifyMode
+	onViolation      func(param *vo.ConfigParam, err *ConfigSignatureError)
+	patterns         []string
+	maxAge           time.Duration
+	now              func() time.Time
+}
+
+func NewConfigSignatureFilter(opts ...SignatureOption) (*ConfigSignatureFilter, error) {
+	f := &ConfigSignatureFilter{
+		trustedKeys: make(map[string]crypto.PublicKey),
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		if err := opt(f); err != nil {
+			return nil, err
+		}
+	}
+	if f.signingKey == nil && len(f.trustedKeys) == 0 {
+		return nil, errors.New("either a signing key or a trusted key is required")
+	}
+	return f, nil
+}
+
+func (f *ConfigSignatureFilter) DoFilter(param *vo.ConfigParam) error {
+	if len(param.Content) == 0 || !f.isSigned(param.DataId) {
+		return nil
+	}
+	if param.UsageType == vo.RequestType {
+		if f.signingKey == nil {
+			return nil
+		}
+		content, _, _ := splitSignatureTrailer(param.Content)
+		signed, err := f.sign(param.DataId, param.Group, content)
+		if err != nil {
+			return errors.Wrapf(err, "sign config dataId=%s", param.DataId)
+		}
+		param.Content = signed
+	} else if param.UsageType == vo.ResponseType {
+		content, keyId, err := f.verify(param.DataId, param.Group, param.Content)
+		if err != nil {
+			signatureErr := &ConfigSignatureError{DataId: param.DataId, Group: param.Group, KeyId: keyId, Reason: err}
+			if f.onViolation != nil {
+				f.onViolation(param, signatureErr)
+			}
+			if f.mode == SignatureReject {
+				return signatureErr
+			}
+			logger.Warnf("%v, it's read anyway", signatureErr)
+		}
+		param.Content = content
+	}
+	return nil
+}
+
+// CanPublish reports whether the content of dataId published through the filter carries the signature its readers
+// expect, false if dataId is signed but the filter has no signing key.
+func (f *ConfigSignatureFilter) CanPublish(dataId string) bool {
+	return f.signingKey != nil || !f.isSigned(dataId)
+}
+
+func (f *ConfigSignatureFilter) GetOrder() int {
+	return signatureFilterOrder
+}
+
+func (f *ConfigSignatureFilter) GetFilterName() string {
+	return SignatureFilterName
+}
+
+func (f *ConfigSignatureFilter) isSigned(dataId string) bool {
+	if len(f.patterns) == 0 {
+		return true
+	}
+	for _, pattern := range f.patterns {
+		if matched, _ := path.Match(pattern, dataId); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *ConfigSignatureFilter) sign(dataId, group, content string) (string, error) {
+	signature := configSignature{KeyId: f.signingKeyId, Algorithm: f.signingAlgorithm, Timestamp: f.now().UnixMilli()}
+	digest, hash := signatureDigest(signature, dataId, group, content)
+	sig, err := f.signingKey.Sign(rand.Reader, digest, hash)
+	if err != nil {
+		return "", err
+	}
+	signature.Signature = base64.StdEncoding.EncodeToString(sig)
+	trailer, err := json.Marshal(signature)
+	if err != nil {
+		return "", err
+	}
+	return content + "\n" + SignatureTrailerPrefix + base64.StdEncoding.EncodeToString(trailer), nil
+}
+
+// verify returns the content without its signature, along with the key that signed it
+func (f *ConfigSignatureFilter) verify(dataId, group, signed string) (string, string, error) {
+	content, trailer, found := splitSignatureTrailer(signed)
+	if !found {
+		return content, "", UnsignedConfigError
+	}
+	signature := configSignature{}
+	data, err := base64.StdEncoding.DecodeString(trailer)
+	if err != nil || json.Unmarshal(data, &signature) != nil {
+		return content, "", InvalidConfigSignatureError
+	}
+	key, ok := f.trustedKeys[signature.KeyId]
+	if !ok {
+		return content, signature.KeyId, UntrustedSignatureKeyError
+	}
+	if algorithm, _ := signatureAlgorithmOf(key); algorithm != signature.Algorithm {
+		return content, signature.KeyId, InvalidConfigSignatureError
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature.Signature)
+	if err != nil {
+		return content, signature.KeyId, InvalidConfigSignatureError
+	}
+	digest, _ := signatureDigest(signature, dataId, group, content)
+	var valid bool
+	switch publicKey := key.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(publicKey, digest, sig)
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(publicKey, digest, sig)
+	}
+	if !valid {
+		return content, signature.KeyId, InvalidConfigSignatureError
+	}
+	if f.maxAge > 0 && f.now().Sub(time.UnixMilli(signature.Timestamp)) > f.maxAge {
+		return content, signature.KeyId, ExpiredConfigSignatureError
+	}
+	return content, signature.KeyId, nil
+}
+
+// splitSignatureTrailer splits the signed content into the content and the encoded signature of its last line
+func splitSignatureTrailer(signed string) (string, string, bool) {
+	index := strings.LastIndex(signed, "\n"+SignatureTrailerPrefix)
+	if index < 0 || strings.Contains(signed[index+1:], "\n") {
+		return signed, "", false
+	}
+	return signed[:index], signed[index+1+len(SignatureTrailerPrefix):], true
+}
+
+// signatureDigest returns what is signed, the message itself for ed25519 and its hash for ecdsa. The dataId and the
+// group can't hold a line break, so the fields are told apart by the lines.
+func signatureDigest(signature configSignature, dataId, group, content string) ([]byte, crypto.Hash) {
+	if len(group) == 0 {
+		group = constant.DEFAULT_GROUP
+	}
+	message := []byte(strings.Join([]string{signatureVersion, signature.Algorithm, signature.KeyId, dataId, group,
+		strconv.FormatInt(signature.Timestamp, 10), content}, "\n"))
+	switch signature.Algorithm {
+	case SignatureAlgorithmEcdsaP256Sha256:
+		digest := sha256.Sum256(message)
+		return digest[:], crypto.SHA256
+	case SignatureAlgorithmEcdsaP384Sha384:
+		digest := sha512.Sum384(message)
+		return digest[:], crypto.SHA384
+	case SignatureAlgorithmEcdsaP521Sha512:
+		digest := sha512.Sum512(message)
+		return digest[:], crypto.SHA512
+	}
+	return message, crypto.Hash(0)
+}
+
+func signatureAlgorithmOf(key crypto.PublicKey) (string, error) {
+	switch publicKey := key.(type) {
+	case ed25519.PublicKey:
+		return SignatureAlgorithmEd25519, nil
+	case *ecdsa.PublicKey:
+		switch publicKey.Curve {
+		case elliptic.P256():
+			return SignatureAlgorithmEcdsaP256Sha256, nil
+		case elliptic.P384():
+			return SignatureAlgorithmEcdsaP384Sha384, nil
+		case elliptic.P521():
+			return SignatureAlgorithmEcdsaP521Sha512, nil
+		}
+	}
+	return "", errors.Errorf("unsupported signature key %T, ed25519 or ecdsa P-256, P-384, P-521 is required", key)
+}
+
+func checkSignatureKeyId(keyId string) error {
+	if len(keyId) == 0 || strings.ContainsAny(keyId, "\r\n") {
+		return errors.Errorf("invalid signature keyId [%s]", keyId)
+	}
+	return nil
+}
+
+// ParseSignaturePrivateKey parses the PKCS #8 PEM of an ed25519 or ecdsa private key for WithSigningKey.
+func ParseSignaturePrivateKey(pemData []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("unsupported signature key %T", key)
+	}
+	if _, err = signatureAlgorithmOf(signer.Public()); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// ParseSignaturePublicKey parses the PKIX PEM of an ed25519 or ecdsa public key for WithTrustedKey.
+func ParseSignaturePublicKey(pemData []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = signatureAlgorithmOf(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
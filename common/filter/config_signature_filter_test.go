@@ -0,0 +1,212 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSignatureFilter(t *testing.T, opts ...SignatureOption) *ConfigSignatureFilter {
+	f, err := NewConfigSignatureFilter(opts...)
+	assert.Nil(t, err)
+	return f
+}
+
+func signatureFilterConfig(f *ConfigSignatureFilter, dataId, group, content string, usageType vo.UsageType) (string, error) {
+	param := &vo.ConfigParam{DataId: dataId, Group: group, Content: content, UsageType: usageType}
+	err := f.DoFilter(param)
+	return param.Content, err
+}
+
+func TestConfigSignatureFilter_RoundTrip(t *testing.T) {
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	verifier := newTestSignatureFilter(t, WithTrustedKey("ed", edPublic), WithTrustedKey("p256", &p256.PublicKey),
+		WithTrustedKey("p384", &p384.PublicKey))
+
+	for _, signer := range []*ConfigSignatureFilter{
+		newTestSignatureFilter(t, WithSigningKey("ed", edPrivate)),
+		newTestSignatureFilter(t, WithSigningKey("p256", p256)),
+		newTestSignatureFilter(t, WithSigningKey("p384", p384)),
+	} {
+		t.Run(signer.signingAlgorithm, func(t *testing.T) {
+			signed, err := signatureFilterConfig(signer, "flags.json", "APP", `{"newCheckout": true}`, vo.RequestType)
+			assert.Nil(t, err)
+			assert.True(t, strings.HasPrefix(signed, `{"newCheckout": true}`+"\n"+SignatureTrailerPrefix))
+
+			content, err := signatureFilterConfig(verifier, "flags.json", "APP", signed, vo.ResponseType)
+			assert.Nil(t, err)
+			assert.Equal(t, `{"newCheckout": true}`, content)
+
+			// signing again replaces the signature
+			resigned, err := signatureFilterConfig(signer, "flags.json", "APP", signed, vo.RequestType)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, strings.Count(resigned, SignatureTrailerPrefix))
+		})
+	}
+}
+
+func TestConfigSignatureFilter_Violations(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	_, otherPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	now := time.Unix(1700000000, 0)
+	signer := newTestSignatureFilter(t, WithSigningKey("release", private))
+	signer.now = func() time.Time { return now }
+	verifier := newTestSignatureFilter(t, WithTrustedKey("release", public), WithSignatureMaxAge(time.Hour))
+	verifier.now = func() time.Time { return now }
+
+	signed, _ := signatureFilterConfig(signer, "flags.json", "", "enabled=true", vo.RequestType)
+	// an empty group is the default one
+	_, err := signatureFilterConfig(verifier, "flags.json", "DEFAULT_GROUP", signed, vo.ResponseType)
+	assert.Nil(t, err)
+
+	impostor := newTestSignatureFilter(t, WithSigningKey("release", otherPrivate))
+	forged, _ := signatureFilterConfig(impostor, "flags.json", "", "enabled=true", vo.RequestType)
+	untrusted := newTestSignatureFilter(t, WithSigningKey("dev", otherPrivate))
+	untrustedSigned, _ := signatureFilterConfig(untrusted, "flags.json", "", "enabled=true", vo.RequestType)
+
+	cases := []struct {
+		name    string
+		dataId  string
+		group   string
+		content string
+		reason  error
+	}{
+		{"Unsigned", "flags.json", "", "enabled=true", UnsignedConfigError},
+		{"Modified", "flags.json", "", strings.Replace(signed, "true", "false", 1), InvalidConfigSignatureError},
+		{"OtherDataId", "routes.json", "", signed, InvalidConfigSignatureError},
+		{"OtherGroup", "flags.json", "APP", signed, InvalidConfigSignatureError},
+		{"Forged", "flags.json", "", forged, InvalidConfigSignatureError},
+		{"UntrustedKey", "flags.json", "", untrustedSigned, UntrustedSignatureKeyError},
+		{"Garbled", "flags.json", "", "enabled=true\n" + SignatureTrailerPrefix + "!!", InvalidConfigSignatureError},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := signatureFilterConfig(verifier, c.dataId, c.group, c.content, vo.ResponseType)
+			signatureErr := AsConfigSignatureError(err)
+			assert.NotNil(t, signatureErr)
+			assert.ErrorIs(t, err, c.reason)
+			assert.Equal(t, c.dataId, signatureErr.DataId)
+		})
+	}
+
+	now = now.Add(2 * time.Hour)
+	_, err = signatureFilterConfig(verifier, "flags.json", "", signed, vo.ResponseType)
+	assert.ErrorIs(t, err, ExpiredConfigSignatureError)
+}
+
+func TestConfigSignatureFilter_Flag(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	var flagged []*ConfigSignatureError
+	verifier := newTestSignatureFilter(t, WithTrustedKey("release", public), WithSignatureVerifyMode(SignatureFlag),
+		WithSignatureViolationListener(func(param *vo.ConfigParam, err *ConfigSignatureError) {
+			flagged = append(flagged, err)
+		}))
+	signer := newTestSignatureFilter(t, WithSigningKey("release", private))
+	signed, _ := signatureFilterConfig(signer, "flags.json", "APP", "enabled=true", vo.RequestType)
+
+	content, err := signatureFilterConfig(verifier, "flags.json", "APP", "enabled=true", vo.ResponseType)
+	assert.Nil(t, err)
+	assert.Equal(t, "enabled=true", content)
+	content, err = signatureFilterConfig(verifier, "flags.json", "APP", strings.Replace(signed, "true", "false", 1), vo.ResponseType)
+	assert.Nil(t, err)
+	assert.Equal(t, "enabled=false", content)
+	content, err = signatureFilterConfig(verifier, "flags.json", "APP", signed, vo.ResponseType)
+	assert.Nil(t, err)
+	assert.Equal(t, "enabled=true", content)
+
+	assert.Len(t, flagged, 2)
+	assert.ErrorIs(t, flagged[0], UnsignedConfigError)
+	assert.ErrorIs(t, flagged[1], InvalidConfigSignatureError)
+	assert.Equal(t, "release", flagged[1].KeyId)
+}
+
+func TestConfigSignatureFilter_DataIds(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	f := newTestSignatureFilter(t, WithSigningKey("release", private), WithTrustedKey("release", public),
+		WithSignatureDataIdPatterns("flags-*.json"))
+
+	content, err := signatureFilterConfig(f, "app.yaml", "APP", "a: b", vo.RequestType)
+	assert.Nil(t, err)
+	assert.Equal(t, "a: b", content)
+	content, err = signatureFilterConfig(f, "app.yaml", "APP", "a: b", vo.ResponseType)
+	assert.Nil(t, err)
+	assert.Equal(t, "a: b", content)
+	_, err = signatureFilterConfig(f, "flags-checkout.json", "APP", "{}", vo.ResponseType)
+	assert.ErrorIs(t, err, UnsignedConfigError)
+
+	// a verifying client publishes unsigned content
+	verifier := newTestSignatureFilter(t, WithTrustedKey("release", public))
+	content, err = signatureFilterConfig(verifier, "flags-checkout.json", "APP", "{}", vo.RequestType)
+	assert.Nil(t, err)
+	assert.Equal(t, "{}", content)
+}
+
+func TestConfigSignatureFilter_Options(t *testing.T) {
+	_, err := NewConfigSignatureFilter()
+	assert.NotNil(t, err)
+	p224, _ := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	_, err = NewConfigSignatureFilter(WithSigningKey("p224", p224))
+	assert.NotNil(t, err)
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	_, err = NewConfigSignatureFilter(WithTrustedKey("a\nb", public))
+	assert.NotNil(t, err)
+	_, err = NewConfigSignatureFilter(WithTrustedKey("release", public), WithSignatureDataIdPatterns("["))
+	assert.NotNil(t, err)
+
+	privateDer, _ := x509.MarshalPKCS8PrivateKey(private)
+	signer, err := ParseSignaturePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDer}))
+	assert.Nil(t, err)
+	assert.Equal(t, private, signer)
+	publicDer, _ := x509.MarshalPKIXPublicKey(public)
+	parsed, err := ParseSignaturePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer}))
+	assert.Nil(t, err)
+	assert.Equal(t, public, parsed)
+	_, err = ParseSignaturePublicKey([]byte("not a pem"))
+	assert.NotNil(t, err)
+}
+
+func TestConfigSignatureFilter_WithEncryption(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	handler := &wrappingHandler{}
+	chain := NewConfigFilterChainManager()
+	assert.Nil(t, RegisterConfigFilterToChain(chain, NewDefaultConfigEncryptionFilter(handler)))
+	assert.Nil(t, RegisterConfigFilterToChain(chain,
+		newTestSignatureFilter(t, WithSigningKey("release", private), WithTrustedKey("release", public))))
+
+	// the content is signed once it's encrypted, and verified before it's decrypted
+	param := &vo.ConfigParam{DataId: "cipher-kms-aes-256-flags.json", Group: "APP", Content: "enabled=true", UsageType: vo.RequestType}
+	assert.Nil(t, chain.DoFilters(param))
+	assert.Equal(t, []string{"enabled=true"}, handler.encrypted)
+	assert.True(t, strings.HasPrefix(param.Content, "enc(enabled=true)\n"+SignatureTrailerPrefix))
+
+	param.UsageType = vo.ResponseType
+	assert.Nil(t, chain.DoFilters(param))
+	assert.Equal(t, "enabled=true", param.Content)
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/encoding"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	SyntaxValidatorName = "configSyntaxValidator"
+)
+
+// IConfigValidator checks the content of a config before it is published, the content is the one given to
+// PublishConfig, before any filter has run on it.
+type IConfigValidator interface {
+	// Validate returns a *ConfigValidationError to reject the publish
+	Validate(param *vo.ConfigParam) error
+	GetValidatorName() string
+}
+
+// IConfigValidatorChain is implemented by the filter chains that run validators on the request path.
+type IConfigValidatorChain interface {
+	AddValidator(IConfigValidator) error
+	GetValidators() []IConfigValidator
+}
+
+func RegisterConfigValidatorToChain(chain IConfigFilterChain, validator IConfigValidator) error {
+	validatorChain, ok := chain.(IConfigValidatorChain)
+	if !ok {
+		return errors.New("the filter chain doesn't support validators")
+	}
+	return validatorChain.AddValidator(validator)
+}
+
+// ConfigViolation is a single problem found by a validator.
+type ConfigViolation struct {
+	// Path of the offending value, keys are joined with "." and list elements are addressed as key[index],
+	// empty for the whole content
+	Path string
+	// Line of the offending content, 0 if unknown
+	Line    int
+	Message string
+}
+
+func (v ConfigViolation) String() string {
+	var location string
+	if len(v.Path) > 0 {
+		location = v.Path + ": "
+	} else if v.Line > 0 {
+		location = "line " + strconv.Itoa(v.Line) + ": "
+	}
+	return location + v.Message
+}
+
+// ConfigValidationError is returned by PublishConfig when a validator rejects the content.
+type ConfigValidationError struct {
+	DataId     string
+	Group      string
+	Validator  string
+	Violations []ConfigViolation
+}
+
+func (e *ConfigValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.String())
+	}
+	return fmt.Sprintf("config dataId=%s, group=%s is rejected by %s: %s", e.DataId, e.Group, e.Validator,
+		strings.Join(messages, "; "))
+}
+
+// AsConfigValidationError returns the *ConfigValidationError err wraps, nil if it doesn't.
+func AsConfigValidationError(err error) *ConfigValidationError {
+	var validationErr *ConfigValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr
+	}
+	return nil
+}
+
+type configSyntaxValidator struct{}
+
+// NewConfigSyntaxValidator creates a validator rejecting content that doesn't parse as its type, which is
+// param.Type, or the extension of param.DataId when Type is empty. Json, yaml, properties, toml and xml are checked,
+// other types are accepted as they are.
+func NewConfigSyntaxValidator() IConfigValidator {
+	return &configSyntaxValidator{}
+}
+
+func (v *configSyntaxValidator) GetValidatorName() string {
+	return SyntaxValidatorName
+}
+
+func (v *configSyntaxValidator) Validate(param *vo.ConfigParam) error {
+	configType := encoding.ResolveConfigType(param.Type, param.DataId)
+	violation := checkConfigSyntax(configType, param.Content)
+	if violation == nil {
+		return nil
+	}
+	violation.Message = "invalid " + configType + ": " + violation.Message
+	return &ConfigValidationError{
+		DataId:     param.DataId,
+		Group:      param.Group,
+		Validator:  SyntaxValidatorName,
+		Violations: []ConfigViolation{*violation},
+	}
+}
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+func checkConfigSyntax(configType, content string) *ConfigViolation {
+	switch configType {
+	case encoding.ConfigTypeJson:
+		var value interface{}
+		err := json.Unmarshal([]byte(content), &value)
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return &ConfigViolation{Line: lineOfOffset(content, syntaxErr.Offset), Message: err.Error()}
+		}
+		if err != nil {
+			return &ConfigViolation{Message: err.Error()}
+		}
+	case encoding.ConfigTypeYaml:
+		var value yaml.Node
+		if err := yaml.Unmarshal([]byte(content), &value); err != nil {
+			violation := &ConfigViolation{Message: err.Error()}
+			if match := yamlErrorLine.FindStringSubmatch(err.Error()); match != nil {
+				violation.Line, _ = strconv.Atoi(match[1])
+			}
+			return violation
+		}
+	case encoding.ConfigTypeProperties:
+		if _, err := encoding.ParseProperties(content); err != nil {
+			return &ConfigViolation{Message: err.Error()}
+		}
+	case encoding.ConfigTypeToml:
+		var value map[string]interface{}
+		if _, err := toml.Decode(content, &value); err != nil {
+			violation := &ConfigViolation{Message: err.Error()}
+			var parseErr toml.ParseError
+			if errors.As(err, &parseErr) {
+				violation.Line = parseErr.Position.Line
+			}
+			return violation
+		}
+	case encoding.ConfigTypeXml:
+		decoder := xml.NewDecoder(strings.NewReader(content))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				violation := &ConfigViolation{Message: err.Error()}
+				var syntaxErr *xml.SyntaxError
+				if errors.As(err, &syntaxErr) {
+					violation.Line = syntaxErr.Line
+				}
+				return violation
+			}
+		}
+	}
+	return nil
+}
+
+func lineOfOffset(content string, offset int64) int {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	return bytes.Count([]byte(content[:offset]), []byte("\n")) + 1
+}
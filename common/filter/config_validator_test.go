@@ -0,0 +1,127 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func validateSyntax(dataId, configType, content string) *ConfigValidationError {
+	err := NewConfigSyntaxValidator().Validate(&vo.ConfigParam{DataId: dataId, Group: "DEFAULT_GROUP", Type: configType,
+		Content: content})
+	return AsConfigValidationError(err)
+}
+
+func TestConfigSyntaxValidator_Valid(t *testing.T) {
+	assert.Nil(t, validateSyntax("app.json", "", `{"a": [1, 2]}`))
+	assert.Nil(t, validateSyntax("app.yaml", "", "a:\n  b: 1\n"))
+	assert.Nil(t, validateSyntax("app", "properties", "a.b=1\nc=2\n"))
+	assert.Nil(t, validateSyntax("app.toml", "", "[a]\nb = 1\n"))
+	assert.Nil(t, validateSyntax("app.xml", "", "<a><b>1</b></a>"))
+	// text and unknown types are not checked
+	assert.Nil(t, validateSyntax("app", "", "{not json"))
+	assert.Nil(t, validateSyntax("app.json", "html", "{not json"))
+}
+
+func TestConfigSyntaxValidator_Invalid(t *testing.T) {
+	err := validateSyntax("app.json", "", "{\n  \"a\": 1,\n  \"b\" 2\n}")
+	assert.NotNil(t, err)
+	assert.Equal(t, "app.json", err.DataId)
+	assert.Equal(t, SyntaxValidatorName, err.Validator)
+	assert.Equal(t, 3, err.Violations[0].Line)
+	assert.Contains(t, err.Error(), "invalid json")
+
+	err = validateSyntax("app.yml", "", "a: 1\nb: [1, 2\n")
+	assert.NotNil(t, err)
+	assert.True(t, err.Violations[0].Line > 0)
+
+	err = validateSyntax("app.toml", "", "a = 1\nb = 1 2\nc = 3\n")
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, err.Violations[0].Line)
+
+	err = validateSyntax("app.xml", "", "<a>\n<b></a>")
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, err.Violations[0].Line)
+
+	// the type given overrides the extension of the dataId
+	assert.NotNil(t, validateSyntax("app.txt", "json", "{"))
+}
+
+type rejectingValidator struct {
+	validated []string
+}
+
+func (v *rejectingValidator) Validate(param *vo.ConfigParam) error {
+	v.validated = append(v.validated, param.Content)
+	return &ConfigValidationError{DataId: param.DataId, Validator: v.GetValidatorName(),
+		Violations: []ConfigViolation{{Message: "rejected"}}}
+}
+
+func (v *rejectingValidator) GetValidatorName() string {
+	return "rejectingValidator"
+}
+
+type prefixFilter struct{}
+
+func (f *prefixFilter) DoFilter(param *vo.ConfigParam) error {
+	param.Content = "FILTERED:" + param.Content
+	return nil
+}
+
+func (f *prefixFilter) GetOrder() int {
+	return 0
+}
+
+func (f *prefixFilter) GetFilterName() string {
+	return "prefixFilter"
+}
+
+func TestDefaultConfigFilterChainManager_Validators(t *testing.T) {
+	chain := NewConfigFilterChainManager()
+	assert.Nil(t, RegisterConfigFilterToChain(chain, &prefixFilter{}))
+	validator := &rejectingValidator{}
+	assert.Nil(t, RegisterConfigValidatorToChain(chain, validator))
+	// registering the same validator twice is a no-op
+	assert.Nil(t, RegisterConfigValidatorToChain(chain, validator))
+	assert.Len(t, chain.(IConfigValidatorChain).GetValidators(), 1)
+
+	// the validators see the content before the filters and stop the publish
+	param := &vo.ConfigParam{DataId: "app", Content: "plain", UsageType: vo.RequestType}
+	err := chain.DoFilters(param)
+	assert.NotNil(t, AsConfigValidationError(errors.Wrap(err, "publish")))
+	assert.Equal(t, []string{"plain"}, validator.validated)
+	assert.Equal(t, "plain", param.Content)
+
+	// the content being read is not validated
+	param = &vo.ConfigParam{DataId: "app", Content: "plain", UsageType: vo.ResponseType}
+	assert.Nil(t, chain.DoFilters(param))
+	assert.Equal(t, "FILTERED:plain", param.Content)
+	assert.Len(t, validator.validated, 1)
+}
+
+type plainChain struct {
+	IConfigFilterChain
+}
+
+func TestRegisterConfigValidatorToChain_Unsupported(t *testing.T) {
+	assert.NotNil(t, RegisterConfigValidatorToChain(plainChain{}, NewConfigSyntaxValidator()))
+	assert.NotNil(t, RegisterConfigValidatorToChain(NewConfigFilterChainManager(), nil))
+}
@@ -17,13 +17,14 @@
 package http_agent
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 )
 
-func delete(client *http.Client, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
+func delete(ctx context.Context, client *http.Client, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
 	if len(params) > 0 {
 		if !strings.HasSuffix(path, "?") {
 			path = path + "?"
@@ -36,7 +37,7 @@ func delete(client *http.Client, path string, header http.Header, timeoutMs uint
 		}
 	}
 	client.Timeout = time.Millisecond * time.Duration(timeoutMs)
-	request, errNew := http.NewRequest(http.MethodDelete, path, nil)
+	request, errNew := http.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if errNew != nil {
 		err = errNew
 		return
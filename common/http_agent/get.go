@@ -17,13 +17,14 @@
 package http_agent
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 )
 
-func get(client *http.Client, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
+func get(ctx context.Context, client *http.Client, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
 	if !strings.Contains(path, "?") {
 		path = path + "?"
 	}
@@ -39,7 +40,7 @@ func get(client *http.Client, path string, header http.Header, timeoutMs uint64,
 	}
 
 	client.Timeout = time.Millisecond * time.Duration(timeoutMs)
-	request, errNew := http.NewRequest(http.MethodGet, path, nil)
+	request, errNew := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if errNew != nil {
 		err = errNew
 		return
@@ -17,6 +17,7 @@
 package http_agent
 
 import (
+	"context"
 	"io"
 	"net/http"
 
@@ -38,7 +39,7 @@ func (agent *HttpAgent) Get(path string, header http.Header, timeoutMs uint64,
 	if err != nil {
 		return nil, err
 	}
-	return get(client, path, header, timeoutMs, params)
+	return get(context.Background(), client, path, header, timeoutMs, params)
 }
 
 func (agent *HttpAgent) RequestOnlyResult(method string, path string, header http.Header, timeoutMs uint64, params map[string]string) string {
@@ -98,13 +99,37 @@ func (agent *HttpAgent) Request(method string, path string, header http.Header,
 	}
 	return
 }
+
+// RequestWithContext behaves like Request, but the underlying http request is bound to ctx,
+// so cancelling ctx aborts the call.
+func (agent *HttpAgent) RequestWithContext(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
+	client, err := agent.createClient()
+	if err != nil {
+		return nil, err
+	}
+	switch method {
+	case http.MethodGet:
+		return get(ctx, client, path, header, timeoutMs, params)
+	case http.MethodPost:
+		return post(ctx, client, path, header, timeoutMs, params)
+	case http.MethodPut:
+		return put(ctx, client, path, header, timeoutMs, params)
+	case http.MethodDelete:
+		return delete(ctx, client, path, header, timeoutMs, params)
+	default:
+		err = errors.New("not available method")
+		logger.Errorf("request method[%s], path[%s],header:[%s],params:[%s], not available method ", method, path, util.ToJsonString(header), util.ToJsonString(params))
+	}
+	return
+}
+
 func (agent *HttpAgent) Post(path string, header http.Header, timeoutMs uint64,
 	params map[string]string) (response *http.Response, err error) {
 	client, err := agent.createClient()
 	if err != nil {
 		return nil, err
 	}
-	return post(client, path, header, timeoutMs, params)
+	return post(context.Background(), client, path, header, timeoutMs, params)
 }
 func (agent *HttpAgent) Delete(path string, header http.Header, timeoutMs uint64,
 	params map[string]string) (response *http.Response, err error) {
@@ -112,7 +137,7 @@ func (agent *HttpAgent) Delete(path string, header http.Header, timeoutMs uint64
 	if err != nil {
 		return nil, err
 	}
-	return delete(client, path, header, timeoutMs, params)
+	return delete(context.Background(), client, path, header, timeoutMs, params)
 }
 func (agent *HttpAgent) Put(path string, header http.Header, timeoutMs uint64,
 	params map[string]string) (response *http.Response, err error) {
@@ -120,7 +145,7 @@ func (agent *HttpAgent) Put(path string, header http.Header, timeoutMs uint64,
 	if err != nil {
 		return nil, err
 	}
-	return put(client, path, header, timeoutMs, params)
+	return put(context.Background(), client, path, header, timeoutMs, params)
 }
 
 func (agent *HttpAgent) createClient() (*http.Client, error) {
@@ -16,6 +16,7 @@
 package http_agent
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"time"
@@ -23,11 +24,11 @@ import (
 	"github.com/nacos-group/nacos-sdk-go/v2/util"
 )
 
-func post(client *http.Client, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
+func post(ctx context.Context, client *http.Client, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
 	client.Timeout = time.Millisecond * time.Duration(timeoutMs)
 
 	body := util.GetUrlFormedMap(params)
-	request, errNew := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	request, errNew := http.NewRequestWithContext(ctx, http.MethodPost, path, strings.NewReader(body))
 	if errNew != nil {
 		err = errNew
 		return
@@ -27,11 +27,15 @@ var (
 		Name: "nacos_client_request",
 		Help: "nacos_client_request",
 	}, []string{"module", "method", "url", "code"})
+	counterMonitorVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nacos_client_counter",
+		Help: "nacos_client_counter",
+	}, []string{"module", "name"})
 )
 
 // register collectors vec
 func init() {
-	prometheus.MustRegister(gaugeMonitorVec, histogramMonitorVec)
+	prometheus.MustRegister(gaugeMonitorVec, histogramMonitorVec, counterMonitorVec)
 }
 
 // get gauge with labels and use gaugeMonitorVec
@@ -51,6 +55,39 @@ func GetListenConfigCountMonitor() prometheus.Gauge {
 	return GetGaugeWithLabels("listenConfig", "listenConfigCount")
 }
 
+func GetListenerPendingMonitor() prometheus.Gauge {
+	return GetGaugeWithLabels("listener", "listenerPendingCount")
+}
+
+// get counter with labels and use counterMonitorVec
+func GetCounterWithLabels(labels ...string) prometheus.Counter {
+	return counterMonitorVec.WithLabelValues(labels...)
+}
+
+func GetListenerPanicMonitor() prometheus.Counter {
+	return GetCounterWithLabels("listener", "listenerPanicCount")
+}
+
+func GetListenerCoalescedMonitor() prometheus.Counter {
+	return GetCounterWithLabels("listener", "listenerCoalescedCount")
+}
+
+func GetListenerQueueFullMonitor() prometheus.Counter {
+	return GetCounterWithLabels("listener", "listenerQueueFullCount")
+}
+
+func GetConfigRateLimitedMonitor(scope string) prometheus.Counter {
+	return GetCounterWithLabels("rateLimit", scope+"LimitedCount")
+}
+
+func GetConfigRateLimitWaitMonitor() prometheus.Counter {
+	return GetCounterWithLabels("rateLimit", "waitCount")
+}
+
+func GetKmsDataKeyCacheMonitor(name string) prometheus.Counter {
+	return GetCounterWithLabels("kmsDataKeyCache", name+"Count")
+}
+
 // get histogram with labels and use histogramMonitorVec
 func GetHistogramWithLabels(labels ...string) prometheus.Observer {
 	return histogramMonitorVec.WithLabelValues(labels...)
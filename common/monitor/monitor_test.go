@@ -37,3 +37,19 @@ func TestHistorgam(t *testing.T) {
 		assert.NotNil(t, monitor)
 	})
 }
+
+func TestCounter(t *testing.T) {
+	t.Run("getCounter", func(t *testing.T) {
+		// will panic because of wrong label count.
+		defer func() {
+			r := recover()
+			assert.NotNil(t, r)
+		}()
+		GetCounterWithLabels("counter", "test_counter", "should_not_exist_label")
+	})
+
+	t.Run("listenerPanicMonitor", func(t *testing.T) {
+		monitor := GetListenerPanicMonitor()
+		assert.NotNil(t, monitor)
+	})
+}
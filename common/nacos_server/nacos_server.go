@@ -96,7 +96,7 @@ func NewNacosServerWithRamCredentialProvider(ctx context.Context, serverList []c
 	return &ns, nil
 }
 
-func (server *NacosServer) callConfigServer(api string, params map[string]string, newHeaders map[string]string,
+func (server *NacosServer) callConfigServer(ctx context.Context, api string, params map[string]string, newHeaders map[string]string,
 	method string, curServer string, contextPath string, timeoutMS uint64) (result string, err error) {
 	start := time.Now()
 	if contextPath == "" {
@@ -124,7 +124,7 @@ func (server *NacosServer) callConfigServer(api string, params map[string]string
 	headers["Content-Type"] = []string{"application/x-www-form-urlencoded;charset=utf-8"}
 
 	var response *http.Response
-	response, err = server.httpAgent.Request(method, url, headers, timeoutMS, params)
+	response, err = server.httpAgent.RequestWithContext(ctx, method, url, headers, timeoutMS, params)
 	monitor.GetConfigRequestMonitor(method, url, util.GetStatusCode(response)).Observe(float64(time.Now().Nanosecond() - start.Nanosecond()))
 	if err != nil {
 		return
@@ -144,7 +144,7 @@ func (server *NacosServer) callConfigServer(api string, params map[string]string
 	}
 }
 
-func (server *NacosServer) callServer(api string, params map[string]string, method string, curServer string, contextPath string) (result string, err error) {
+func (server *NacosServer) callServer(ctx context.Context, api string, params map[string]string, method string, curServer string, contextPath string) (result string, err error) {
 	start := time.Now()
 	if contextPath == "" {
 		contextPath = constant.WEB_CONTEXT
@@ -166,7 +166,7 @@ func (server *NacosServer) callServer(api string, params map[string]string, meth
 	headers["Content-Type"] = []string{"application/x-www-form-urlencoded;charset=utf-8"}
 
 	var response *http.Response
-	response, err = server.httpAgent.Request(method, url, headers, server.timeoutMs, params)
+	response, err = server.httpAgent.RequestWithContext(ctx, method, url, headers, server.timeoutMs, params)
 	if err != nil {
 		return
 	}
@@ -187,6 +187,11 @@ func (server *NacosServer) callServer(api string, params map[string]string, meth
 }
 
 func (server *NacosServer) ReqConfigApi(api string, params map[string]string, headers map[string]string, method string, timeoutMS uint64) (string, error) {
+	return server.ReqConfigApiWithContext(context.Background(), api, params, headers, method, timeoutMS)
+}
+
+// ReqConfigApiWithContext calls the config api like ReqConfigApi, giving up the remaining retries once ctx is done.
+func (server *NacosServer) ReqConfigApiWithContext(ctx context.Context, api string, params map[string]string, headers map[string]string, method string, timeoutMS uint64) (string, error) {
 	srvs := server.serverList
 	if srvs == nil || len(srvs) == 0 {
 		return "", errors.New("server list is empty")
@@ -199,20 +204,26 @@ func (server *NacosServer) ReqConfigApi(api string, params map[string]string, he
 	var result string
 	if len(srvs) == 1 {
 		for i := 0; i < constant.REQUEST_DOMAIN_RETRY_TIME; i++ {
-			result, err = server.callConfigServer(api, params, headers, method, getAddress(srvs[0]), srvs[0].ContextPath, timeoutMS)
+			result, err = server.callConfigServer(ctx, api, params, headers, method, getAddress(srvs[0]), srvs[0].ContextPath, timeoutMS)
 			if err == nil {
 				return result, nil
 			}
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
 			logger.Errorf("api<%s>,method:<%s>, params:<%s>, call domain error:<%+v> , result:<%s>", api, method, util.ToJsonString(params), err, result)
 		}
 	} else {
 		index := rand.Intn(len(srvs))
 		for i := 1; i <= len(srvs); i++ {
 			curServer := srvs[index]
-			result, err = server.callConfigServer(api, params, headers, method, getAddress(curServer), curServer.ContextPath, timeoutMS)
+			result, err = server.callConfigServer(ctx, api, params, headers, method, getAddress(curServer), curServer.ContextPath, timeoutMS)
 			if err == nil {
 				return result, nil
 			}
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
 			logger.Errorf("[ERROR] api<%s>,method:<%s>, params:<%s>, call domain error:<%+v> , result:<%s> \n", api, method, util.ToJsonString(params), err, result)
 			index = (index + i) % len(srvs)
 		}
@@ -221,6 +232,11 @@ func (server *NacosServer) ReqConfigApi(api string, params map[string]string, he
 }
 
 func (server *NacosServer) ReqApi(api string, params map[string]string, method string, config constant.ClientConfig) (string, error) {
+	return server.ReqApiWithContext(context.Background(), api, params, method, config)
+}
+
+// ReqApiWithContext calls the naming api like ReqApi, giving up the remaining retries once ctx is done.
+func (server *NacosServer) ReqApiWithContext(ctx context.Context, api string, params map[string]string, method string, config constant.ClientConfig) (string, error) {
 	srvs := server.serverList
 	if srvs == nil || len(srvs) == 0 {
 		return "", errors.New("server list is empty")
@@ -233,20 +249,26 @@ func (server *NacosServer) ReqApi(api string, params map[string]string, method s
 	var result string
 	if len(srvs) == 1 {
 		for i := 0; i < constant.REQUEST_DOMAIN_RETRY_TIME; i++ {
-			result, err = server.callServer(api, params, method, getAddress(srvs[0]), srvs[0].ContextPath)
+			result, err = server.callServer(ctx, api, params, method, getAddress(srvs[0]), srvs[0].ContextPath)
 			if err == nil {
 				return result, nil
 			}
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
 			logger.Errorf("api<%s>,method:<%s>, params:<%s>, call domain error:<%+v> , result:<%s>", api, method, util.ToJsonString(params), err, result)
 		}
 	} else {
 		index := rand.Intn(len(srvs))
 		for i := 1; i <= len(srvs); i++ {
 			curServer := srvs[index]
-			result, err = server.callServer(api, params, method, getAddress(curServer), curServer.ContextPath)
+			result, err = server.callServer(ctx, api, params, method, getAddress(curServer), curServer.ContextPath)
 			if err == nil {
 				return result, nil
 			}
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
 			logger.Errorf("api<%s>,method:<%s>, params:<%s>, call domain error:<%+v> , result:<%s>", api, method, util.ToJsonString(params), err, result)
 			index = (index + i) % len(srvs)
 		}
@@ -17,13 +17,15 @@
 package rpc
 
 import (
+	"context"
+
 	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_request"
 	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_response"
 	"google.golang.org/grpc"
 )
 
 type IConnection interface {
-	request(request rpc_request.IRequest, timeoutMills int64, client *RpcClient) (rpc_response.IResponse, error)
+	request(ctx context.Context, request rpc_request.IRequest, timeoutMills int64, client *RpcClient) (rpc_response.IResponse, error)
 	close()
 	getConnectionId() string
 	getServerInfo() ServerInfo
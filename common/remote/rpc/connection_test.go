@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_request"
 	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_response"
 )
@@ -8,7 +9,7 @@ import (
 type MockConnection struct {
 }
 
-func (m *MockConnection) request(request rpc_request.IRequest, timeoutMills int64, client *RpcClient) (rpc_response.IResponse, error) {
+func (m *MockConnection) request(ctx context.Context, request rpc_request.IRequest, timeoutMills int64, client *RpcClient) (rpc_response.IResponse, error) {
 	return nil, nil
 }
 func (m *MockConnection) close() {
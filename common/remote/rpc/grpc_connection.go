@@ -51,9 +51,9 @@ func NewGrpcConnection(serverInfo ServerInfo, connectionId string, conn *grpc.Cl
 		biStreamClient: biStreamClient,
 	}
 }
-func (g *GrpcConnection) request(request rpc_request.IRequest, timeoutMills int64, client *RpcClient) (rpc_response.IResponse, error) {
+func (g *GrpcConnection) request(ctx context.Context, request rpc_request.IRequest, timeoutMills int64, client *RpcClient) (rpc_response.IResponse, error) {
 	p := convertRequest(request)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutMills)*time.Millisecond)
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeoutMills)*time.Millisecond)
 	defer cancel()
 	responsePayload, err := g.client.Request(ctx, p)
 	if err != nil {
@@ -546,7 +546,7 @@ func (r *RpcClient) sendHealthCheck() bool {
 	if conn == nil {
 		return false
 	}
-	response, err := conn.request(rpc_request.NewHealthCheckRequest(),
+	response, err := conn.request(context.Background(), rpc_request.NewHealthCheckRequest(),
 		constant.DEFAULT_TIMEOUT_MILLS, r)
 	if err != nil {
 		logger.Errorf("client sendHealthCheck failed,err=%v", err)
@@ -609,22 +609,38 @@ func (c *ConnectionEvent) toString() string {
 }
 
 func (r *RpcClient) Request(request rpc_request.IRequest, timeoutMills int64) (rpc_response.IResponse, error) {
+	return r.RequestWithContext(context.Background(), request, timeoutMills)
+}
+
+// RequestWithContext sends the request like Request, but stops retrying and returns ctx.Err()
+// as soon as ctx is cancelled or its deadline expires.
+func (r *RpcClient) RequestWithContext(ctx context.Context, request rpc_request.IRequest, timeoutMills int64) (rpc_response.IResponse, error) {
 	retryTimes := 0
 	start := util.CurrentMillis()
 	var currentErr error
 	for retryTimes < constant.REQUEST_DOMAIN_RETRY_TIME && util.CurrentMillis() < start+timeoutMills {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		conn := r.GetCurrentConnection()
 		if conn == nil || !r.IsRunning() {
-			currentErr = waitReconnect(timeoutMills, &retryTimes, request,
+			currentErr = waitReconnect(ctx, timeoutMills, &retryTimes, request,
 				errors.Errorf("client not connected, current status:%s", r.rpcClientStatus.getDesc()))
 			continue
 		}
-		response, err := conn.request(request, timeoutMills, r)
+		response, err := conn.request(ctx, request, timeoutMills, r)
 		if err != nil {
-			currentErr = waitReconnect(timeoutMills, &retryTimes, request, err)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			currentErr = waitReconnect(ctx, timeoutMills, &retryTimes, request, err)
 			continue
 		}
 		if resp, ok := response.(*rpc_response.ErrorResponse); ok {
+			// the server doesn't know this request type, retrying or switching server won't help
+			if resp.GetErrorCode() == constant.NO_HANDLER {
+				return response, nil
+			}
 			if resp.GetErrorCode() == constant.UN_REGISTER {
 				r.mux.Lock()
 				if atomic.CompareAndSwapInt32((*int32)(&r.rpcClientStatus), (int32)(RUNNING), (int32)(UNHEALTHY)) {
@@ -634,7 +650,7 @@ func (r *RpcClient) Request(request rpc_request.IRequest, timeoutMills int64) (r
 				}
 				r.mux.Unlock()
 			}
-			currentErr = waitReconnect(timeoutMills, &retryTimes, request, errors.New(response.GetMessage()))
+			currentErr = waitReconnect(ctx, timeoutMills, &retryTimes, request, errors.New(response.GetMessage()))
 			continue
 		}
 		if response != nil && !response.IsSuccess() {
@@ -644,6 +660,10 @@ func (r *RpcClient) Request(request rpc_request.IRequest, timeoutMills int64) (r
 		return response, nil
 	}
 
+	// the caller gave up, which says nothing about the health of the connection
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if atomic.CompareAndSwapInt32((*int32)(&r.rpcClientStatus), int32(RUNNING), int32(UNHEALTHY)) {
 		r.switchServerAsync(ServerInfo{}, true)
 	}
@@ -653,9 +673,14 @@ func (r *RpcClient) Request(request rpc_request.IRequest, timeoutMills int64) (r
 	return nil, errors.New("request fail, unknown error")
 }
 
-func waitReconnect(timeoutMills int64, retryTimes *int, request rpc_request.IRequest, err error) error {
+func waitReconnect(ctx context.Context, timeoutMills int64, retryTimes *int, request rpc_request.IRequest, err error) error {
 	logger.Errorf("Send request fail, request=%s, body=%s, retryTimes=%v, error=%+v", request.GetRequestType(), request.GetBody(request), *retryTimes, err)
-	time.Sleep(time.Duration(math.Min(100, float64(timeoutMills/3))) * time.Millisecond)
+	timer := time.NewTimer(time.Duration(math.Min(100, float64(timeoutMills/3))) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
 	*retryTimes++
 	return err
 }
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -19,7 +20,7 @@ type mockConn struct {
 	closed     bool
 }
 
-func (m *mockConn) request(request rpc_request.IRequest, timeoutMills int64, client *RpcClient) (rpc_response.IResponse, error) {
+func (m *mockConn) request(ctx context.Context, request rpc_request.IRequest, timeoutMills int64, client *RpcClient) (rpc_response.IResponse, error) {
 	return nil, nil
 }
 func (m *mockConn) close()                    { m.closed = true }
@@ -1,7 +1,33 @@
 package rpc
 
-import "testing"
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/common/remote/rpc/rpc_request"
+	"github.com/stretchr/testify/assert"
+)
 
 func TestHealthCheck(t *testing.T) {
 
 }
+
+func TestRequestWithContext_Cancelled(t *testing.T) {
+	client := &RpcClient{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	response, err := client.RequestWithContext(ctx, rpc_request.NewHealthCheckRequest(), 3000)
+	assert.Nil(t, response)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestRequestWithContext_DeadlineStopsRetry(t *testing.T) {
+	client := &RpcClient{}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.RequestWithContext(ctx, rpc_request.NewHealthCheckRequest(), 10000)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
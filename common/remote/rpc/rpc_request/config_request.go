@@ -116,6 +116,7 @@ func (r *ConfigPublishRequest) GetRequestType() string {
 
 type ConfigRemoveRequest struct {
 	*ConfigRequest
+	Tag string `json:"tag"`
 }
 
 func NewConfigRemoveRequest(group, dataId, tenant string) *ConfigRemoveRequest {
@@ -125,3 +126,67 @@ func NewConfigRemoveRequest(group, dataId, tenant string) *ConfigRemoveRequest {
 func (r *ConfigRemoveRequest) GetRequestType() string {
 	return constant.CONFIG_REMOVE_REQUEST_NAME
 }
+
+// request of watching all configs whose group and dataId match a pattern, supported since nacos 3.0.
+type ConfigFuzzyWatchRequest struct {
+	*Request
+	GroupKeyPattern   string   `json:"groupKeyPattern"`
+	ReceivedGroupKeys []string `json:"receivedGroupKeys"`
+	WatchType         string   `json:"watchType"`
+	IsInitializing    bool     `json:"initializing"`
+	Module            string   `json:"module"`
+}
+
+func NewConfigFuzzyWatchRequest(groupKeyPattern string, receivedGroupKeys []string, watchType string) *ConfigFuzzyWatchRequest {
+	return &ConfigFuzzyWatchRequest{
+		Request:           &Request{Headers: make(map[string]string, 8)},
+		GroupKeyPattern:   groupKeyPattern,
+		ReceivedGroupKeys: receivedGroupKeys,
+		WatchType:         watchType,
+		Module:            "config",
+	}
+}
+
+func (r *ConfigFuzzyWatchRequest) GetRequestType() string {
+	return constant.CONFIG_FUZZY_WATCH_REQUEST_NAME
+}
+
+// pushed by server when a config matching a watched pattern is added or deleted.
+type ConfigFuzzyWatchChangeNotifyRequest struct {
+	*Request
+	GroupKey   string `json:"groupKey"`
+	ChangeType string `json:"changeType"`
+	Module     string `json:"module"`
+}
+
+func NewConfigFuzzyWatchChangeNotifyRequest() *ConfigFuzzyWatchChangeNotifyRequest {
+	return &ConfigFuzzyWatchChangeNotifyRequest{Request: &Request{Headers: make(map[string]string, 8)}, Module: "config"}
+}
+
+func (r *ConfigFuzzyWatchChangeNotifyRequest) GetRequestType() string {
+	return constant.CONFIG_FUZZY_WATCH_CHANGE_NOTIFY_REQUEST_NAME
+}
+
+type FuzzyWatchSyncContext struct {
+	GroupKey    string `json:"groupKey"`
+	ChangedType string `json:"changedType"`
+}
+
+// pushed by server with the full or incremental set of configs matching a watched pattern.
+type ConfigFuzzyWatchSyncRequest struct {
+	*Request
+	GroupKeyPattern string                  `json:"groupKeyPattern"`
+	Contexts        []FuzzyWatchSyncContext `json:"contexts"`
+	SyncType        string                  `json:"syncType"`
+	TotalBatch      int                     `json:"totalBatch"`
+	CurrentBatch    int                     `json:"currentBatch"`
+	Module          string                  `json:"module"`
+}
+
+func NewConfigFuzzyWatchSyncRequest() *ConfigFuzzyWatchSyncRequest {
+	return &ConfigFuzzyWatchSyncRequest{Request: &Request{Headers: make(map[string]string, 8)}, Module: "config"}
+}
+
+func (r *ConfigFuzzyWatchSyncRequest) GetRequestType() string {
+	return constant.CONFIG_FUZZY_WATCH_SYNC_REQUEST_NAME
+}
@@ -35,7 +35,7 @@ type ConfigQueryResponse struct {
 	Md5              string `json:"md5"`
 	LastModified     int64  `json:"lastModified"`
 	IsBeta           bool   `json:"isBeta"`
-	Tag              bool   `json:"tag"`
+	Tag              string `json:"tag"`
 }
 
 func (c *ConfigQueryResponse) GetResponseType() string {
@@ -57,3 +57,27 @@ type ConfigRemoveResponse struct {
 func (c *ConfigRemoveResponse) GetResponseType() string {
 	return "ConfigRemoveResponse"
 }
+
+type ConfigFuzzyWatchResponse struct {
+	*Response
+}
+
+func (c *ConfigFuzzyWatchResponse) GetResponseType() string {
+	return "ConfigFuzzyWatchResponse"
+}
+
+type ConfigFuzzyWatchChangeNotifyResponse struct {
+	*Response
+}
+
+func (c *ConfigFuzzyWatchChangeNotifyResponse) GetResponseType() string {
+	return "ConfigFuzzyWatchChangeNotifyResponse"
+}
+
+type ConfigFuzzyWatchSyncResponse struct {
+	*Response
+}
+
+func (c *ConfigFuzzyWatchSyncResponse) GetResponseType() string {
+	return "ConfigFuzzyWatchSyncResponse"
+}
@@ -146,6 +146,11 @@ func registerClientResponses() {
 	registerClientResponse(func() IResponse {
 		return &ConfigRemoveResponse{Response: &Response{}}
 	})
+
+	//register ConfigFuzzyWatchResponse
+	registerClientResponse(func() IResponse {
+		return &ConfigFuzzyWatchResponse{Response: &Response{}}
+	})
 }
 
 // get grpc response status code with NA default.
@@ -90,6 +90,10 @@ func (m *MockHttpAgent) Put(url string, header http.Header, timeoutMs uint64, pa
 	return m.Request(http.MethodPut, url, header, timeoutMs, params)
 }
 
+func (m *MockHttpAgent) RequestWithContext(ctx context.Context, method string, url string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+	return m.Request(method, url, header, timeoutMs, params)
+}
+
 func TestNacosAuthClient_Login_Success(t *testing.T) {
 	// Setup mock response
 	mockResp := &http.Response{
@@ -4,6 +4,7 @@ module github.com/nacos-group/nacos-sdk-go/v2
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/alibabacloud-go/darabonba-openapi/v2 v2.0.10
 	github.com/alibabacloud-go/kms-20160120/v3 v3.2.3
 	github.com/alibabacloud-go/tea v1.2.2
@@ -25,6 +26,7 @@ require (
 	google.golang.org/protobuf v1.36.5
 	gopkg.in/check.v1 v1.0.0-20200227125254-8fa46927fb4f
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require github.com/golang/protobuf v1.5.4 // indirect
@@ -68,5 +70,4 @@ require (
 	golang.org/x/text v0.21.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -16,7 +16,14 @@
 
 package model
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
 
 type ConfigItem struct {
 	Id      json.Number `param:"id"`
@@ -52,3 +59,276 @@ type ConfigPageResult struct {
 	Message string     `json:"message"`
 	Data    ConfigPage `json:"data"`
 }
+
+type ConfigChangeType string
+
+const (
+	ConfigAdded    ConfigChangeType = "ADDED"
+	ConfigModified ConfigChangeType = "MODIFIED"
+	ConfigDeleted  ConfigChangeType = "DELETED"
+)
+
+// ConfigChangeItem is the change of a single key between two versions of a properties, yaml or json config.
+type ConfigChangeItem struct {
+	Key      string
+	OldValue string
+	NewValue string
+	Type     ConfigChangeType
+}
+
+// ConfigChangeEvent describes a config change pushed to a listener, contents are decrypted.
+type ConfigChangeEvent struct {
+	Namespace    string
+	Group        string
+	DataId       string
+	OldContent   string
+	NewContent   string
+	OldMd5       string
+	NewMd5       string
+	ContentType  string
+	ChangeType   ConfigChangeType
+	ChangedItems []ConfigChangeItem
+	// IsGray is true when NewContent is the beta or gray version served to this client
+	IsGray bool
+}
+
+// ConfigBeta is the beta version of a config, served to the clients listed in BetaIps or matching the gray rule.
+type ConfigBeta struct {
+	Id       json.Number `json:"id"`
+	DataId   string      `json:"dataId"`
+	Group    string      `json:"group"`
+	Tenant   string      `json:"tenant"`
+	Content  string      `json:"content"`
+	Md5      string      `json:"md5"`
+	AppName  string      `json:"appName"`
+	Type     string      `json:"type"`
+	BetaIps  string      `json:"betaIps"`
+	GrayName string      `json:"grayName"`
+	GrayRule string      `json:"grayRule"`
+}
+
+type ConfigBetaResult struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    *ConfigBeta `json:"data"`
+}
+
+// ConfigMetadata is the metadata of a config kept by the server, which a publish overwrites.
+type ConfigMetadata struct {
+	DataId     string `json:"dataId"`
+	Group      string `json:"group"`
+	Tenant     string `json:"tenant"`
+	AppName    string `json:"appName"`
+	Desc       string `json:"desc"`
+	ConfigTags string `json:"configTags"`
+	Type       string `json:"type"`
+}
+
+type ConfigMetadataResult struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    *ConfigMetadata `json:"data"`
+}
+
+// ConfigHistoryItem is one revision of a config. OpType is I, U or D for insert, update and delete,
+// Content is the content the config had before an update or delete and the inserted content otherwise.
+type ConfigHistoryItem struct {
+	Id               json.Number
+	LastId           json.Number
+	DataId           string
+	Group            string
+	Tenant           string
+	AppName          string
+	Content          string
+	Md5              string
+	Type             string
+	EncryptedDataKey string
+	SrcIp            string
+	SrcUser          string
+	OpType           string
+	PublishType      string
+	CreatedTime      time.Time
+	LastModifiedTime time.Time
+}
+
+// UnmarshalJSON accepts the history of the v1 api as well as of the v3 admin api, whose field names and
+// time formats differ.
+func (item *ConfigHistoryItem) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Id               json.Number     `json:"id"`
+		LastId           json.Number     `json:"lastId"`
+		DataId           string          `json:"dataId"`
+		Group            string          `json:"group"`
+		GroupName        string          `json:"groupName"`
+		Tenant           string          `json:"tenant"`
+		NamespaceId      string          `json:"namespaceId"`
+		AppName          string          `json:"appName"`
+		Content          string          `json:"content"`
+		Md5              string          `json:"md5"`
+		Type             string          `json:"type"`
+		EncryptedDataKey string          `json:"encryptedDataKey"`
+		SrcIp            string          `json:"srcIp"`
+		SrcUser          string          `json:"srcUser"`
+		OpType           string          `json:"opType"`
+		PublishType      string          `json:"publishType"`
+		CreatedTime      json.RawMessage `json:"createdTime"`
+		CreateTime       json.RawMessage `json:"createTime"`
+		LastModifiedTime json.RawMessage `json:"lastModifiedTime"`
+		ModifyTime       json.RawMessage `json:"modifyTime"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*item = ConfigHistoryItem{
+		Id:               raw.Id,
+		LastId:           raw.LastId,
+		DataId:           raw.DataId,
+		Group:            firstNonEmpty(raw.Group, raw.GroupName),
+		Tenant:           firstNonEmpty(raw.Tenant, raw.NamespaceId),
+		AppName:          raw.AppName,
+		Content:          raw.Content,
+		Md5:              raw.Md5,
+		Type:             raw.Type,
+		EncryptedDataKey: raw.EncryptedDataKey,
+		SrcIp:            raw.SrcIp,
+		SrcUser:          raw.SrcUser,
+		OpType:           strings.TrimSpace(raw.OpType),
+		PublishType:      raw.PublishType,
+	}
+	var err error
+	if item.CreatedTime, err = parseHistoryTime(raw.CreatedTime, raw.CreateTime); err != nil {
+		return err
+	}
+	item.LastModifiedTime, err = parseHistoryTime(raw.LastModifiedTime, raw.ModifyTime)
+	return err
+}
+
+// parseHistoryTime takes the first present value, either epoch millis or a date string.
+func parseHistoryTime(values ...json.RawMessage) (time.Time, error) {
+	for _, value := range values {
+		if len(value) == 0 || string(value) == "null" {
+			continue
+		}
+		if millis, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+			return time.UnixMilli(millis), nil
+		}
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			return time.Time{}, err
+		}
+		if millis, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return time.UnixMilli(millis), nil
+		}
+		for _, layout := range []string{"2006-01-02T15:04:05.000-07:00", time.RFC3339Nano, "2006-01-02 15:04:05"} {
+			if t, err := time.Parse(layout, text); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, errors.Errorf("unknown time format: %s", text)
+	}
+	return time.Time{}, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if len(value) > 0 {
+			return value
+		}
+	}
+	return ""
+}
+
+type ConfigHistoryPage struct {
+	TotalCount     int                 `json:"totalCount"`
+	PageNumber     int                 `json:"pageNumber"`
+	PagesAvailable int                 `json:"pagesAvailable"`
+	PageItems      []ConfigHistoryItem `json:"pageItems"`
+}
+
+type ConfigHistoryPageResult struct {
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Data    ConfigHistoryPage `json:"data"`
+}
+
+type ConfigHistoryResult struct {
+	Code    int                `json:"code"`
+	Message string             `json:"message"`
+	Data    *ConfigHistoryItem `json:"data"`
+}
+
+// ConfigSource tells where the content of a config was read from.
+type ConfigSource string
+
+const (
+	ConfigSourceServer   ConfigSource = "server"
+	ConfigSourceFailover ConfigSource = "failover"
+	ConfigSourceSnapshot ConfigSource = "snapshot"
+)
+
+// ConfigDetail is a config together with its metadata, see GetConfigDetail. ContentType, LastModified, Tag and
+// IsBeta are only known when Source is ConfigSourceServer, Md5 is computed locally otherwise.
+type ConfigDetail struct {
+	DataId       string
+	Group        string
+	Namespace    string
+	Content      string
+	ContentType  string
+	Md5          string
+	LastModified int64 // milliseconds since the epoch
+	Tag          string
+	IsBeta       bool
+	Encrypted    bool // whether the content was stored with an encrypted data key
+	Source       ConfigSource
+}
+
+// IsLocal reports whether the content came from a local file rather than the server, it may be out of date then.
+func (detail *ConfigDetail) IsLocal() bool {
+	return detail.Source != ConfigSourceServer
+}
+
+// ConfigQueryResult is the outcome for one config of a batch query, Err is nil if Content was read.
+type ConfigQueryResult struct {
+	DataId  string
+	Group   string
+	Content string
+	Err     error
+}
+
+// ConfigKeyRotationStatus is the outcome of the key rotation of one config.
+type ConfigKeyRotationStatus string
+
+const (
+	ConfigKeyRotated ConfigKeyRotationStatus = "rotated"
+	ConfigKeySkipped ConfigKeyRotationStatus = "skipped"
+	ConfigKeyFailed  ConfigKeyRotationStatus = "failed"
+)
+
+type ConfigKeyRotationItem struct {
+	DataId  string                  `json:"dataId"`
+	Group   string                  `json:"group"`
+	Status  ConfigKeyRotationStatus `json:"status"`
+	Message string                  `json:"message,omitempty"`
+	Time    time.Time               `json:"time"`
+}
+
+// ConfigKeyRotationReport is the progress of a key rotation, see RotateConfigKey. It is written to the report file
+// after each config, so that an interrupted rotation is resumed from it.
+type ConfigKeyRotationReport struct {
+	Namespace string                  `json:"namespace"`
+	NewKeyId  string                  `json:"newKeyId"`
+	StartTime time.Time               `json:"startTime"`
+	EndTime   time.Time               `json:"endTime,omitempty"`
+	Items     []ConfigKeyRotationItem `json:"items"`
+}
+
+// Count returns the number of configs having status.
+func (report *ConfigKeyRotationReport) Count(status ConfigKeyRotationStatus) int {
+	count := 0
+	for _, item := range report.Items {
+		if item.Status == status {
+			count++
+		}
+	}
+	return count
+}
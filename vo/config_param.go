@@ -16,8 +16,13 @@
 
 package vo
 
+import "github.com/nacos-group/nacos-sdk-go/v2/model"
+
 type Listener func(namespace, group, dataId, data string)
 
+// ConfigChangeListener receives the old and new content of a changed config together with a per-key diff.
+type ConfigChangeListener func(event *model.ConfigChangeEvent)
+
 type ConfigParam struct {
 	DataId           string    `param:"dataId"`  //required
 	Group            string    `param:"group"`   //required
@@ -25,6 +30,7 @@ type ConfigParam struct {
 	Tag              string    `param:"tag"`
 	ConfigTags       string    `param:"configTags"`
 	AppName          string    `param:"appName"`
+	Desc             string    `param:"desc"`
 	BetaIps          string    `param:"betaIps"`
 	CasMd5           string    `param:"casMd5"`
 	Type             string    `param:"type"`
@@ -32,7 +38,25 @@ type ConfigParam struct {
 	EncryptedDataKey string    `param:"encryptedDataKey"`
 	KmsKeyId         string    `param:"kmsKeyId"`
 	UsageType        UsageType `param:"usageType"`
+	GrayName         string    `param:"grayName"`
+	GrayRuleExp      string    `param:"grayRuleExp"`
+	GrayVersion      string    `param:"grayVersion"`
+	GrayPriority     int       `param:"grayPriority"`
 	OnChange         func(namespace, group, dataId, data string)
+	OnChangeEvent    ConfigChangeListener
+	// NotifyInitial makes ListenConfig call the listener with the current value before it returns,
+	// it fails if the value can be read from neither the server nor the local snapshot
+	NotifyInitial bool
+	// ListenerId names the listener added by ListenConfig, CancelListenConfig with the same ListenerId removes it
+	// and leaves the other listeners of the config alone. It must be unique among the listeners of the config
+	ListenerId string
+}
+
+// FuzzyListenConfigParam watches every config whose dataId and group match the glob patterns, e.g. tenant-*.yaml.
+type FuzzyListenConfigParam struct {
+	DataIdPattern string //required
+	GroupPattern  string //required
+	OnChangeEvent ConfigChangeListener
 }
 
 func (this *ConfigParam) DeepCopy() *ConfigParam {
@@ -44,6 +68,15 @@ func (this *ConfigParam) DeepCopy() *ConfigParam {
 	return result
 }
 
+// RotateConfigKeyParam selects the encrypted configs of the namespace of the client to encrypt again with NewKeyId.
+type RotateConfigKeyParam struct {
+	NewKeyId   string                                 //required, the master key the configs are encrypted with
+	DataId     string                                 //optional, the dataIds to rotate, "*" is a wildcard, default value is cipher-*
+	Group      string                                 //optional, only the configs of the group
+	ReportFile string                                 //optional, the report is written there after each config and the rotation resumed from it
+	OnProgress func(item model.ConfigKeyRotationItem) //optional, called after each config
+}
+
 type UsageType string
 
 const (
@@ -51,12 +84,22 @@ const (
 	ResponseType UsageType = "ResponseType"
 )
 
+// PageParam selects a page of a listing, PageNo starts from 1.
+type PageParam struct {
+	PageNo   int `param:"pageNo"`
+	PageSize int `param:"pageSize"`
+}
+
 type SearchConfigParam struct {
-	Search   string `param:"search"`
-	DataId   string `param:"dataId"`
-	Group    string `param:"group"`
-	Tag      string `param:"tag"`
-	AppName  string `param:"appName"`
-	PageNo   int    `param:"pageNo"`
-	PageSize int    `param:"pageSize"`
+	Search     string   `param:"search"`
+	DataId     string   `param:"dataId"`
+	Group      string   `param:"group"`
+	Tag        string   `param:"tag"`
+	AppName    string   `param:"appName"`
+	ConfigTags []string `param:"config_tags"`   //optional, configs having any of the tags
+	Types      []string `param:"types"`         //optional, configs of any of the types, e.g. yaml, json
+	Content    string   `param:"config_detail"` //optional, configs whose content contains it, "*" is a wildcard
+	PageNo     int      `param:"pageNo"`
+	PageSize   int      `param:"pageSize"`
+	MaxItems   int      `param:"-"` //optional, the most configs SearchConfigAll yields, 0 for all
 }
@@ -70,6 +70,7 @@ type GetAllServiceInfoParam struct {
 	GroupName string `param:"groupName"` //optional,default:DEFAULT_GROUP
 	PageNo    uint32 `param:"pageNo"`    //optional,default:1
 	PageSize  uint32 `param:"pageSize"`  //optional,default:10
+	MaxItems  uint32 `param:"-"`         //optional, the most services ListAllServices yields, 0 for all
 }
 
 type SubscribeParam struct {